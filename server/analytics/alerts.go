@@ -0,0 +1,141 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"sort"
+	"time"
+)
+
+// defaultAlertThresholds fire a warning at 80% of a category budget and a
+// breach at 100%
+var defaultAlertThresholds = []float64{0.8, 1.0}
+
+// AlertSink receives alerts raised by the service, e.g. to push notifications
+type AlertSink interface {
+	Send(ctx context.Context, alert types.Alert) error
+}
+
+// WithAlertSink delivers every alert raised by CheckCategoryAlerts to sink
+func WithAlertSink(sink AlertSink) Option {
+	return func(s *service) {
+		s.alertSink = sink
+	}
+}
+
+// WithAlertThresholds sets the fractions of a budget at which alerts fire.
+// Thresholds below 1.0 raise warnings, 1.0 and above raise breaches.
+func WithAlertThresholds(thresholds ...float64) Option {
+	return func(s *service) {
+		if len(thresholds) == 0 {
+			return
+		}
+		s.alertThresholds = append([]float64(nil), thresholds...)
+		sort.Float64s(s.alertThresholds)
+	}
+}
+
+// CheckCategoryAlerts compares this month's spend in each budgeted category
+// against the configured thresholds and returns one alert per category for the
+// highest threshold crossed. With no budgets given, the account's saved
+// budgets are used.
+func (s *service) CheckCategoryAlerts(ctx context.Context, accountID string, budgets map[string]float64) ([]types.Alert, error) {
+	budgets, spent, err := s.budgetSpend(ctx, accountID, budgets)
+	if err != nil {
+		return nil, err
+	}
+	config, err := s.accountConfig(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	currency := s.configCurrency(config)
+
+	alerts := make([]types.Alert, 0)
+	for category, budget := range budgets {
+		if budget <= 0 {
+			continue
+		}
+
+		ratio := spent[category] / budget
+		crossed := s.crossedThreshold(ratio)
+		if crossed < 0 {
+			continue
+		}
+
+		level := types.AlertLevelWarning
+		if crossed >= 1 {
+			level = types.AlertLevelBreach
+		}
+		alerts = append(alerts, types.Alert{
+			AccountID: accountID,
+			Category:  category,
+			Level:     level,
+			Threshold: crossed,
+			Spent:     spent[category],
+			Budget:    budget,
+			Message: fmt.Sprintf("%s spending is at %.0f%% of its %s budget",
+				category, ratio*100, FormatAmount(budget, currency)),
+		})
+	}
+
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].Category < alerts[j].Category
+	})
+
+	if s.alertSink != nil {
+		for _, alert := range alerts {
+			if err := s.alertSink.Send(ctx, alert); err != nil {
+				return nil, fmt.Errorf("failed to send alert for %s: %w", alert.Category, err)
+			}
+		}
+	}
+
+	return alerts, nil
+}
+
+// budgetSpend returns the budgets to check, the account's saved ones if none
+// are given, keyed by canonical category, along with this month's spend so
+// far in each category
+func (s *service) budgetSpend(ctx context.Context, accountID string, budgets map[string]float64) (map[string]float64, map[string]float64, error) {
+	if budgets == nil {
+		config, err := s.accountConfig(ctx, accountID)
+		if err != nil {
+			return nil, nil, err
+		}
+		budgets = config.Budgets
+	}
+
+	transactions, err := s.loadTransactions(ctx, accountID, "1 month")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+	return s.canonicalBudgets(budgets), monthToDateSpend(transactions, s.now()), nil
+}
+
+// crossedThreshold is the highest alert threshold ratio, a share of a budget,
+// has reached, or -1 if it has reached none
+func (s *service) crossedThreshold(ratio float64) float64 {
+	crossed := -1.0
+	for _, threshold := range s.alertThresholds {
+		if ratio >= threshold {
+			crossed = threshold
+		}
+	}
+	return crossed
+}
+
+// monthToDateSpend totals expenses per category from the start of now's month
+// up to now
+func monthToDateSpend(transactions []types.Transaction, now time.Time) map[string]float64 {
+	start := monthStart(now)
+	spent := make(map[string]float64)
+	for _, t := range transactions {
+		date := t.Date.In(now.Location())
+		if date.Before(start) || date.After(now) {
+			continue
+		}
+		spent[t.Category] += expenseAmount(t)
+	}
+	return spent
+}
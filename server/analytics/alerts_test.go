@@ -0,0 +1,82 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+)
+
+type recordingSink struct {
+	alerts []types.Alert
+}
+
+func (r *recordingSink) Send(ctx context.Context, alert types.Alert) error {
+	r.alerts = append(r.alerts, alert)
+	return nil
+}
+
+func TestCheckCategoryAlerts(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-04-02", -250, "Dining", "Chipotle"),
+		txn("2025-04-09", -170, "Dining", "Chipotle"),
+		txn("2025-04-03", -650, "Groceries", "Whole Foods"),
+		txn("2025-04-04", -20, "Books", "Strand"),
+		txn("2025-03-28", -500, "Books", "Strand"),
+	}}
+	sink := &recordingSink{}
+	svc := NewService(repo, fixedClock("2025-04-15"), WithAlertSink(sink))
+
+	budgets := map[string]float64{
+		"Dining":    500,
+		"Groceries": 600,
+		"Books":     100,
+	}
+	got, err := svc.CheckCategoryAlerts(context.Background(), "1234567891", budgets)
+	if err != nil {
+		t.Fatalf("CheckCategoryAlerts() failed: %v", err)
+	}
+
+	want := map[string]types.AlertLevel{
+		"Dining":    types.AlertLevelWarning,
+		"Groceries": types.AlertLevelBreach,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("CheckCategoryAlerts() returned %d alerts, want %d: %+v", len(got), len(want), got)
+	}
+	for _, alert := range got {
+		if alert.Level != want[alert.Category] {
+			t.Errorf("%s alert level = %q, want %q", alert.Category, alert.Level, want[alert.Category])
+		}
+	}
+	if got[0].Category != "Dining" || got[0].Threshold != 0.8 {
+		t.Errorf("Dining alert = %+v, want the 80%% threshold", got[0])
+	}
+	if len(sink.alerts) != len(got) {
+		t.Errorf("sink received %d alerts, want %d", len(sink.alerts), len(got))
+	}
+}
+
+func TestCheckCategoryAlertsMessageCurrency(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-04-02", -45000, "Dining", "Sushi Dai"),
+	}}
+	for _, tt := range []struct {
+		currency string
+		want     string
+	}{
+		{currency: "USD", want: "Dining spending is at 90% of its 50000.00 budget"},
+		{currency: "JPY", want: "Dining spending is at 90% of its 50000 budget"},
+	} {
+		t.Run(tt.currency, func(t *testing.T) {
+			svc := NewService(repo, fixedClock("2025-04-15"), WithCurrency(tt.currency))
+
+			got, err := svc.CheckCategoryAlerts(context.Background(), "1234567891", map[string]float64{"Dining": 50000})
+			if err != nil {
+				t.Fatalf("CheckCategoryAlerts() failed: %v", err)
+			}
+			if len(got) != 1 || got[0].Message != tt.want {
+				t.Errorf("CheckCategoryAlerts() = %+v, want the message %q", got, tt.want)
+			}
+		})
+	}
+}
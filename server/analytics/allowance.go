@@ -0,0 +1,62 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// allowanceMonths is how many complete months of income and spend the
+// allowance is based on
+const allowanceMonths = 3
+
+// ErrOvercommitted is returned when income doesn't cover essential spending
+// and the savings target, leaving nothing to spend
+var ErrOvercommitted = errors.New("income doesn't cover essentials and savings")
+
+// SuggestWeeklyAllowance turns the account's budget into one weekly number:
+// average monthly income, less spending outside the given discretionary
+// categories and the account's monthly savings target, spread over the weeks
+// of a month. When the account is overcommitted the shortfall is returned as
+// a negative allowance alongside ErrOvercommitted, so callers can show how
+// far over it is.
+func (s *service) SuggestWeeklyAllowance(ctx context.Context, accountID string, discretionaryCategories []string) (float64, error) {
+	config, err := s.accountConfig(ctx, accountID)
+	if err != nil {
+		return 0, err
+	}
+	discretionary := make(map[string]bool, len(discretionaryCategories))
+	for _, c := range discretionaryCategories {
+		discretionary[strings.ToLower(c)] = true
+	}
+
+	transactions, err := s.loadTransactions(ctx, accountID, fmt.Sprintf("%d months", allowanceMonths+1))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	end := monthStart(s.now())
+	start := end.AddDate(0, -allowanceMonths, 0)
+	var income float64
+	for _, t := range transactions {
+		if t.Amount > 0 && s.isIncome(t) && !t.Date.Before(start) && t.Date.Before(end) {
+			income += t.Amount
+		}
+	}
+	income /= allowanceMonths
+
+	var essentials float64
+	for category, series := range monthlySpendSeries(transactions, s.now(), allowanceMonths) {
+		if !discretionary[strings.ToLower(category)] {
+			essentials += mean(series)
+		}
+	}
+
+	weekly := (income - essentials - config.SavingsTarget) / weeksPerMonth
+	if weekly < 0 {
+		return weekly, fmt.Errorf("%.2f a month short after %.2f of essentials and %.2f of savings: %w",
+			-weekly*weeksPerMonth, essentials, config.SavingsTarget, ErrOvercommitted)
+	}
+	return weekly, nil
+}
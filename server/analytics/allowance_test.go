@@ -0,0 +1,49 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"server/types"
+	"testing"
+)
+
+func TestSuggestWeeklyAllowance(t *testing.T) {
+	var transactions []types.Transaction
+	for _, month := range []string{"2025-01", "2025-02", "2025-03"} {
+		transactions = append(transactions,
+			txn(month+"-01", 4000, "Income", "Acme Payroll"),
+			txn(month+"-02", -2000, "Rent", "Park Avenue Apartments"),
+			txn(month+"-10", -400, "Groceries", "Whole Foods"),
+			txn(month+"-15", -300, "Dining", "Chipotle"),
+		)
+	}
+
+	tests := []struct {
+		name    string
+		savings float64
+		want    float64
+		err     error
+	}{
+		// 4000 income - 2400 essentials - 500 savings = 1100 a month
+		{name: "room to spend", savings: 500, want: 1100 * 12 / 52.0},
+		// 4000 - 2400 - 2000 = 400 a month short
+		{name: "overcommitted", savings: 2000, want: -400 * 12 / 52.0, err: ErrOvercommitted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryConfigStore()
+			if err := store.SaveConfig(context.Background(), types.AccountConfig{AccountID: "1234567891", SavingsTarget: tt.savings}); err != nil {
+				t.Fatalf("SaveConfig() failed: %v", err)
+			}
+			svc := NewService(&fakeRepo{transactions: transactions}, fixedClock("2025-04-10"), WithConfigStore(store))
+
+			got, err := svc.SuggestWeeklyAllowance(context.Background(), "1234567891", []string{"dining"})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("SuggestWeeklyAllowance() error = %v, want %v", err, tt.err)
+			}
+			if !approxEqual(got, tt.want) {
+				t.Errorf("SuggestWeeklyAllowance() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package analytics
+
+import "server/types"
+
+// expenseAmount returns the outflow of a transaction as a positive number, or 0
+// for credits. Amounts follow the bank feed convention: money leaving the
+// account is negative, deposits and refunds are positive.
+func expenseAmount(t types.Transaction) float64 {
+	if t.Amount < 0 {
+		return -t.Amount
+	}
+	return 0
+}
@@ -0,0 +1,71 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+)
+
+const (
+	annualizeSeasonal = "seasonal"
+	annualizeLinear   = "linear"
+)
+
+// AnnualizeSpend projects the spend observed over timeRange to a full year.
+// When the account has a full year of history before the window, it scales by
+// the share of that year's spend that fell in the same months, so a window
+// covering the holidays isn't simply multiplied out. Otherwise it scales
+// linearly and, for windows shorter than a year, flags the figure as low
+// confidence.
+func (s *service) AnnualizeSpend(ctx context.Context, accountID string, timeRange string) (*types.AnnualizedSpend, error) {
+	months := timeRangeToMonths(timeRange)
+	history, err := s.loadTransactions(ctx, accountID, fmt.Sprintf("%d months", int(months)+12))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction history: %w", err)
+	}
+
+	end := s.now()
+	start := end.AddDate(0, -int(months), 0)
+	// The reference year is the twelve months before the window, starting with
+	// the same season last year
+	refStart := start.AddDate(-1, 0, 0)
+	refSeasonEnd := end.AddDate(-1, 0, 0)
+
+	var observed, refYear, refSeason float64
+	first := end
+	for _, t := range history {
+		amount := expenseAmount(t)
+		if amount == 0 {
+			continue
+		}
+		if t.Date.Before(first) {
+			first = t.Date
+		}
+		switch {
+		case t.Date.After(end):
+		case !t.Date.Before(start):
+			observed += amount
+		case !t.Date.Before(refStart):
+			refYear += amount
+			if t.Date.Before(refSeasonEnd) {
+				refSeason += amount
+			}
+		}
+	}
+
+	result := &types.AnnualizedSpend{
+		Observed:       observed,
+		ObservedMonths: months,
+	}
+	coversYear := !first.After(refStart.AddDate(0, 1, 0))
+	if months < 12 && coversYear && refSeason > 0 {
+		result.Annual = observed * refYear / refSeason
+		result.Method = annualizeSeasonal
+	} else {
+		result.Annual = observed * 12 / months
+		result.Method = annualizeLinear
+		result.LowConfidence = months < 12
+	}
+
+	return result, nil
+}
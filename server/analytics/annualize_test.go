@@ -0,0 +1,54 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+)
+
+func TestAnnualizeSpend(t *testing.T) {
+	// January to March, spending 300 a month
+	window := []types.Transaction{
+		txn("2025-01-20", -300, "Food", "Whole Foods"),
+		txn("2025-02-20", -300, "Food", "Whole Foods"),
+		txn("2025-03-20", -300, "Food", "Whole Foods"),
+	}
+
+	t.Run("linear without history", func(t *testing.T) {
+		svc := NewService(&fakeRepo{transactions: window}, fixedClock("2025-03-31"))
+		got, err := svc.AnnualizeSpend(context.Background(), "1234567891", "3 months")
+		if err != nil {
+			t.Fatalf("AnnualizeSpend() failed: %v", err)
+		}
+		if !approxEqual(got.Observed, 900) || !approxEqual(got.Annual, 3600) {
+			t.Errorf("Observed, Annual = %v, %v, want 900, 3600", got.Observed, got.Annual)
+		}
+		if got.Method != annualizeLinear || !got.LowConfidence {
+			t.Errorf("Method, LowConfidence = %q, %v, want linear and low confidence", got.Method, got.LowConfidence)
+		}
+	})
+
+	t.Run("seasonal with a year of history", func(t *testing.T) {
+		// Last year January to March was a quiet quarter: 100 a month against
+		// 300 a month for the rest of the year, so 300 of 3000
+		history := append([]types.Transaction(nil), window...)
+		for _, date := range []string{"2024-01-05", "2024-02-05", "2024-03-05"} {
+			history = append(history, txn(date, -100, "Food", "Whole Foods"))
+		}
+		for _, date := range []string{"2024-04-05", "2024-05-05", "2024-06-05", "2024-07-05", "2024-08-05", "2024-09-05", "2024-10-05", "2024-11-05", "2024-12-05"} {
+			history = append(history, txn(date, -300, "Food", "Whole Foods"))
+		}
+
+		svc := NewService(&fakeRepo{transactions: history}, fixedClock("2025-03-31"))
+		got, err := svc.AnnualizeSpend(context.Background(), "1234567891", "3 months")
+		if err != nil {
+			t.Fatalf("AnnualizeSpend() failed: %v", err)
+		}
+		if !approxEqual(got.Observed, 900) || !approxEqual(got.Annual, 900*3000/300) {
+			t.Errorf("Observed, Annual = %v, %v, want 900, %v", got.Observed, got.Annual, 900*3000/300)
+		}
+		if got.Method != annualizeSeasonal || got.LowConfidence {
+			t.Errorf("Method, LowConfidence = %q, %v, want seasonal and confident", got.Method, got.LowConfidence)
+		}
+	})
+}
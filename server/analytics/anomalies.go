@@ -0,0 +1,193 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"sort"
+)
+
+const (
+	// minAnomalySamples is how many charges a category needs before any of
+	// them can be called anomalous
+	minAnomalySamples = 5
+	// zScoreCutoff flags amounts more than 3 standard deviations above the mean
+	zScoreCutoff = 3.0
+	// iqrFence flags amounts more than 1.5 interquartile ranges above the
+	// third quartile, Tukey's outer fence for outliers
+	iqrFence = 1.5
+	// madCutoff flags amounts whose modified z-score exceeds 3.5, the cutoff
+	// recommended by Iglewicz and Hoaglin
+	madCutoff = 3.5
+	// madScale makes the median absolute deviation comparable to a standard
+	// deviation for normally distributed data
+	madScale = 0.6745
+)
+
+// AnomalyOptions tunes anomaly detection. The zero value uses z-scores with
+// each method's usual cutoff, over a baseline of the time range itself.
+type AnomalyOptions struct {
+	// Method is how unusual amounts are recognised. Z-scores suit roughly
+	// symmetric spend; IQR and MAD are robust to the long right tail typical
+	// of financial data, where one large purchase inflates the mean and
+	// standard deviation enough to hide others.
+	Method types.AnomalyMethod
+	// Threshold overrides the method's cutoff score: standard deviations for
+	// z-scores, interquartile ranges for IQR, modified z-score for MAD. Zero
+	// keeps the method's default.
+	Threshold float64
+	// MinSamples is how many charges a category needs before any of them can
+	// be flagged, so sparse categories don't produce false positives. Zero
+	// means 5.
+	MinSamples int
+	// BaselineRange is the history each category's normal range is learned
+	// from, e.g. "1 year", when it should be longer than the time range being
+	// checked. Empty uses the time range itself.
+	BaselineRange string
+	// MinAmount suppresses anomalies smaller than this, however unusual, so a
+	// $4 charge among $2 ones isn't reported. Zero flags any amount.
+	MinAmount float64
+}
+
+// DetectAnomalies flags expenses that are unusually large for their category
+// within the time range, largest score first. Only the high side is flagged:
+// spending less than usual isn't a concern.
+func (s *service) DetectAnomalies(ctx context.Context, accountID string, timeRange string, opts AnomalyOptions) ([]types.Anomaly, error) {
+	method := opts.Method
+	if method == "" {
+		method = types.AnomalyZScore
+	}
+	var scorer func(amounts []float64) (score func(float64) float64, expected, cutoff float64)
+	switch method {
+	case types.AnomalyZScore:
+		scorer = zScorer
+	case types.AnomalyIQR:
+		scorer = iqrScorer
+	case types.AnomalyMAD:
+		scorer = madScorer
+	default:
+		return nil, fmt.Errorf("unknown anomaly method %q", method)
+	}
+
+	// With a longer baseline, only charges within the time range are flagged
+	window, err := s.dateRange(timeRange)
+	if err != nil {
+		return nil, err
+	}
+	baselineRange := timeRange
+	if opts.BaselineRange != "" {
+		baselineRange = opts.BaselineRange
+	}
+	transactions, err := s.loadTransactions(ctx, accountID, baselineRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+	transactions = s.netDaily(transactions, s.now().Location())
+	minSamples := opts.MinSamples
+	if minSamples <= 0 {
+		minSamples = minAnomalySamples
+	}
+
+	// Holiday spending is expected to be unusual, so it neither counts towards
+	// a category's baseline nor gets flagged
+	byCategory := make(map[string][]types.Transaction)
+	for _, t := range transactions {
+		if expenseAmount(t) > 0 && !s.isHoliday(t.Date) {
+			byCategory[t.Category] = append(byCategory[t.Category], t)
+		}
+	}
+
+	anomalies := make([]types.Anomaly, 0)
+	for category, txns := range byCategory {
+		if len(txns) < minSamples {
+			continue
+		}
+		amounts := make([]float64, len(txns))
+		for i, t := range txns {
+			amounts[i] = expenseAmount(t)
+		}
+		baseline := types.AnomalyBaseline{
+			Count:  len(amounts),
+			Mean:   mean(amounts),
+			StdDev: stddev(amounts),
+			Median: median(amounts),
+		}
+
+		score, expected, cutoff := scorer(amounts)
+		if opts.Threshold > 0 {
+			cutoff = opts.Threshold
+		}
+		for i, t := range txns {
+			if opts.BaselineRange != "" && !window.Contains(t.Date) {
+				continue
+			}
+			if amounts[i] < opts.MinAmount {
+				continue
+			}
+			if sc := score(amounts[i]); sc > cutoff {
+				var stdDevs float64
+				if baseline.StdDev > 0 {
+					stdDevs = (amounts[i] - baseline.Mean) / baseline.StdDev
+				}
+				anomalies = append(anomalies, types.Anomaly{
+					Transaction: t,
+					Category:    category,
+					Method:      method,
+					Score:       sc,
+					Expected:    expected,
+					Baseline:    baseline,
+					StdDevs:     stdDevs,
+				})
+			}
+		}
+	}
+
+	sort.Slice(anomalies, func(i, j int) bool {
+		if anomalies[i].Score == anomalies[j].Score {
+			return anomalies[i].Transaction.Date.Before(anomalies[j].Transaction.Date)
+		}
+		return anomalies[i].Score > anomalies[j].Score
+	})
+
+	return anomalies, nil
+}
+
+// zScorer scores amounts by standard deviations above the mean
+func zScorer(amounts []float64) (func(float64) float64, float64, float64) {
+	m, sd := mean(amounts), stddev(amounts)
+	return func(x float64) float64 {
+		if sd == 0 {
+			return 0
+		}
+		return (x - m) / sd
+	}, m, zScoreCutoff
+}
+
+// iqrScorer scores amounts by interquartile ranges above the third quartile
+func iqrScorer(amounts []float64) (func(float64) float64, float64, float64) {
+	q1, q3 := quantile(amounts, 0.25), quantile(amounts, 0.75)
+	iqr := q3 - q1
+	return func(x float64) float64 {
+		if iqr == 0 {
+			return 0
+		}
+		return (x - q3) / iqr
+	}, median(amounts), iqrFence
+}
+
+// madScorer scores amounts by modified z-score, using the median and median
+// absolute deviation in place of the mean and standard deviation
+func madScorer(amounts []float64) (func(float64) float64, float64, float64) {
+	med := median(amounts)
+	deviations := make([]float64, len(amounts))
+	for i, a := range amounts {
+		deviations[i] = abs(a - med)
+	}
+	mad := median(deviations)
+	return func(x float64) float64 {
+		if mad == 0 {
+			return 0
+		}
+		return madScale * (x - med) / mad
+	}, med, madCutoff
+}
@@ -0,0 +1,146 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"testing"
+)
+
+func TestDetectAnomalies(t *testing.T) {
+	// Right-skewed dining spend: mostly small meals, a long tail of pricier
+	// ones and one very large catered event
+	var transactions []types.Transaction
+	for i, amount := range []float64{10, 11, 12, 12, 13, 14, 15, 16, 18, 20, 22, 25, 48, 60, 400} {
+		transactions = append(transactions, txn(fmt.Sprintf("2025-03-%02d", i+1), -amount, "Dining", "Restaurant"))
+	}
+
+	for _, tt := range []struct {
+		method types.AnomalyMethod
+		want   []float64
+	}{
+		{method: "", want: []float64{400}},
+		{method: types.AnomalyZScore, want: []float64{400}},
+		{method: types.AnomalyIQR, want: []float64{400, 60, 48}},
+		{method: types.AnomalyMAD, want: []float64{400, 60, 48}},
+	} {
+		t.Run(string(tt.method), func(t *testing.T) {
+			svc := NewService(&fakeRepo{transactions: transactions})
+			got, err := svc.DetectAnomalies(context.Background(), "1234567891", "1 month", AnomalyOptions{Method: tt.method})
+			if err != nil {
+				t.Fatalf("DetectAnomalies() failed: %v", err)
+			}
+			var flagged []float64
+			for _, a := range got {
+				flagged = append(flagged, expenseAmount(a.Transaction))
+			}
+			if fmt.Sprint(flagged) != fmt.Sprint(tt.want) {
+				t.Errorf("DetectAnomalies() flagged %v, want %v", flagged, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectAnomaliesUnknownMethod(t *testing.T) {
+	svc := NewService(&fakeRepo{})
+	if _, err := svc.DetectAnomalies(context.Background(), "1234567891", "1 month", AnomalyOptions{Method: "bogus"}); err == nil {
+		t.Error("DetectAnomalies() with an unknown method succeeded, want an error")
+	}
+}
+
+func TestDetectAnomaliesHolidayCalendar(t *testing.T) {
+	var transactions []types.Transaction
+	for i, amount := range []float64{40, 45, 50, 55, 60, 42, 48, 52, 58, 44, 46, 54, 50, 47} {
+		transactions = append(transactions, txn(fmt.Sprintf("2024-11-%02d", i+1), -amount, "Shopping", "Target"))
+	}
+	blackFriday := txn("2024-11-29", -900, "Shopping", "Best Buy")
+	transactions = append(transactions, blackFriday)
+
+	svc := NewService(&fakeRepo{transactions: transactions})
+	got, err := svc.DetectAnomalies(context.Background(), "1234567891", "1 month", AnomalyOptions{})
+	if err != nil {
+		t.Fatalf("DetectAnomalies() failed: %v", err)
+	}
+	if len(got) != 1 || got[0].Transaction.Merchant != "Best Buy" {
+		t.Fatalf("without a calendar DetectAnomalies() = %+v, want the Black Friday purchase", got)
+	}
+
+	svc = NewService(&fakeRepo{transactions: transactions}, WithHolidayCalendar(NewHolidaySet(blackFriday.Date)))
+	got, err = svc.DetectAnomalies(context.Background(), "1234567891", "1 month", AnomalyOptions{})
+	if err != nil {
+		t.Fatalf("DetectAnomalies() failed: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("with a calendar DetectAnomalies() = %+v, want nothing flagged", got)
+	}
+}
+
+func TestDetectAnomaliesOptions(t *testing.T) {
+	var transactions []types.Transaction
+	// A year of small coffee purchases, then one $2,000 charge this month
+	for i, amount := range []float64{4.5, 5, 5.25, 4.75, 6, 5.5, 4.25, 5, 5.75, 6.5, 4.5, 5} {
+		transactions = append(transactions, txn(fmt.Sprintf("2024-%02d-15", i+1), -amount, "Coffee", "Blue Bottle"))
+	}
+	transactions = append(transactions,
+		txn("2025-01-10", -2000, "Coffee", "Blue Bottle"),
+		// Only three gifts, one much larger than the others
+		txn("2024-06-01", -20, "Gifts", "Etsy"),
+		txn("2024-09-01", -25, "Gifts", "Etsy"),
+		txn("2025-01-05", -500, "Gifts", "Etsy"),
+	)
+	svc := NewService(&fakeRepo{transactions: transactions}, fixedClock("2025-01-20"))
+
+	got, err := svc.DetectAnomalies(context.Background(), "1234567891", "1 month", AnomalyOptions{BaselineRange: "1 year", Threshold: 2})
+	if err != nil {
+		t.Fatalf("DetectAnomalies() failed: %v", err)
+	}
+	if len(got) != 1 || got[0].Category != "Coffee" || expenseAmount(got[0].Transaction) != 2000 {
+		t.Fatalf("DetectAnomalies() = %+v, want only the $2,000 coffee charge", got)
+	}
+	a := got[0]
+	if a.Baseline.Count != 13 || a.Baseline.Median != 5 {
+		t.Errorf("Baseline = %+v, want 13 charges with a median of 5", a.Baseline)
+	}
+	if want := (2000 - a.Baseline.Mean) / a.Baseline.StdDev; !approxEqual(a.StdDevs, want) || a.StdDevs < 2 {
+		t.Errorf("StdDevs = %v, want %v", a.StdDevs, want)
+	}
+
+	// The sparse Gifts category is only considered once the minimum allows it
+	got, err = svc.DetectAnomalies(context.Background(), "1234567891", "1 month", AnomalyOptions{BaselineRange: "1 year", Threshold: 1, MinSamples: 3})
+	if err != nil {
+		t.Fatalf("DetectAnomalies() failed: %v", err)
+	}
+	categories := make(map[string]bool)
+	for _, a := range got {
+		categories[a.Category] = true
+	}
+	if !categories["Coffee"] || !categories["Gifts"] {
+		t.Errorf("DetectAnomalies() = %+v, want Coffee and Gifts flagged with MinSamples 3", got)
+	}
+}
+
+func TestDetectAnomaliesMinAmount(t *testing.T) {
+	// A $4 coffee stands far out from a run of $2 ones but isn't worth flagging
+	var transactions []types.Transaction
+	for i := 0; i < 12; i++ {
+		transactions = append(transactions, txn(fmt.Sprintf("2025-03-%02d", i+1), -2, "Coffee", "Corner Cafe"))
+	}
+	transactions = append(transactions, txn("2025-03-20", -4, "Coffee", "Corner Cafe"))
+	svc := NewService(&fakeRepo{transactions: transactions})
+
+	got, err := svc.DetectAnomalies(context.Background(), "1234567891", "1 month", AnomalyOptions{})
+	if err != nil {
+		t.Fatalf("DetectAnomalies() failed: %v", err)
+	}
+	if len(got) != 1 || got[0].Score <= zScoreCutoff {
+		t.Fatalf("without a floor DetectAnomalies() = %+v, want the $4 coffee flagged", got)
+	}
+
+	got, err = svc.DetectAnomalies(context.Background(), "1234567891", "1 month", AnomalyOptions{MinAmount: 20})
+	if err != nil {
+		t.Fatalf("DetectAnomalies() failed: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("with a $20 floor DetectAnomalies() = %+v, want nothing flagged", got)
+	}
+}
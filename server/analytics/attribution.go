@@ -0,0 +1,62 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"sort"
+)
+
+// GetCategoryAttribution splits each category's spend in the range into what
+// went on recurring charges, such as subscriptions and bills, and what was
+// discretionary, e.g. Entertainment: 60 = Netflix 15 recurring + 45
+// discretionary. Largest category first.
+func (s *service) GetCategoryAttribution(ctx context.Context, accountID string, timeRange string) ([]types.CategoryAttribution, error) {
+	transactions, err := s.loadTransactions(ctx, accountID, timeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	// Recurring detection needs a longer history than the window itself
+	history, err := s.loadTransactions(ctx, accountID, "1 year")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction history: %w", err)
+	}
+	isRecurring := recurringSet(s.detectRecurring(history))
+
+	byCategory := make(map[string]*types.CategoryAttribution)
+	for _, t := range transactions {
+		amount := expenseAmount(t)
+		if amount == 0 {
+			continue
+		}
+		a, ok := byCategory[t.Category]
+		if !ok {
+			a = &types.CategoryAttribution{
+				Category:           t.Category,
+				RecurringMerchants: make(map[string]float64),
+			}
+			byCategory[t.Category] = a
+		}
+		a.Total += amount
+		if isRecurring[keyOf(t)] {
+			a.Recurring += amount
+			a.RecurringMerchants[t.Merchant] += amount
+		} else {
+			a.Discretionary += amount
+		}
+	}
+
+	result := make([]types.CategoryAttribution, 0, len(byCategory))
+	for _, a := range byCategory {
+		result = append(result, *a)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Total == result[j].Total {
+			return result[i].Category < result[j].Category
+		}
+		return result[i].Total > result[j].Total
+	})
+
+	return result, nil
+}
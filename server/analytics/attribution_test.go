@@ -0,0 +1,45 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+)
+
+func TestGetCategoryAttribution(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-01-05", -15, "Entertainment", "Netflix"),
+		txn("2025-02-05", -15, "Entertainment", "Netflix"),
+		txn("2025-03-05", -15, "Entertainment", "Netflix"),
+		txn("2025-03-08", -25, "Entertainment", "AMC Theatres"),
+		txn("2025-03-21", -20, "Entertainment", "Steam"),
+		txn("2025-03-12", -84.20, "Food", "Whole Foods"),
+	}}
+	svc := NewService(repo, fixedClock("2025-03-25"))
+
+	got, err := svc.GetCategoryAttribution(context.Background(), "1234567891", "3 months")
+	if err != nil {
+		t.Fatalf("GetCategoryAttribution() failed: %v", err)
+	}
+
+	var entertainment *types.CategoryAttribution
+	for i := range got {
+		if got[i].Category == "Entertainment" {
+			entertainment = &got[i]
+		}
+	}
+	if entertainment == nil {
+		t.Fatalf("GetCategoryAttribution() = %+v, want an Entertainment row", got)
+	}
+
+	if !approxEqual(entertainment.Total, 90) || !approxEqual(entertainment.Recurring, 45) || !approxEqual(entertainment.Discretionary, 45) {
+		t.Errorf("Entertainment total, recurring, discretionary = %v, %v, %v, want 90, 45, 45",
+			entertainment.Total, entertainment.Recurring, entertainment.Discretionary)
+	}
+	if len(entertainment.RecurringMerchants) != 1 || !approxEqual(entertainment.RecurringMerchants["Netflix"], 45) {
+		t.Errorf("RecurringMerchants = %v, want only Netflix at 45", entertainment.RecurringMerchants)
+	}
+	if !approxEqual(entertainment.Recurring+entertainment.Discretionary, entertainment.Total) {
+		t.Errorf("recurring + discretionary = %v, want Total %v", entertainment.Recurring+entertainment.Discretionary, entertainment.Total)
+	}
+}
@@ -0,0 +1,53 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"sort"
+)
+
+// CheckBudgets compares this month's spend in each budgeted category with its
+// budget, along with the spend projected for the whole month at the current
+// pace. A category is over budget once the projection, which is never below
+// the spend so far, exceeds the budget, and near it once the projection
+// reaches any of the thresholds CheckCategoryAlerts uses. With no budgets
+// given, the account's saved budgets are used. Categories are returned in name
+// order.
+func (s *service) CheckBudgets(ctx context.Context, accountID string, budgets map[string]float64) ([]types.BudgetStatus, error) {
+	budgets, spent, err := s.budgetSpend(ctx, accountID, budgets)
+	if err != nil {
+		return nil, err
+	}
+
+	// Today counts as elapsed, so the pace is spend per day so far
+	now := s.now()
+	daysInMonth := monthStart(now).AddDate(0, 1, -1).Day()
+	pace := float64(daysInMonth) / float64(now.Day())
+
+	statuses := make([]types.BudgetStatus, 0, len(budgets))
+	for category, budget := range budgets {
+		if budget <= 0 {
+			continue
+		}
+		status := types.BudgetStatus{
+			Category:  category,
+			Spent:     spent[category],
+			Budgeted:  budget,
+			Projected: spent[category] * pace,
+			Status:    types.BudgetUnder,
+		}
+		switch {
+		case status.Projected > budget:
+			status.Status = types.BudgetOver
+		case s.crossedThreshold(status.Projected/budget) >= 0:
+			status.Status = types.BudgetNear
+		}
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Category < statuses[j].Category
+	})
+
+	return statuses, nil
+}
@@ -0,0 +1,114 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+)
+
+func TestCheckBudgets(t *testing.T) {
+	repo := &windowRepo{fakeRepo{transactions: []types.Transaction{
+		// 90% of the Food budget gone by the middle of the month
+		txn("2025-04-03", -200, "Food", "Whole Foods"),
+		txn("2025-04-12", -160, "Food", "Whole Foods"),
+		// On pace to land just under the Fuel budget
+		txn("2025-04-10", -70, "Fuel", "Shell"),
+		txn("2025-04-02", -20, "Dining", "Chipotle"),
+		// Last month's spend doesn't count
+		txn("2025-03-28", -500, "Dining", "Steakhouse"),
+	}}}
+	svc := NewService(repo, fixedClock("2025-04-15"))
+
+	got, err := svc.CheckBudgets(context.Background(), "1234567891", map[string]float64{
+		"Food":   400,
+		"Fuel":   150,
+		"Dining": 200,
+		"Gifts":  0,
+	})
+	if err != nil {
+		t.Fatalf("CheckBudgets() failed: %v", err)
+	}
+
+	want := []types.BudgetStatus{
+		{Category: "Dining", Spent: 20, Budgeted: 200, Projected: 40, Status: types.BudgetUnder},
+		{Category: "Food", Spent: 360, Budgeted: 400, Projected: 720, Status: types.BudgetOver},
+		{Category: "Fuel", Spent: 70, Budgeted: 150, Projected: 140, Status: types.BudgetNear},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("CheckBudgets() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].Category != want[i].Category || got[i].Status != want[i].Status || !approxEqual(got[i].Spent, want[i].Spent) ||
+			!approxEqual(got[i].Budgeted, want[i].Budgeted) || !approxEqual(got[i].Projected, want[i].Projected) {
+			t.Errorf("status %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestCheckBudgetsSharesAlertSettings(t *testing.T) {
+	store := NewMemoryConfigStore()
+	if err := store.SaveConfig(context.Background(), types.AccountConfig{
+		AccountID: "1234567891",
+		Budgets:   map[string]float64{"Food": 400},
+	}); err != nil {
+		t.Fatalf("SaveConfig() failed: %v", err)
+	}
+	// Projected to 340 of 400, 85%, by the end of the month
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-04-10", -170, "Food", "Whole Foods"),
+	}}
+
+	tests := []struct {
+		name string
+		opts []Option
+		want types.BudgetState
+	}{
+		{name: "default 80% warning", want: types.BudgetNear},
+		{name: "90% warning", opts: []Option{WithAlertThresholds(0.9, 1.0)}, want: types.BudgetUnder},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(repo, append([]Option{fixedClock("2025-04-15"), WithConfigStore(store)}, tt.opts...)...)
+
+			// No budgets given falls back to the saved ones
+			got, err := svc.CheckBudgets(context.Background(), "1234567891", nil)
+			if err != nil {
+				t.Fatalf("CheckBudgets() failed: %v", err)
+			}
+			if len(got) != 1 || got[0].Category != "Food" || got[0].Status != tt.want {
+				t.Errorf("CheckBudgets() = %+v, want Food %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckBudgetsComparesWithBudget(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount float64
+		opts   []Option
+		want   types.BudgetState
+	}{
+		// Well past the budget with no breach threshold configured
+		{name: "over without breach threshold", amount: -150, opts: []Option{WithAlertThresholds(0.8)}, want: types.BudgetOver},
+		// Projected to exactly the 100 budget
+		{name: "projected to budget", amount: -50, want: types.BudgetNear},
+		{name: "projected past budget", amount: -51, want: types.BudgetOver},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRepo{transactions: []types.Transaction{
+				txn("2025-04-10", tt.amount, "Food", "Whole Foods"),
+			}}
+			svc := NewService(repo, append([]Option{fixedClock("2025-04-15")}, tt.opts...)...)
+
+			got, err := svc.CheckBudgets(context.Background(), "1234567891", map[string]float64{"Food": 100})
+			if err != nil {
+				t.Fatalf("CheckBudgets() failed: %v", err)
+			}
+			if len(got) != 1 || got[0].Status != tt.want {
+				t.Errorf("CheckBudgets() = %+v, want Food %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"strconv"
+	"time"
+)
+
+// minBurnDays is how many days of a period must pass before its pace is
+// taken as a fair guide to the rest of it
+const minBurnDays = 3
+
+// currentPeriod returns the calendar period of a time range's length that
+// now falls in, starting at the beginning of the current day, week (Monday),
+// month or year, e.g. this calendar month for "1 month"
+func currentPeriod(timeRange string, now time.Time) (types.DateRange, error) {
+	match := timeRangePattern.FindStringSubmatch(timeRange)
+	if match == nil {
+		return types.DateRange{}, fmt.Errorf("%w: %q", ErrInvalidTimeRange, timeRange)
+	}
+	n, _ := strconv.Atoi(match[1])
+
+	if match[2] == "year" {
+		start := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location())
+		return types.DateRange{Start: start, End: start.AddDate(n, 0, 0)}, nil
+	}
+	start, err := intervalStart(now, match[2], now.Location())
+	if err != nil {
+		return types.DateRange{}, err
+	}
+	end := start
+	for i := 0; i < n; i++ {
+		end = nextInterval(end, match[2])
+	}
+	return types.DateRange{Start: start, End: end}, nil
+}
+
+// GetBurnRate reports the spend so far in the current period of the time
+// range's length, its daily average and the total it's on course for by the
+// period's end. Today counts as elapsed, so a period that has only just
+// started still has a pace, but it is flagged as Early.
+func (s *service) GetBurnRate(ctx context.Context, accountID string, timeRange string) (*types.BurnRate, error) {
+	now := s.now()
+	period, err := currentPeriod(timeRange, now)
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := s.repo.GetTransactions(ctx, accountID, types.DateRange{Start: period.Start, End: now})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	rate := &types.BurnRate{
+		Start:        period.Start,
+		End:          period.End,
+		DaysElapsed:  calendarDays(period.Start, now) + 1,
+		DaysInPeriod: calendarDays(period.Start, period.End),
+	}
+	for _, t := range transactions {
+		if t.Date.Before(period.Start) || t.Date.After(now) {
+			continue
+		}
+		rate.SpentSoFar += expenseAmount(t)
+	}
+	rate.AverageDailySpend = ratio(rate.SpentSoFar, float64(rate.DaysElapsed))
+	rate.ProjectedTotal = rate.AverageDailySpend * float64(rate.DaysInPeriod)
+	rate.Early = rate.DaysElapsed < minBurnDays
+
+	return rate, nil
+}
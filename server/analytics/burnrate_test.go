@@ -0,0 +1,55 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"server/types"
+	"testing"
+)
+
+func TestGetBurnRate(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-03-28", -500, "Rent", "Landlord"), // last period
+		txn("2025-04-01", -120, "Food", "Whole Foods"),
+		txn("2025-04-04", -80, "Gas", "Shell"),
+		txn("2025-04-09", -100, "Shopping", "Target"),
+		txn("2025-04-09", 40, "Shopping", "Target"),
+	}}
+	svc := NewService(repo, fixedClock("2025-04-10"))
+
+	got, err := svc.GetBurnRate(context.Background(), "1234567891", "1 month")
+	if err != nil {
+		t.Fatalf("GetBurnRate() failed: %v", err)
+	}
+	if got.DaysElapsed != 10 || got.DaysInPeriod != 30 {
+		t.Errorf("days = %d of %d, want 10 of 30", got.DaysElapsed, got.DaysInPeriod)
+	}
+	if !approxEqual(got.SpentSoFar, 300) || !approxEqual(got.AverageDailySpend, 30) {
+		t.Errorf("spent %v at %v a day, want 300 at 30", got.SpentSoFar, got.AverageDailySpend)
+	}
+	if !approxEqual(got.ProjectedTotal, 900) {
+		t.Errorf("ProjectedTotal = %v, want 900", got.ProjectedTotal)
+	}
+	if got.Early {
+		t.Error("Early = true, want false ten days in")
+	}
+}
+
+func TestGetBurnRateJustStarted(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-04-01", -45, "Food", "Whole Foods"),
+	}}
+	svc := NewService(repo, fixedClock("2025-04-01"))
+
+	got, err := svc.GetBurnRate(context.Background(), "1234567891", "1 month")
+	if err != nil {
+		t.Fatalf("GetBurnRate() failed: %v", err)
+	}
+	if got.DaysElapsed != 1 || !approxEqual(got.ProjectedTotal, 1350) || !got.Early {
+		t.Errorf("GetBurnRate() = %+v, want day 1 of 30 projecting 1350 and flagged early", got)
+	}
+
+	if _, err := svc.GetBurnRate(context.Background(), "1234567891", "forever"); !errors.Is(err, ErrInvalidTimeRange) {
+		t.Errorf("GetBurnRate() error = %v, want ErrInvalidTimeRange", err)
+	}
+}
@@ -0,0 +1,177 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"sync"
+	"time"
+)
+
+// cachingRepository serves repeated reads of the same account and window from
+// memory until they are ttl old. Errors aren't cached.
+type cachingRepository struct {
+	next Repository
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[cacheKey]cacheEntry
+	pending map[cacheKey]*pendingRead
+}
+
+// readKind is which repository read a cache entry holds
+type readKind int
+
+const (
+	readTransactions readKind = iota
+	readTotals
+	readBalance
+)
+
+// cacheKey identifies a read. Explicit windows are keyed by their exact
+// bounds. Relative ones are resolved against a moving "now" and would never
+// repeat, so they are keyed by the range and the UTC day it ends on instead;
+// the TTL already bounds how stale a read can be. Balance reads have no
+// window and leave the rest zero.
+type cacheKey struct {
+	kind       readKind
+	accountID  string
+	relative   string
+	start, end int64
+}
+
+// cacheEntry is one cached read, holding whichever result it is for
+type cacheEntry struct {
+	transactions []types.Transaction
+	totals       map[string]float64
+	balance      float64
+	fetchedAt    time.Time
+}
+
+// pendingRead is a read in flight, which concurrent callers of the same key
+// wait on instead of repeating
+type pendingRead struct {
+	done  chan struct{}
+	entry cacheEntry
+	err   error
+}
+
+// NewCachingRepository wraps repo so that identical reads within ttl, such
+// as the several loads of one dashboard request, reach it only once. It is
+// safe for concurrent use.
+func NewCachingRepository(repo Repository, ttl time.Duration) Repository {
+	return &cachingRepository{
+		next:    repo,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[cacheKey]cacheEntry),
+		pending: make(map[cacheKey]*pendingRead),
+	}
+}
+
+func keyFor(kind readKind, accountID string, window types.DateRange) cacheKey {
+	if window.Relative != "" {
+		return cacheKey{kind: kind, accountID: accountID, relative: window.Relative, end: window.End.Truncate(day).Unix()}
+	}
+	return cacheKey{kind: kind, accountID: accountID, start: window.Start.UnixNano(), end: window.End.UnixNano()}
+}
+
+// fresh reports whether an entry fetched at fetchedAt can still be served
+func (c *cachingRepository) fresh(fetchedAt time.Time) bool {
+	return c.now().Sub(fetchedAt) < c.ttl
+}
+
+// read serves key from the cache, from a read of it already in flight, or
+// else by calling fetch and caching what it returns
+func (c *cachingRepository) read(ctx context.Context, key cacheKey, fetch func() (cacheEntry, error)) (cacheEntry, error) {
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok && c.fresh(entry.fetchedAt) {
+		c.mu.Unlock()
+		return entry, nil
+	}
+	if call, ok := c.pending[key]; ok {
+		c.mu.Unlock()
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return cacheEntry{}, ctx.Err()
+		}
+		if call.err == nil {
+			return call.entry, nil
+		}
+		// The read we waited on may have failed for reasons of its own, such
+		// as its caller giving up, so try again under our context
+		return fetch()
+	}
+	call := &pendingRead{done: make(chan struct{})}
+	c.pending[key] = call
+	c.mu.Unlock()
+
+	call.entry, call.err = fetch()
+
+	c.mu.Lock()
+	delete(c.pending, key)
+	if call.err == nil {
+		c.evictExpired()
+		call.entry.fetchedAt = c.now()
+		c.entries[key] = call.entry
+	}
+	c.mu.Unlock()
+	close(call.done)
+	return call.entry, call.err
+}
+
+func (c *cachingRepository) GetTransactions(ctx context.Context, accountID string, window types.DateRange) ([]types.Transaction, error) {
+	entry, err := c.read(ctx, keyFor(readTransactions, accountID, window), func() (cacheEntry, error) {
+		transactions, err := c.next.GetTransactions(ctx, accountID, window)
+		return cacheEntry{transactions: transactions}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Callers own what they are given, so keep the cached copy to ourselves
+	return append([]types.Transaction(nil), entry.transactions...), nil
+}
+
+func (c *cachingRepository) GetCategoryTotals(ctx context.Context, accountID string, window types.DateRange) (map[string]float64, error) {
+	entry, err := c.read(ctx, keyFor(readTotals, accountID, window), func() (cacheEntry, error) {
+		totals, err := c.next.GetCategoryTotals(ctx, accountID, window)
+		return cacheEntry{totals: totals}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return copyTotals(entry.totals), nil
+}
+
+func (c *cachingRepository) GetBalance(ctx context.Context, accountID string) (float64, error) {
+	entry, err := c.read(ctx, cacheKey{kind: readBalance, accountID: accountID}, func() (cacheEntry, error) {
+		balance, err := c.next.GetBalance(ctx, accountID)
+		return cacheEntry{balance: balance}, err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return entry.balance, nil
+}
+
+// evictExpired drops stale entries so windows relative to a moving "now"
+// don't accumulate. Callers must hold c.mu.
+func (c *cachingRepository) evictExpired() {
+	for key, entry := range c.entries {
+		if !c.fresh(entry.fetchedAt) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+func copyTotals(totals map[string]float64) map[string]float64 {
+	if totals == nil {
+		return nil
+	}
+	copied := make(map[string]float64, len(totals))
+	for category, amount := range totals {
+		copied[category] = amount
+	}
+	return copied
+}
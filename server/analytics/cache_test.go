@@ -0,0 +1,154 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingRepo is a fakeRepo that counts the reads reaching it
+type countingRepo struct {
+	fakeRepo
+
+	mu               sync.Mutex
+	transactionCalls int
+	totalsCalls      int
+	balanceCalls     int
+}
+
+func (c *countingRepo) GetTransactions(ctx context.Context, accountID string, window types.DateRange) ([]types.Transaction, error) {
+	c.mu.Lock()
+	c.transactionCalls++
+	c.mu.Unlock()
+	return c.transactions, nil
+}
+
+func (c *countingRepo) GetCategoryTotals(ctx context.Context, accountID string, window types.DateRange) (map[string]float64, error) {
+	c.mu.Lock()
+	c.totalsCalls++
+	c.mu.Unlock()
+	return map[string]float64{"Food": 12.50}, nil
+}
+
+func (c *countingRepo) GetBalance(ctx context.Context, accountID string) (float64, error) {
+	c.mu.Lock()
+	c.balanceCalls++
+	c.mu.Unlock()
+	return 100, nil
+}
+
+func TestCachingRepository(t *testing.T) {
+	underlying := &countingRepo{fakeRepo: fakeRepo{transactions: []types.Transaction{
+		txn("2025-04-03", -12.50, "Food", "Chipotle"),
+	}}}
+	now := txn("2025-04-15", 0, "", "").Date
+	repo := NewCachingRepository(underlying, time.Minute)
+	repo.(*cachingRepository).now = func() time.Time { return now }
+
+	ctx := context.Background()
+	window := types.DateRange{Start: now.AddDate(0, -1, 0), End: now}
+	for i := 0; i < 2; i++ {
+		if _, err := repo.GetTransactions(ctx, "1234567891", window); err != nil {
+			t.Fatalf("GetTransactions() failed: %v", err)
+		}
+		if _, err := repo.GetCategoryTotals(ctx, "1234567891", window); err != nil {
+			t.Fatalf("GetCategoryTotals() failed: %v", err)
+		}
+		if _, err := repo.GetBalance(ctx, "1234567891"); err != nil {
+			t.Fatalf("GetBalance() failed: %v", err)
+		}
+	}
+	if underlying.transactionCalls != 1 || underlying.totalsCalls != 1 || underlying.balanceCalls != 1 {
+		t.Errorf("calls = %d, %d, %d, want each read made once", underlying.transactionCalls, underlying.totalsCalls, underlying.balanceCalls)
+	}
+
+	// A different window or account is a different read
+	if _, err := repo.GetTransactions(ctx, "1234567891", types.DateRange{Start: window.Start.AddDate(0, -1, 0), End: now}); err != nil {
+		t.Fatalf("GetTransactions() failed: %v", err)
+	}
+	if _, err := repo.GetTransactions(ctx, "9876543210", window); err != nil {
+		t.Fatalf("GetTransactions() failed: %v", err)
+	}
+	if underlying.transactionCalls != 3 {
+		t.Errorf("transaction calls = %d, want 3 after two new reads", underlying.transactionCalls)
+	}
+
+	// Callers get their own copies
+	totals, _ := repo.GetCategoryTotals(ctx, "1234567891", window)
+	totals["Food"] = 0
+	if totals, _ = repo.GetCategoryTotals(ctx, "1234567891", window); totals["Food"] != 12.50 {
+		t.Errorf("cached totals = %v, want them unaffected by a caller's change", totals)
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := repo.GetTransactions(ctx, "1234567891", window); err != nil {
+		t.Fatalf("GetTransactions() failed: %v", err)
+	}
+	if underlying.transactionCalls != 4 {
+		t.Errorf("transaction calls = %d, want the read repeated once the TTL expired", underlying.transactionCalls)
+	}
+}
+
+func TestCachingRepositoryExplicitWindows(t *testing.T) {
+	underlying := &countingRepo{}
+	repo := NewCachingRepository(underlying, time.Minute)
+	morning := types.DateRange{Start: time.Date(2025, 4, 15, 9, 0, 0, 0, time.UTC), End: time.Date(2025, 4, 15, 12, 0, 0, 0, time.UTC)}
+	evening := types.DateRange{Start: time.Date(2025, 4, 15, 17, 0, 0, 0, time.UTC), End: time.Date(2025, 4, 15, 20, 0, 0, 0, time.UTC)}
+
+	// Windows on the same day are still different reads when given exactly
+	for _, window := range []types.DateRange{morning, evening, morning} {
+		if _, err := repo.GetTransactions(context.Background(), "1234567891", window); err != nil {
+			t.Fatalf("GetTransactions() failed: %v", err)
+		}
+	}
+	if underlying.transactionCalls != 2 {
+		t.Errorf("transaction calls = %d, want one per distinct window", underlying.transactionCalls)
+	}
+}
+
+func TestCachingRepositoryConcurrent(t *testing.T) {
+	underlying := &countingRepo{}
+	repo := NewCachingRepository(underlying, time.Minute)
+	window := types.DateRange{Start: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := repo.GetCategoryTotals(context.Background(), "1234567891", window); err != nil {
+				t.Errorf("GetCategoryTotals() failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if _, err := repo.GetCategoryTotals(context.Background(), "1234567891", window); err != nil {
+		t.Fatalf("GetCategoryTotals() failed: %v", err)
+	}
+	if underlying.totalsCalls != 1 {
+		t.Errorf("totals calls = %d, want concurrent callers to share one read", underlying.totalsCalls)
+	}
+}
+
+func TestCachingRepositoryRealClock(t *testing.T) {
+	underlying := &countingRepo{fakeRepo: fakeRepo{transactions: []types.Transaction{
+		txn("2025-04-03", -12.50, "Food", "Chipotle"),
+	}}}
+	svc := NewService(NewCachingRepository(underlying, time.Minute))
+
+	// Relative ranges resolve against time.Now on each call, so these only
+	// share reads if the cache doesn't key on the exact instant
+	for i := 0; i < 2; i++ {
+		if _, err := svc.GetSpendingAnalytics(context.Background(), "1234567891", "1 month", AnalyticsOptions{}); err != nil {
+			t.Fatalf("GetSpendingAnalytics() failed: %v", err)
+		}
+		// The month, the prediction lookback and the health year are each
+		// read once, however many loads of them the pipeline makes
+		if underlying.transactionCalls != 3 || underlying.totalsCalls != 1 {
+			t.Errorf("after call %d: calls = %d, %d, want 3 transaction reads and 1 totals read", i+1, underlying.transactionCalls, underlying.totalsCalls)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package analytics
+
+import (
+	"server/types"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+// calendarDays counts the calendar days from one date to another, ignoring the
+// time of day. Working from dates rather than durations keeps DST changes from
+// shaving an hour off a day, and leap days count like any other.
+func calendarDays(from, to time.Time) int {
+	y1, m1, d1 := from.Date()
+	y2, m2, d2 := to.In(from.Location()).Date()
+	start := time.Date(y1, m1, d1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(y2, m2, d2, 0, 0, 0, 0, time.UTC)
+	return int(end.Sub(start) / day)
+}
+
+// cadenceBands maps the typical gap between charges onto a named cadence. The
+// bands are loose enough to absorb weekends, short months and leap years.
+var cadenceBands = []struct {
+	cadence  types.Cadence
+	min, max float64 // days between charges
+	perMonth float64 // occurrences in an average month
+}{
+	{types.CadenceDaily, 0.5, 1.5, 30.44},
+	{types.CadenceWeekly, 6, 8, 30.44 / 7},
+	{types.CadenceBiweekly, 13, 16, 30.44 / 14},
+	{types.CadenceMonthly, 27, 33, 1},
+	{types.CadenceQuarterly, 85, 95, 1.0 / 3},
+	{types.CadenceYearly, 355, 375, 1.0 / 12},
+}
+
+// classifyInterval names the cadence for an average gap between charges
+func classifyInterval(interval time.Duration) types.Cadence {
+	days := interval.Hours() / 24
+	for _, band := range cadenceBands {
+		if days >= band.min && days <= band.max {
+			return band.cadence
+		}
+	}
+	return types.CadenceIrregular
+}
+
+// inCadenceBand reports whether a single gap fits the given cadence
+func inCadenceBand(interval time.Duration, cadence types.Cadence) bool {
+	return classifyInterval(interval) == cadence
+}
+
+// cadenceOf classifies a series of gaps between charges by their average and
+// returns the share of individual gaps that fit that cadence. Series whose gaps
+// mostly don't fit are irregular even if the average happens to.
+func cadenceOf(intervals []time.Duration, minRegularity float64) (types.Cadence, float64) {
+	if len(intervals) == 0 {
+		return types.CadenceIrregular, 0
+	}
+
+	var total time.Duration
+	for _, interval := range intervals {
+		total += interval
+	}
+	cadence := classifyInterval(total / time.Duration(len(intervals)))
+	if cadence == types.CadenceIrregular {
+		return cadence, 0
+	}
+
+	inBand := 0
+	for _, interval := range intervals {
+		if inCadenceBand(interval, cadence) {
+			inBand++
+		}
+	}
+	regularity := float64(inBand) / float64(len(intervals))
+	if regularity < minRegularity {
+		return types.CadenceIrregular, regularity
+	}
+	return cadence, regularity
+}
+
+// monthlyFactor returns how many times a charge of the given cadence lands in
+// an average month, or 0 for irregular charges
+func monthlyFactor(cadence types.Cadence) float64 {
+	for _, band := range cadenceBands {
+		if band.cadence == cadence {
+			return band.perMonth
+		}
+	}
+	return 0
+}
@@ -0,0 +1,59 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"time"
+)
+
+// GetCurrentMonthCalendar lays out the current month's spend per day as a
+// calendar: one row per week, seven columns starting on the account's week
+// start. Days before the 1st and after the last day of the month are zero.
+func (s *service) GetCurrentMonthCalendar(ctx context.Context, accountID string) ([][]types.DaySpend, error) {
+	config, err := s.accountConfig(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := s.configLocation(config)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().In(loc)
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+	end := start.AddDate(0, 1, 0)
+	transactions, err := s.repo.GetTransactions(ctx, accountID, types.DateRange{Start: start, End: end.Add(-time.Nanosecond)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	daysInMonth := end.AddDate(0, 0, -1).Day()
+	offset := (int(start.Weekday()) - int(config.WeekStart) + 7) % 7
+	weeks := (offset + daysInMonth + 6) / 7
+
+	grid := make([][]types.DaySpend, weeks)
+	for w := range grid {
+		grid[w] = make([]types.DaySpend, 7)
+	}
+	cell := func(day int) *types.DaySpend {
+		i := offset + day - 1
+		return &grid[i/7][i%7]
+	}
+	for day := 1; day <= daysInMonth; day++ {
+		*cell(day) = types.DaySpend{Date: start.AddDate(0, 0, day-1), Day: day}
+	}
+
+	for _, t := range transactions {
+		amount := expenseAmount(t)
+		date := t.Date.In(loc)
+		if amount == 0 || date.Before(start) || !date.Before(end) {
+			continue
+		}
+		c := cell(date.Day())
+		c.Amount += amount
+		c.Count++
+	}
+
+	return grid, nil
+}
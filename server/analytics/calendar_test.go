@@ -0,0 +1,65 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+	"time"
+)
+
+func TestGetCurrentMonthCalendar(t *testing.T) {
+	// April 1st 2025 is a Tuesday
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-04-01", -12, "Food", "Blue Bottle"),
+		txn("2025-04-01", -8, "Food", "Blue Bottle"),
+		txn("2025-04-30", -40, "Fuel", "Shell"),
+		txn("2025-03-31", -99, "Food", "Whole Foods"),
+		txn("2025-04-10", 500, "Income", "Acme Payroll"),
+	}}
+
+	tests := []struct {
+		name      string
+		weekStart time.Weekday
+		column    int
+		weeks     int
+	}{
+		{name: "sunday start", weekStart: time.Sunday, column: 2, weeks: 5},
+		{name: "monday start", weekStart: time.Monday, column: 1, weeks: 5},
+		{name: "wednesday start", weekStart: time.Wednesday, column: 6, weeks: 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryConfigStore()
+			if err := store.SaveConfig(context.Background(), types.AccountConfig{AccountID: "1234567891", WeekStart: tt.weekStart}); err != nil {
+				t.Fatalf("SaveConfig() failed: %v", err)
+			}
+			svc := NewService(repo, fixedClock("2025-04-15"), WithConfigStore(store))
+
+			grid, err := svc.GetCurrentMonthCalendar(context.Background(), "1234567891")
+			if err != nil {
+				t.Fatalf("GetCurrentMonthCalendar() failed: %v", err)
+			}
+			if len(grid) != tt.weeks {
+				t.Fatalf("got %d weeks, want %d", len(grid), tt.weeks)
+			}
+			for col := 0; col < tt.column; col++ {
+				if grid[0][col] != (types.DaySpend{}) {
+					t.Errorf("grid[0][%d] = %+v, want empty before the 1st", col, grid[0][col])
+				}
+			}
+
+			first := grid[0][tt.column]
+			if first.Day != 1 || first.Date.Weekday() != time.Tuesday {
+				t.Fatalf("grid[0][%d] = %+v, want the 1st", tt.column, first)
+			}
+			if !approxEqual(first.Amount, 20) || first.Count != 2 {
+				t.Errorf("1st = %v over %d, want 20 over 2", first.Amount, first.Count)
+			}
+
+			last := (tt.column + 29) % 7
+			if got := grid[len(grid)-1][last]; got.Day != 30 || !approxEqual(got.Amount, 40) {
+				t.Errorf("grid[%d][%d] = %+v, want the 30th at 40", len(grid)-1, last, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"server/types"
+	"sort"
+	"time"
+)
+
+const (
+	// calibrationBins is how finely raw likelihoods are bucketed
+	calibrationBins = 10
+	// maxBacktestSamples caps how many historical cutoffs a backtest replays
+	maxBacktestSamples = 200
+)
+
+// Calibrator maps raw likelihood scores onto the hit rate observed for
+// similar scores in the past, so a reported 70% means spend happened in the
+// predicted window about 70% of the time
+type Calibrator struct {
+	hits   [calibrationBins]float64
+	totals [calibrationBins]float64
+}
+
+// NewCalibrator builds a calibrator from backtested prediction outcomes
+func NewCalibrator(samples []types.CalibrationSample) *Calibrator {
+	c := &Calibrator{}
+	for _, sample := range samples {
+		bin := calibrationBin(sample.Likelihood)
+		c.totals[bin]++
+		if sample.Hit {
+			c.hits[bin]++
+		}
+	}
+	return c
+}
+
+// Calibrate returns the observed hit rate for scores like raw. One pseudo
+// observation at the raw score keeps sparse bins from snapping to 0 or 1, and
+// bins with no history fall back to the raw score.
+func (c *Calibrator) Calibrate(raw float64) float64 {
+	bin := calibrationBin(raw)
+	return (c.hits[bin] + raw) / (c.totals[bin] + 1)
+}
+
+func calibrationBin(likelihood float64) int {
+	bin := int(likelihood * calibrationBins)
+	if bin < 0 {
+		return 0
+	}
+	if bin >= calibrationBins {
+		return calibrationBins - 1
+	}
+	return bin
+}
+
+// WithCalibration calibrates prediction likelihoods against historical
+// outcomes, typically produced by BacktestPredictions
+func WithCalibration(samples []types.CalibrationSample) Option {
+	return func(s *service) {
+		s.calibrator = NewCalibrator(samples)
+	}
+}
+
+// BacktestPredictions replays the predictor at past points in each category's
+// history and records whether spend actually landed within half an interval
+// of the predicted date. The outcomes feed WithCalibration.
+func (s *service) BacktestPredictions(ctx context.Context, accountID string) ([]types.CalibrationSample, error) {
+	transactions, err := s.loadTransactions(ctx, accountID, "1 year")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	byCategory := make(map[string][]types.Transaction)
+	for _, t := range transactions {
+		byCategory[t.Category] = append(byCategory[t.Category], t)
+	}
+	categories := make([]string, 0, len(byCategory))
+	for category := range byCategory {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	type cutoff struct {
+		category string
+		history  []types.Transaction
+		next     *types.Transaction
+	}
+	var cutoffs []cutoff
+	for _, category := range categories {
+		txns := byCategory[category]
+		sort.Slice(txns, func(i, j int) bool {
+			return txns[i].Date.Before(txns[j].Date)
+		})
+		for i := 3; i <= len(txns); i++ {
+			c := cutoff{category: category, history: txns[:i]}
+			if i < len(txns) {
+				c.next = &txns[i]
+			}
+			cutoffs = append(cutoffs, c)
+		}
+	}
+
+	if len(cutoffs) > maxBacktestSamples {
+		perm := rand.New(rand.NewSource(s.seed)).Perm(len(cutoffs))[:maxBacktestSamples]
+		sort.Ints(perm)
+		sampled := make([]cutoff, len(perm))
+		for i, idx := range perm {
+			sampled[i] = cutoffs[idx]
+		}
+		cutoffs = sampled
+	}
+
+	now := s.now()
+	samples := make([]types.CalibrationSample, 0, len(cutoffs))
+	for _, c := range cutoffs {
+		prediction := predictCategory(c.category, c.history, AverageMean, s.predictionConfig)
+		last := c.history[len(c.history)-1].Date
+		halfWindow := prediction.PredictedDate.Sub(last) / 2
+		if halfWindow < day {
+			halfWindow = day
+		}
+		windowEnd := prediction.PredictedDate.Add(halfWindow)
+
+		var hit bool
+		if c.next != nil {
+			hit = absDuration(c.next.Date.Sub(prediction.PredictedDate)) <= halfWindow
+		} else if now.Before(windowEnd) {
+			continue // the window hasn't closed yet, so there's no outcome
+		}
+		samples = append(samples, types.CalibrationSample{
+			Likelihood: prediction.Likelihood,
+			Hit:        hit,
+		})
+	}
+
+	return samples, nil
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
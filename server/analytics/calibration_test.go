@@ -0,0 +1,121 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"server/types"
+	"testing"
+)
+
+func TestCalibrator(t *testing.T) {
+	var history []types.CalibrationSample
+	// Scores around 0.8 only came true half the time
+	for i := 0; i < 19; i++ {
+		history = append(history, types.CalibrationSample{Likelihood: 0.82, Hit: i%2 == 0})
+	}
+	// Scores around 0.3 came true 9 times out of 9
+	for i := 0; i < 9; i++ {
+		history = append(history, types.CalibrationSample{Likelihood: 0.3, Hit: true})
+	}
+	c := NewCalibrator(history)
+
+	tests := []struct {
+		raw  float64
+		want float64
+	}{
+		{raw: 0.8, want: (10 + 0.8) / 20},
+		{raw: 0.3, want: (9 + 0.3) / 10},
+		{raw: 0.55, want: 0.55}, // no history in this bin
+	}
+	for _, tt := range tests {
+		if got := c.Calibrate(tt.raw); !approxEqual(got, tt.want) {
+			t.Errorf("Calibrate(%v) = %v, want %v", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestPredictFutureSpendingCalibrated(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-01-01", -2260, "Rent", "Park Avenue Apartments"),
+		txn("2025-02-01", -2260, "Rent", "Park Avenue Apartments"),
+		txn("2025-03-01", -2260, "Rent", "Park Avenue Apartments"),
+	}}
+	raw, err := NewService(repo, fixedClock("2025-03-20")).PredictFutureSpending(context.Background(), "1234567891", PredictionOptions{})
+	if err != nil {
+		t.Fatalf("PredictFutureSpending() failed: %v", err)
+	}
+
+	history := []types.CalibrationSample{
+		{Likelihood: raw[0].Likelihood, Hit: false},
+		{Likelihood: raw[0].Likelihood, Hit: false},
+		{Likelihood: raw[0].Likelihood, Hit: false},
+	}
+	got, err := NewService(repo, fixedClock("2025-03-20"), WithCalibration(history)).PredictFutureSpending(context.Background(), "1234567891", PredictionOptions{})
+	if err != nil {
+		t.Fatalf("PredictFutureSpending() failed: %v", err)
+	}
+
+	want := raw[0].Likelihood / 4
+	if !approxEqual(got[0].Likelihood, want) {
+		t.Errorf("calibrated Likelihood = %v, want %v", got[0].Likelihood, want)
+	}
+	if got[0].RawLikelihood != raw[0].Likelihood {
+		t.Errorf("RawLikelihood = %v, want %v", got[0].RawLikelihood, raw[0].Likelihood)
+	}
+	if got[0].Warning != nil {
+		t.Errorf("Warning = %+v, want none after calibrating down", got[0].Warning)
+	}
+}
+
+func TestBacktestPredictions(t *testing.T) {
+	var transactions []types.Transaction
+	for _, date := range []string{"2024-10-01", "2024-11-01", "2024-12-01", "2025-01-01", "2025-02-01", "2025-03-01"} {
+		transactions = append(transactions, txn(date, -2260, "Rent", "Park Avenue Apartments"))
+	}
+	svc := NewService(&fakeRepo{transactions: transactions}, fixedClock("2025-03-10"))
+
+	got, err := svc.BacktestPredictions(context.Background(), "1234567891")
+	if err != nil {
+		t.Fatalf("BacktestPredictions() failed: %v", err)
+	}
+
+	// Cutoffs after the 3rd, 4th and 5th payments can be scored; the window
+	// after the last payment is still open
+	if len(got) != 3 {
+		t.Fatalf("BacktestPredictions() returned %d samples, want 3", len(got))
+	}
+	for _, sample := range got {
+		if !sample.Hit {
+			t.Errorf("sample %+v missed, want a hit for a perfectly monthly charge", sample)
+		}
+	}
+}
+
+func TestBacktestPredictionsReproducible(t *testing.T) {
+	// Enough irregular history to exceed maxBacktestSamples cutoffs
+	var transactions []types.Transaction
+	start := txn("2024-06-01", 0, "", "").Date
+	for c := 0; c < 30; c++ {
+		date := start
+		for i := 0; i < 12; i++ {
+			date = date.AddDate(0, 0, 1+(i*7+c*3)%11)
+			tx := txn("2024-06-01", -20, fmt.Sprintf("Category %d", c), "Shop")
+			tx.Date = date
+			transactions = append(transactions, tx)
+		}
+	}
+	svc := NewService(&fakeRepo{transactions: transactions}, fixedClock("2025-03-10"))
+
+	first, err := svc.BacktestPredictions(context.Background(), "1234567891")
+	if err != nil {
+		t.Fatalf("BacktestPredictions() failed: %v", err)
+	}
+	second, err := svc.BacktestPredictions(context.Background(), "1234567891")
+	if err != nil {
+		t.Fatalf("BacktestPredictions() failed: %v", err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("BacktestPredictions() differs between identical calls:\n%+v\n%+v", first, second)
+	}
+}
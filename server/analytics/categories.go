@@ -0,0 +1,153 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WithCategoryAliases merges categories under one name, e.g. "supermarket"
+// into "Groceries". Aliases are matched like categories are merged anyway:
+// ignoring case and surrounding whitespace.
+func WithCategoryAliases(aliases map[string]string) Option {
+	return func(s *service) {
+		s.categoryAliases = make(map[string]string, len(aliases))
+		for alias, name := range aliases {
+			if name = strings.TrimSpace(name); name != "" {
+				s.categoryAliases[categoryKey(alias)] = name
+			}
+		}
+	}
+}
+
+// categoryKey is the form categories are merged by, so "Groceries",
+// "groceries" and "GROCERIES " are one category
+func categoryKey(category string) string {
+	return strings.ToLower(strings.TrimSpace(category))
+}
+
+// canonicalBudgets re-keys budgets by canonical category name, so a budget set
+// for "groceries" applies to the merged "Groceries"
+func (s *service) canonicalBudgets(budgets map[string]float64) map[string]float64 {
+	canonical := make(map[string]float64, len(budgets))
+	for category, budget := range budgets {
+		canonical[s.categories.name(category)] += budget
+	}
+	return canonical
+}
+
+// titleCase upper-cases the first letter of each word
+func titleCase(s string) string {
+	words := strings.Fields(s)
+	for i, w := range words {
+		r, size := utf8.DecodeRuneInString(w)
+		words[i] = string(unicode.ToUpper(r)) + w[size:]
+	}
+	return strings.Join(words, " ")
+}
+
+// normalizingRepository merges categories that differ only in case or
+// whitespace, or are aliases of one another, on every read, so each analysis
+// groups by the same names however it loads its data. A category keeps the
+// spelling it is recorded under while that is the only one seen; once it has
+// been seen spelled more than one way, its variants merge under its key
+// title-cased. Uncategorized spend, blank or not, stays uncategorized.
+type normalizingRepository struct {
+	next    Repository
+	aliases map[string]string
+
+	mu sync.Mutex
+	// spellings is the spelling seen for each key, or "" once it has been
+	// seen spelled more than one way
+	spellings map[string]string
+}
+
+// observe records the spellings of categories
+func (r *normalizingRepository) observe(categories []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, category := range categories {
+		key := categoryKey(category)
+		if key == "" {
+			continue
+		}
+		spelling := strings.TrimSpace(category)
+		if seen, ok := r.spellings[key]; !ok {
+			r.spellings[key] = spelling
+		} else if seen != spelling {
+			r.spellings[key] = ""
+		}
+	}
+}
+
+// name is the name category is shown and grouped under: its alias if it has
+// one, else its spelling if only one has been seen, else its key title-cased
+func (r *normalizingRepository) name(category string) string {
+	key := categoryKey(category)
+	if alias, ok := r.aliases[key]; ok {
+		return alias
+	}
+	r.mu.Lock()
+	spelling, ok := r.spellings[key]
+	r.mu.Unlock()
+	switch {
+	case !ok:
+		return strings.TrimSpace(category)
+	case spelling == "":
+		return titleCase(key)
+	}
+	return spelling
+}
+
+func (r *normalizingRepository) GetTransactions(ctx context.Context, accountID string, window types.DateRange) ([]types.Transaction, error) {
+	transactions, err := r.next.GetTransactions(ctx, accountID, window)
+	if err != nil {
+		return nil, err
+	}
+	var categories []string
+	for _, t := range transactions {
+		categories = append(categories, t.Category)
+		for _, split := range t.Splits {
+			categories = append(categories, split.Category)
+		}
+	}
+	r.observe(categories)
+
+	renamed := make([]types.Transaction, len(transactions))
+	for i, t := range transactions {
+		renamed[i] = t
+		renamed[i].Category = r.name(t.Category)
+		if len(t.Splits) > 0 {
+			renamed[i].Splits = make([]types.Split, len(t.Splits))
+			for j, split := range t.Splits {
+				renamed[i].Splits[j] = types.Split{Category: r.name(split.Category), Amount: split.Amount}
+			}
+		}
+	}
+	return renamed, nil
+}
+
+func (r *normalizingRepository) GetCategoryTotals(ctx context.Context, accountID string, window types.DateRange) (map[string]float64, error) {
+	totals, err := r.next.GetCategoryTotals(ctx, accountID, window)
+	if err != nil || totals == nil {
+		return totals, err
+	}
+	categories := make([]string, 0, len(totals))
+	for category := range totals {
+		categories = append(categories, category)
+	}
+	r.observe(categories)
+
+	merged := make(map[string]float64, len(totals))
+	for category, amount := range totals {
+		merged[r.name(category)] += amount
+	}
+	return merged, nil
+}
+
+func (r *normalizingRepository) GetBalance(ctx context.Context, accountID string) (float64, error) {
+	return r.next.GetBalance(ctx, accountID)
+}
@@ -0,0 +1,126 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+)
+
+func TestCategoryVariantsMerge(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-03-03", -80, "Groceries", "Whole Foods"),
+		txn("2025-03-10", -60, "groceries", "Whole Foods"),
+		txn("2025-03-17", -70, "GROCERIES ", "Whole Foods"),
+		txn("2025-03-12", -40, "Gas", "Shell"),
+	}}
+	svc := NewService(repo, fixedClock("2025-04-05"))
+
+	totals, err := svc.GetCategoryTotals(context.Background(), "1234567891", "1 month")
+	if err != nil {
+		t.Fatalf("GetCategoryTotals() failed: %v", err)
+	}
+	if len(totals) != 2 || !approxEqual(totals["Groceries"], 210) || !approxEqual(totals["Gas"], 40) {
+		t.Errorf("GetCategoryTotals() = %v, want Groceries at 210 and Gas at 40", totals)
+	}
+
+	got, err := svc.GetSpendingAnalytics(context.Background(), "1234567891", "1 month", AnalyticsOptions{})
+	if err != nil {
+		t.Fatalf("GetSpendingAnalytics() failed: %v", err)
+	}
+	if len(got.Data.TopCategories) != 2 || got.Data.TopCategories[0].Category != "Groceries" || got.Data.TopCategories[0].TotalSpent != "210.00" {
+		t.Errorf("TopCategories = %+v, want one Groceries row at 210.00", got.Data.TopCategories)
+	}
+	// Health is graded from its own load of the year, which must merge the
+	// same way for the row to find its grade
+	if got.Data.TopCategories[0].Health == nil {
+		t.Errorf("TopCategories[0].Health = nil, want the merged category's health")
+	}
+	health, err := svc.GetCategoryHealth(context.Background(), "1234567891")
+	if err != nil {
+		t.Fatalf("GetCategoryHealth() failed: %v", err)
+	}
+	if len(health) != 2 {
+		t.Errorf("GetCategoryHealth() = %v, want Groceries and Gas only", health)
+	}
+
+	predictions, err := svc.PredictFutureSpending(context.Background(), "1234567891", PredictionOptions{})
+	if err != nil {
+		t.Fatalf("PredictFutureSpending() failed: %v", err)
+	}
+	var groceries int
+	for _, p := range predictions {
+		if p.Category == "Groceries" && !p.ColdStart {
+			groceries++
+		}
+	}
+	if groceries != 1 {
+		t.Errorf("PredictFutureSpending() = %+v, want one Groceries prediction from all three spellings", predictions)
+	}
+}
+
+func TestWithCategoryAliases(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-03-03", -80, "Groceries", "Whole Foods"),
+		txn("2025-03-10", -60, "supermarket", "Safeway"),
+		txn("2025-03-12", -40, "FUEL", "Shell"),
+		txn("2025-03-14", -25, "fuel", "Shell"),
+	}}
+	svc := NewService(repo, fixedClock("2025-03-20"), WithCategoryAliases(map[string]string{" Supermarket": "Groceries"}))
+
+	totals, err := svc.GetCategoryTotals(context.Background(), "1234567891", "1 month")
+	if err != nil {
+		t.Fatalf("GetCategoryTotals() failed: %v", err)
+	}
+	// Fuel has no alias, so it is title-cased
+	want := map[string]float64{"Groceries": 140, "Fuel": 65}
+	if len(totals) != len(want) {
+		t.Fatalf("GetCategoryTotals() = %v, want %v", totals, want)
+	}
+	for category, amount := range want {
+		if !approxEqual(totals[category], amount) {
+			t.Errorf("GetCategoryTotals()[%q] = %v, want %v", category, totals[category], amount)
+		}
+	}
+}
+
+func TestBudgetsMatchMergedCategories(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-04-03", -70, "GROCERIES", "Whole Foods"),
+		txn("2025-04-08", -50, "groceries ", "Safeway"),
+	}}
+	svc := NewService(repo, fixedClock("2025-04-15"))
+
+	got, err := svc.CheckCategoryAlerts(context.Background(), "1234567891", map[string]float64{"groceries": 100})
+	if err != nil {
+		t.Fatalf("CheckCategoryAlerts() failed: %v", err)
+	}
+	if len(got) != 1 || got[0].Category != "Groceries" || !approxEqual(got[0].Spent, 120) || got[0].Level != types.AlertLevelBreach {
+		t.Errorf("CheckCategoryAlerts() = %+v, want a Groceries breach at 120", got)
+	}
+}
+
+func TestSingleSpellingsKept(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-04-03", -6, "ATM Fees", "Chase"),
+		txn("2025-04-08", -3, "ATM Fees ", "Chase"),
+		txn("2025-04-10", -40, "eBay", "eBay"),
+	}}
+	svc := NewService(repo, fixedClock("2025-04-15"))
+
+	totals, err := svc.GetCategoryTotals(context.Background(), "1234567891", "1 month")
+	if err != nil {
+		t.Fatalf("GetCategoryTotals() failed: %v", err)
+	}
+	if len(totals) != 2 || !approxEqual(totals["ATM Fees"], 9) || !approxEqual(totals["eBay"], 40) {
+		t.Errorf("GetCategoryTotals() = %v, want ATM Fees at 9 and eBay at 40", totals)
+	}
+
+	// A budget spelled another way still finds the category
+	got, err := svc.CheckBudgets(context.Background(), "1234567891", map[string]float64{"atm fees": 20})
+	if err != nil {
+		t.Fatalf("CheckBudgets() failed: %v", err)
+	}
+	if len(got) != 1 || got[0].Category != "ATM Fees" || !approxEqual(got[0].Spent, 9) {
+		t.Errorf("CheckBudgets() = %+v, want ATM Fees at 9", got)
+	}
+}
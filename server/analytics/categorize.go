@@ -0,0 +1,93 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"strings"
+)
+
+// Uncategorized is the category given to transactions no keyword rule matches
+const Uncategorized = "Uncategorized"
+
+// KeywordRule assigns Category to any transaction whose merchant name
+// contains Keyword, ignoring case
+type KeywordRule struct {
+	Keyword  string
+	Category string
+}
+
+// DefaultKeywordRules covers the merchants that turn up most often in
+// uncategorized bank feeds
+var DefaultKeywordRules = []KeywordRule{
+	{Keyword: "STARBUCKS", Category: "Coffee"},
+	{Keyword: "DUNKIN", Category: "Coffee"},
+	{Keyword: "SHELL", Category: "Gas"},
+	{Keyword: "EXXON", Category: "Gas"},
+	{Keyword: "CHEVRON", Category: "Gas"},
+	{Keyword: "UBER", Category: "Transport"},
+	{Keyword: "LYFT", Category: "Transport"},
+	{Keyword: "NETFLIX", Category: "Entertainment"},
+	{Keyword: "SPOTIFY", Category: "Entertainment"},
+	{Keyword: "WHOLE FOODS", Category: "Groceries"},
+	{Keyword: "TRADER JOE", Category: "Groceries"},
+	{Keyword: "AMAZON", Category: "Shopping"},
+}
+
+// KeywordCategorizer categorizes transactions from keywords in their
+// merchant names. Rules are tried in order and the first match wins.
+// It also satisfies CategoryResolver, so it can be plugged into the service
+// with WithCategoryResolver.
+type KeywordCategorizer struct {
+	rules []KeywordRule
+}
+
+// NewKeywordCategorizer builds a categorizer from rules, falling back to
+// DefaultKeywordRules when none are given
+func NewKeywordCategorizer(rules []KeywordRule) *KeywordCategorizer {
+	if len(rules) == 0 {
+		rules = DefaultKeywordRules
+	}
+	normalized := make([]KeywordRule, 0, len(rules))
+	for _, r := range rules {
+		keyword := strings.ToLower(strings.TrimSpace(r.Keyword))
+		if keyword == "" || r.Category == "" {
+			continue
+		}
+		normalized = append(normalized, KeywordRule{Keyword: keyword, Category: r.Category})
+	}
+	return &KeywordCategorizer{rules: normalized}
+}
+
+// Categorize returns a copy of txns with every uncategorized transaction
+// given a category from the rules, or Uncategorized if none match.
+// Transactions that already have a category are left alone.
+func (c *KeywordCategorizer) Categorize(txns []types.Transaction) []types.Transaction {
+	categorized := make([]types.Transaction, len(txns))
+	copy(categorized, txns)
+	for i, t := range categorized {
+		if t.Category == "" {
+			categorized[i].Category = c.match(t.Merchant)
+		}
+	}
+	return categorized
+}
+
+// Resolve returns the category the rules give the transaction's merchant
+func (c *KeywordCategorizer) Resolve(ctx context.Context, txn types.Transaction) (string, error) {
+	return c.match(txn.Merchant), nil
+}
+
+func (c *KeywordCategorizer) match(merchant string) string {
+	name := strings.ToLower(merchant)
+	for _, r := range c.rules {
+		if strings.Contains(name, r.Keyword) {
+			return r.Category
+		}
+	}
+	return Uncategorized
+}
+
+// Categorize categorizes txns with DefaultKeywordRules
+func Categorize(txns []types.Transaction) []types.Transaction {
+	return NewKeywordCategorizer(nil).Categorize(txns)
+}
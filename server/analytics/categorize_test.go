@@ -0,0 +1,74 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+)
+
+func TestCategorize(t *testing.T) {
+	txns := []types.Transaction{
+		txn("2025-04-02", -5, "", "STARBUCKS #1234"),
+		txn("2025-04-03", -40, "", "Shell Oil 5521"),
+		txn("2025-04-04", -12, "", "Corner Kiosk"),
+		txn("2025-04-05", -8, "Treats", "Starbucks Reserve"),
+	}
+
+	got := Categorize(txns)
+
+	want := []string{"Coffee", "Gas", Uncategorized, "Treats"}
+	for i, category := range want {
+		if got[i].Category != category {
+			t.Errorf("Categorize()[%d].Category = %q, want %q", i, got[i].Category, category)
+		}
+	}
+	if txns[0].Category != "" {
+		t.Errorf("Categorize() modified its input: %q", txns[0].Category)
+	}
+}
+
+func TestKeywordCategorizerCustomRules(t *testing.T) {
+	c := NewKeywordCategorizer([]KeywordRule{
+		{Keyword: "kiosk", Category: "Snacks"},
+		{Keyword: "STARBUCKS", Category: "Treats"},
+	})
+
+	got := c.Categorize([]types.Transaction{
+		txn("2025-04-02", -5, "", "STARBUCKS #1234"),
+		txn("2025-04-03", -40, "", "Shell Oil 5521"),
+		txn("2025-04-04", -12, "", "Corner Kiosk"),
+	})
+
+	want := []string{"Treats", Uncategorized, "Snacks"}
+	for i, category := range want {
+		if got[i].Category != category {
+			t.Errorf("Categorize()[%d].Category = %q, want %q", i, got[i].Category, category)
+		}
+	}
+}
+
+func TestGetSpendingAnalyticsKeywordCategorizer(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-04-02", -30, "", "STARBUCKS #1234"),
+		txn("2025-04-06", -20, "", "Starbucks Reserve"),
+		txn("2025-04-08", -15, "", "Corner Kiosk"),
+		txn("2025-04-05", -50, "Food", "Whole Foods"),
+	}}
+	svc := NewService(repo, fixedClock("2025-04-15"), WithCategoryResolver(NewKeywordCategorizer(nil)))
+
+	got, err := svc.GetSpendingAnalytics(context.Background(), "1234567891", "1 month", AnalyticsOptions{})
+	if err != nil {
+		t.Fatalf("GetSpendingAnalytics() failed: %v", err)
+	}
+
+	amounts := make(map[string]string)
+	for _, c := range got.Data.TopCategories {
+		amounts[c.Category] = c.TotalSpent
+	}
+	if amounts["Coffee"] != "50.00" || amounts["Food"] != "50.00" || amounts[Uncategorized] != "15.00" {
+		t.Errorf("category totals = %v, want Starbucks as Coffee and the kiosk Uncategorized", amounts)
+	}
+	if _, ok := amounts[""]; ok {
+		t.Errorf("category totals = %v, want no blank category left", amounts)
+	}
+}
@@ -0,0 +1,87 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"server/types"
+	"time"
+)
+
+// tenureCohorts are the account-age buckets spend is split into, by month of
+// account life starting at 1. A ToMonth of 0 is open-ended.
+var tenureCohorts = []struct {
+	label    string
+	from, to int
+}{
+	{"Month 1", 1, 1},
+	{"Months 2-3", 2, 3},
+	{"Months 4-6", 4, 6},
+	{"Months 7-12", 7, 12},
+	{"Months 13-24", 13, 24},
+	{"Months 25+", 25, 0},
+}
+
+// GetTenureCohorts buckets spend by how long the account had been open when
+// it happened, to show how behaviour matures after onboarding. Each cohort's
+// average counts only the part of it the account has lived through, and
+// cohorts the account hasn't reached yet are omitted.
+func (s *service) GetTenureCohorts(ctx context.Context, accountID string, openedAt time.Time) ([]types.TenureCohort, error) {
+	if openedAt.IsZero() {
+		return nil, errors.New("account open date is required")
+	}
+	now := s.now()
+	if openedAt.After(now) {
+		return nil, fmt.Errorf("account open date %s is in the future", openedAt.Format("2006-01-02"))
+	}
+
+	tenure := tenureMonths(openedAt, now)
+	transactions, err := s.loadTransactions(ctx, accountID, fmt.Sprintf("%d months", int(tenure)+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	result := make([]types.TenureCohort, 0, len(tenureCohorts))
+	for _, c := range tenureCohorts {
+		if tenure <= float64(c.from-1) {
+			break
+		}
+		cohort := types.TenureCohort{Label: c.label, FromMonth: c.from, ToMonth: c.to}
+		cohort.Months = tenure - float64(c.from-1)
+		if c.to > 0 && cohort.Months > float64(c.to-c.from+1) {
+			cohort.Months = float64(c.to - c.from + 1)
+		}
+		result = append(result, cohort)
+	}
+
+	for _, t := range transactions {
+		amount := expenseAmount(t)
+		if amount == 0 || t.Date.Before(openedAt) {
+			continue
+		}
+		month := int(tenureMonths(openedAt, t.Date)) + 1
+		for i := range result {
+			if month >= result[i].FromMonth && (result[i].ToMonth == 0 || month <= result[i].ToMonth) {
+				result[i].Total += amount
+				break
+			}
+		}
+	}
+	for i := range result {
+		result[i].MonthlyAverage = ratio(result[i].Total, result[i].Months)
+	}
+
+	return result, nil
+}
+
+// tenureMonths returns how many months of account life have passed between
+// opening and t, counting a partly elapsed month as a fraction
+func tenureMonths(openedAt, t time.Time) float64 {
+	months := 0
+	for !openedAt.AddDate(0, months+1, 0).After(t) {
+		months++
+	}
+	start := openedAt.AddDate(0, months, 0)
+	end := openedAt.AddDate(0, months+1, 0)
+	return float64(months) + float64(t.Sub(start))/float64(end.Sub(start))
+}
@@ -0,0 +1,49 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+)
+
+func TestGetTenureCohorts(t *testing.T) {
+	openedAt := txn("2024-10-10", 0, "", "").Date
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2024-10-09", -999, "Shopping", "Before Opening"), // ignored
+		txn("2024-10-12", -300, "Shopping", "Ikea"),           // month 1
+		txn("2024-11-09", -100, "Food", "Whole Foods"),        // month 1, a day before month 2
+		txn("2024-11-10", -200, "Food", "Whole Foods"),        // month 2
+		txn("2024-12-20", -400, "Food", "Whole Foods"),        // month 3
+		txn("2025-01-10", -150, "Food", "Whole Foods"),        // month 4
+		txn("2025-02-01", -70, "Food", "Whole Foods"),         // month 4
+		txn("2025-03-15", 3500, "Income", "Employer"),         // not spend
+	}}
+	// Five and a half months since opening
+	svc := NewService(repo, fixedClock("2025-03-25"))
+
+	got, err := svc.GetTenureCohorts(context.Background(), "1234567891", openedAt)
+	if err != nil {
+		t.Fatalf("GetTenureCohorts() failed: %v", err)
+	}
+
+	want := []struct {
+		label string
+		total float64
+		avg   float64
+	}{
+		{label: "Month 1", total: 400, avg: 400},
+		{label: "Months 2-3", total: 600, avg: 300},
+		{label: "Months 4-6", total: 220, avg: 220 / got[2].Months},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("GetTenureCohorts() returned %d cohorts, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Label != w.label || !approxEqual(got[i].Total, w.total) || !approxEqual(got[i].MonthlyAverage, w.avg) {
+			t.Errorf("cohort %d = %+v, want %s with total %v and average %v", i, got[i], w.label, w.total, w.avg)
+		}
+	}
+	if got[2].Months <= 2 || got[2].Months >= 3 {
+		t.Errorf("Months 4-6 observed %v months, want between 2 and 3", got[2].Months)
+	}
+}
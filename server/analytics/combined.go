@@ -0,0 +1,115 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"server/types"
+	"strings"
+)
+
+// CombineOptions tunes how several accounts are merged into one view
+type CombineOptions struct {
+	// ExcludeCategories leaves out whole categories, such as "Transfer" or
+	// "Credit Card Payment", for money moved between the user's own accounts
+	// that can't be paired up automatically, e.g. because one account isn't
+	// in the set. Matching is case-insensitive.
+	ExcludeCategories []string
+}
+
+// GetCombinedSpendingAnalytics is GetSpendingAnalytics over the merged
+// transactions of several accounts, such as a checking, savings and credit
+// card account. Settings like currency and timezone come from the first
+// account.
+func (s *service) GetCombinedSpendingAnalytics(ctx context.Context, accountIDs []string, timeRange string, opts AnalyticsOptions, combine CombineOptions) (*types.AnalyticsResponse, error) {
+	view, err := s.combined(accountIDs, combine)
+	if err != nil {
+		return nil, err
+	}
+	return view.GetSpendingAnalytics(ctx, accountIDs[0], timeRange, opts)
+}
+
+// PredictCombinedSpending is PredictFutureSpending over the merged
+// transactions of several accounts
+func (s *service) PredictCombinedSpending(ctx context.Context, accountIDs []string, opts PredictionOptions, combine CombineOptions) ([]types.PredictedSpend, error) {
+	view, err := s.combined(accountIDs, combine)
+	if err != nil {
+		return nil, err
+	}
+	return view.PredictFutureSpending(ctx, accountIDs[0], opts)
+}
+
+// combined returns a copy of the service that reads the merged transactions
+// of the given accounts, whichever account it is asked for
+func (s *service) combined(accountIDs []string, opts CombineOptions) (*service, error) {
+	if len(accountIDs) == 0 {
+		return nil, errors.New("at least one account ID is required")
+	}
+	exclude := make(map[string]bool, len(opts.ExcludeCategories))
+	for _, c := range opts.ExcludeCategories {
+		exclude[strings.ToLower(c)] = true
+	}
+
+	view := *s
+	view.repo = &combinedRepo{repo: s.repo, accountIDs: accountIDs, exclude: exclude}
+	return &view, nil
+}
+
+// combinedRepo merges several accounts' transactions into one. Internal
+// transfers between the accounts are dropped, as both legs would otherwise be
+// counted, as are excluded categories.
+type combinedRepo struct {
+	repo       Repository
+	accountIDs []string
+	exclude    map[string]bool
+}
+
+func (r *combinedRepo) GetTransactions(ctx context.Context, accountID string, window types.DateRange) ([]types.Transaction, error) {
+	byAccount := make(map[string][]types.Transaction, len(r.accountIDs))
+	for _, id := range r.accountIDs {
+		transactions, err := r.repo.GetTransactions(ctx, id, window)
+		if err != nil {
+			return nil, fmt.Errorf("account %s: %w", id, err)
+		}
+		byAccount[id] = transactions
+	}
+
+	transfers := internalTransfers(r.accountIDs, byAccount)
+	merged := make([]types.Transaction, 0)
+	for _, id := range r.accountIDs {
+		for i, t := range byAccount[id] {
+			if !transfers[transferLeg{id, i}] && !r.exclude[strings.ToLower(t.Category)] {
+				merged = append(merged, t)
+			}
+		}
+	}
+	return merged, nil
+}
+
+// GetCategoryTotals sums the merged expenses, so transfers are left out of
+// the totals too
+func (r *combinedRepo) GetCategoryTotals(ctx context.Context, accountID string, window types.DateRange) (map[string]float64, error) {
+	transactions, err := r.GetTransactions(ctx, accountID, window)
+	if err != nil {
+		return nil, err
+	}
+	totals := make(map[string]float64)
+	for _, t := range transactions {
+		if amount := expenseAmount(t); amount > 0 {
+			totals[t.Category] += amount
+		}
+	}
+	return totals, nil
+}
+
+func (r *combinedRepo) GetBalance(ctx context.Context, accountID string) (float64, error) {
+	var total float64
+	for _, id := range r.accountIDs {
+		balance, err := r.repo.GetBalance(ctx, id)
+		if err != nil {
+			return 0, fmt.Errorf("account %s: %w", id, err)
+		}
+		total += balance
+	}
+	return total, nil
+}
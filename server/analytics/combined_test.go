@@ -0,0 +1,74 @@
+package analytics
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetCombinedSpendingAnalytics(t *testing.T) {
+	repo := accountsRepo{
+		"checking": {
+			txn("2025-04-01", -2000, "Rent", "Park Avenue Apartments"),
+			txn("2025-04-03", -100, "Food", "Whole Foods"),
+			// Paying off the card, which shows up on both accounts
+			txn("2025-04-05", -500, "Payment", "Visa Card"),
+			// Moved to a savings account that isn't in the set
+			txn("2025-04-06", -300, "Transfer", "Savings"),
+		},
+		"card": {
+			txn("2025-04-06", 500, "Payment", "Thank You"),
+			txn("2025-03-20", -50, "Food", "Trader Joe's"),
+			txn("2025-04-10", -80, "Dining", "Chipotle"),
+		},
+	}
+	svc := NewService(repo, fixedClock("2025-04-15"))
+
+	got, err := svc.GetCombinedSpendingAnalytics(context.Background(), []string{"checking", "card"}, "1 month", AnalyticsOptions{}, CombineOptions{ExcludeCategories: []string{"transfer"}})
+	if err != nil {
+		t.Fatalf("GetCombinedSpendingAnalytics() failed: %v", err)
+	}
+
+	amounts := make(map[string]string)
+	for _, c := range got.Data.TopCategories {
+		amounts[c.Category] = c.TotalSpent
+	}
+	want := map[string]string{"Rent": "2000.00", "Food": "150.00", "Dining": "80.00"}
+	if len(amounts) != len(want) {
+		t.Errorf("category totals = %v, want %v", amounts, want)
+	}
+	for category, total := range want {
+		if amounts[category] != total {
+			t.Errorf("%s = %q, want %q", category, amounts[category], total)
+		}
+	}
+	if !approxEqual(got.Data.TotalSpent, 2230) || !approxEqual(got.Data.MonthlyAverage, 2230) {
+		t.Errorf("TotalSpent, MonthlyAverage = %v, %v, want 2230, 2230", got.Data.TotalSpent, got.Data.MonthlyAverage)
+	}
+
+	if _, err := svc.GetCombinedSpendingAnalytics(context.Background(), nil, "1 month", AnalyticsOptions{}, CombineOptions{}); err == nil {
+		t.Error("GetCombinedSpendingAnalytics() with no accounts succeeded, want an error")
+	}
+}
+
+func TestPredictCombinedSpending(t *testing.T) {
+	// Groceries are bought on either account, too rarely on each alone for a
+	// prediction
+	repo := accountsRepo{
+		"checking": {
+			txn("2025-01-10", -100, "Groceries", "Whole Foods"),
+			txn("2025-03-10", -100, "Groceries", "Whole Foods"),
+		},
+		"card": {
+			txn("2025-02-10", -100, "Groceries", "Whole Foods"),
+		},
+	}
+	svc := NewService(repo, fixedClock("2025-03-20"))
+
+	got, err := svc.PredictCombinedSpending(context.Background(), []string{"checking", "card"}, PredictionOptions{}, CombineOptions{})
+	if err != nil {
+		t.Fatalf("PredictCombinedSpending() failed: %v", err)
+	}
+	if len(got) != 1 || got[0].Category != "Groceries" || got[0].ColdStart {
+		t.Errorf("PredictCombinedSpending() = %+v, want one Groceries prediction from all three purchases", got)
+	}
+}
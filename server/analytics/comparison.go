@@ -0,0 +1,166 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"server/types"
+	"sort"
+	"time"
+)
+
+// ErrInsufficientHistory is returned when an analysis needs more history than
+// the account has
+var ErrInsufficientHistory = errors.New("insufficient transaction history")
+
+// CompareYearOverYear compares spend in a month ("2006-01") with the same month
+// a year earlier, overall and per category. Comparing like months controls for
+// seasonality that month-over-month comparisons get wrong.
+func (s *service) CompareYearOverYear(ctx context.Context, accountID string, period string) (*types.YoYComparison, error) {
+	start, err := time.ParseInLocation("2006-01", period, s.now().Location())
+	if err != nil {
+		return nil, fmt.Errorf("invalid period %q, expected YYYY-MM: %w", period, err)
+	}
+	end := start.AddDate(0, 1, 0)
+	priorStart := start.AddDate(-1, 0, 0)
+	priorEnd := priorStart.AddDate(0, 1, 0)
+
+	// Load the two months by date, so periods of any age can be compared
+	transactions, err := s.repo.GetTransactions(ctx, accountID, types.DateRange{Start: priorStart, End: end})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	// The prior month through the current one spans 13 months of data
+	var earliest time.Time
+	for _, t := range transactions {
+		if earliest.IsZero() || t.Date.Before(earliest) {
+			earliest = t.Date
+		}
+	}
+	if earliest.IsZero() || earliest.After(priorEnd) {
+		return nil, fmt.Errorf("year-over-year comparison needs 13 months of data: %w", ErrInsufficientHistory)
+	}
+
+	current := make(map[string]float64)
+	prior := make(map[string]float64)
+	for _, t := range transactions {
+		amount := expenseAmount(t)
+		if amount == 0 {
+			continue
+		}
+		switch {
+		case !t.Date.Before(start) && t.Date.Before(end):
+			current[t.Category] += amount
+		case !t.Date.Before(priorStart) && t.Date.Before(priorEnd):
+			prior[t.Category] += amount
+		}
+	}
+
+	result := &types.YoYComparison{
+		Period:      period,
+		PriorPeriod: priorStart.Format("2006-01"),
+		Categories:  categoryDeltas(current, prior),
+	}
+	for _, d := range result.Categories {
+		result.Current += d.Current
+		result.Prior += d.Prior
+	}
+	result.Change = result.Current - result.Prior
+	result.PercentChange = percentChange(result.Current, result.Prior)
+
+	return result, nil
+}
+
+// topIncreases is how many of the largest category increases CompareSpending
+// marks for highlighting
+const topIncreases = 3
+
+// CompareSpending compares spend per category between two periods, such as
+// this month and last, along with the overall change. Categories with spend in
+// only one period are marked new or discontinued, and the largest increases
+// are marked so they can be highlighted.
+func (s *service) CompareSpending(ctx context.Context, accountID string, currentRange, priorRange types.DateRange) (*types.SpendingComparison, error) {
+	current, err := LoadCategoryTotals(ctx, s.repo, accountID, currentRange)
+	if err != nil {
+		return nil, err
+	}
+	prior, err := LoadCategoryTotals(ctx, s.repo, accountID, priorRange)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &types.SpendingComparison{
+		CurrentRange: currentRange,
+		PriorRange:   priorRange,
+		Categories:   categoryDeltas(current, prior),
+	}
+	for i := range result.Categories {
+		d := &result.Categories[i]
+		d.New = d.Prior == 0 && d.Current > 0
+		d.Discontinued = d.Current == 0 && d.Prior > 0
+		result.Current += d.Current
+		result.Prior += d.Prior
+	}
+	// Deltas are ordered by the size of the change, so the first increases
+	// are the largest
+	marked := 0
+	for i := range result.Categories {
+		if d := &result.Categories[i]; d.Change > 0 && marked < topIncreases {
+			d.TopIncrease = true
+			marked++
+		}
+	}
+	result.Change = result.Current - result.Prior
+	result.PercentChange = percentChange(result.Current, result.Prior)
+
+	return result, nil
+}
+
+// categoryDeltas compares two sets of category totals, including categories
+// present in only one of them, largest absolute change first
+func categoryDeltas(current, prior map[string]float64) []types.CategoryDelta {
+	categories := make(map[string]bool)
+	for c := range current {
+		categories[c] = true
+	}
+	for c := range prior {
+		categories[c] = true
+	}
+
+	deltas := make([]types.CategoryDelta, 0, len(categories))
+	for c := range categories {
+		deltas = append(deltas, types.CategoryDelta{
+			Category:      c,
+			Current:       current[c],
+			Prior:         prior[c],
+			Change:        current[c] - prior[c],
+			PercentChange: percentChange(current[c], prior[c]),
+		})
+	}
+
+	sort.Slice(deltas, func(i, j int) bool {
+		ci, cj := abs(deltas[i].Change), abs(deltas[j].Change)
+		if ci == cj {
+			return deltas[i].Category < deltas[j].Category
+		}
+		return ci > cj
+	})
+	return deltas
+}
+
+// percentChange returns the change from prior to current as a percentage, or
+// 0 when there is no prior spend to compare against
+func percentChange(current, prior float64) float64 {
+	if prior == 0 {
+		return 0
+	}
+	return (current - prior) / prior * 100
+}
+
+func abs(x float64) float64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
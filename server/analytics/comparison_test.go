@@ -0,0 +1,149 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"server/types"
+	"testing"
+	"time"
+)
+
+func TestCompareYearOverYear(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2024-02-20", -70, "Dining", "Chipotle"),
+		txn("2024-03-05", -200, "Groceries", "Whole Foods"),
+		txn("2024-03-12", -100, "Dining", "Chipotle"),
+		txn("2024-04-02", -900, "Travel", "Delta"),
+		txn("2025-02-14", -300, "Dining", "Le Bernardin"),
+		txn("2025-03-04", -250, "Groceries", "Whole Foods"),
+		txn("2025-03-18", -150, "Electronics", "Best Buy"),
+		txn("2025-03-21", 3000, "Income", "Tech Corp Inc"),
+	}}
+	svc := NewService(repo, fixedClock("2025-04-10"))
+
+	got, err := svc.CompareYearOverYear(context.Background(), "1234567891", "2025-03")
+	if err != nil {
+		t.Fatalf("CompareYearOverYear() failed: %v", err)
+	}
+
+	if got.PriorPeriod != "2024-03" {
+		t.Errorf("PriorPeriod = %q, want 2024-03", got.PriorPeriod)
+	}
+	if !approxEqual(got.Current, 400) || !approxEqual(got.Prior, 300) {
+		t.Errorf("Current, Prior = %v, %v, want 400, 300", got.Current, got.Prior)
+	}
+	if !approxEqual(got.PercentChange, 33.33) {
+		t.Errorf("PercentChange = %v, want 33.33", got.PercentChange)
+	}
+
+	want := map[string]types.CategoryDelta{
+		"Groceries":   {Category: "Groceries", Current: 250, Prior: 200, Change: 50, PercentChange: 25},
+		"Dining":      {Category: "Dining", Current: 0, Prior: 100, Change: -100, PercentChange: -100},
+		"Electronics": {Category: "Electronics", Current: 150, Prior: 0, Change: 150, PercentChange: 0},
+	}
+	if len(got.Categories) != len(want) {
+		t.Fatalf("Categories = %+v, want %d entries", got.Categories, len(want))
+	}
+	for _, d := range got.Categories {
+		if d != want[d.Category] {
+			t.Errorf("delta for %s = %+v, want %+v", d.Category, d, want[d.Category])
+		}
+	}
+}
+
+func TestCompareYearOverYearNeedsThirteenMonths(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2024-09-01", -100, "Groceries", "Whole Foods"),
+		txn("2025-03-04", -250, "Groceries", "Whole Foods"),
+	}}
+	svc := NewService(repo, fixedClock("2025-04-10"))
+
+	_, err := svc.CompareYearOverYear(context.Background(), "1234567891", "2025-03")
+	if !errors.Is(err, ErrInsufficientHistory) {
+		t.Errorf("CompareYearOverYear() error = %v, want ErrInsufficientHistory", err)
+	}
+}
+
+// windowRepo is a fakeRepo whose category totals honour the requested range
+type windowRepo struct {
+	fakeRepo
+}
+
+func (w *windowRepo) GetCategoryTotals(ctx context.Context, accountID string, window types.DateRange) (map[string]float64, error) {
+	totals := make(map[string]float64)
+	for _, t := range w.transactions {
+		if t.Amount < 0 && window.Contains(t.Date) {
+			totals[t.Category] -= t.Amount
+		}
+	}
+	return totals, nil
+}
+
+func TestCompareYearOverYearOldPeriod(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2020-03-05", -200, "Groceries", "Whole Foods"),
+		txn("2021-03-04", -250, "Groceries", "Whole Foods"),
+	}}
+	svc := NewService(repo, fixedClock("2025-04-10"))
+
+	got, err := svc.CompareYearOverYear(context.Background(), "1234567891", "2021-03")
+	if err != nil {
+		t.Fatalf("CompareYearOverYear() failed: %v", err)
+	}
+	if !approxEqual(got.Current, 250) || !approxEqual(got.Prior, 200) {
+		t.Errorf("Current, Prior = %v, %v, want 250, 200", got.Current, got.Prior)
+	}
+	want := types.DateRange{Start: time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)}
+	if len(repo.ranges) != 1 || !repo.ranges[0].Start.Equal(want.Start) || !repo.ranges[0].End.Equal(want.End) {
+		t.Errorf("ranges = %v, want %v", repo.ranges, want)
+	}
+}
+
+func TestCompareSpending(t *testing.T) {
+	repo := &windowRepo{fakeRepo{transactions: []types.Transaction{
+		txn("2025-02-05", -200, "Groceries", "Whole Foods"),
+		txn("2025-02-11", -80, "Dining", "Chipotle"),
+		txn("2025-02-20", -60, "Fuel", "Shell"),
+		txn("2025-03-05", -250, "Groceries", "Whole Foods"),
+		txn("2025-03-19", -150, "Groceries", "Whole Foods"),
+		txn("2025-03-14", -45, "Pets", "Chewy"),
+		txn("2025-03-22", -55, "Fuel", "Shell"),
+	}}}
+	svc := NewService(repo)
+
+	month := func(m time.Month) types.DateRange {
+		start := time.Date(2025, m, 1, 0, 0, 0, 0, time.UTC)
+		return types.DateRange{Start: start, End: start.AddDate(0, 1, 0).Add(-time.Nanosecond)}
+	}
+	got, err := svc.CompareSpending(context.Background(), "1234567891", month(time.March), month(time.February))
+	if err != nil {
+		t.Fatalf("CompareSpending() failed: %v", err)
+	}
+
+	if !approxEqual(got.Current, 500) || !approxEqual(got.Prior, 340) || !approxEqual(got.Change, 160) {
+		t.Errorf("Current, Prior, Change = %v, %v, %v, want 500, 340, 160", got.Current, got.Prior, got.Change)
+	}
+
+	want := map[string]types.CategoryDelta{
+		// Doubled
+		"Groceries": {Category: "Groceries", Current: 400, Prior: 200, Change: 200, PercentChange: 100, TopIncrease: true},
+		// Vanished
+		"Dining": {Category: "Dining", Current: 0, Prior: 80, Change: -80, PercentChange: -100, Discontinued: true},
+		// Brand new
+		"Pets": {Category: "Pets", Current: 45, Prior: 0, Change: 45, New: true, TopIncrease: true},
+		"Fuel": {Category: "Fuel", Current: 55, Prior: 60, Change: -5, PercentChange: -8.333333333333332},
+	}
+	if len(got.Categories) != len(want) {
+		t.Fatalf("Categories = %+v, want %d entries", got.Categories, len(want))
+	}
+	for _, d := range got.Categories {
+		w := want[d.Category]
+		if d.New != w.New || d.Discontinued != w.Discontinued || d.TopIncrease != w.TopIncrease ||
+			!approxEqual(d.Change, w.Change) || !approxEqual(d.PercentChange, w.PercentChange) {
+			t.Errorf("delta for %s = %+v, want %+v", d.Category, d, w)
+		}
+	}
+	if got.Categories[0].Category != "Groceries" {
+		t.Errorf("largest change = %s, want Groceries", got.Categories[0].Category)
+	}
+}
@@ -0,0 +1,99 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"server/types"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrConfigNotFound is returned by a ConfigStore when an account has no saved
+// configuration
+var ErrConfigNotFound = errors.New("account config not found")
+
+// ConfigStore persists per-account settings so callers don't have to pass
+// budgets, timezone and the like on every call
+type ConfigStore interface {
+	LoadConfig(ctx context.Context, accountID string) (*types.AccountConfig, error)
+	SaveConfig(ctx context.Context, config types.AccountConfig) error
+}
+
+// WithConfigStore loads each account's saved configuration on every call.
+// Saved settings take precedence over the service-wide defaults.
+func WithConfigStore(store ConfigStore) Option {
+	return func(s *service) {
+		s.configs = store
+	}
+}
+
+// accountConfig returns the saved configuration for an account, or an empty
+// one when there is no store or nothing has been saved
+func (s *service) accountConfig(ctx context.Context, accountID string) (types.AccountConfig, error) {
+	if s.configs == nil {
+		return types.AccountConfig{AccountID: accountID}, nil
+	}
+	config, err := s.configs.LoadConfig(ctx, accountID)
+	if errors.Is(err, ErrConfigNotFound) {
+		return types.AccountConfig{AccountID: accountID}, nil
+	}
+	if err != nil {
+		return types.AccountConfig{}, fmt.Errorf("failed to load account config: %w", err)
+	}
+	return *config, nil
+}
+
+// configLocation returns the account's timezone, defaulting to the service
+// clock's location
+func (s *service) configLocation(config types.AccountConfig) (*time.Location, error) {
+	if config.Timezone == "" {
+		return s.now().Location(), nil
+	}
+	loc, err := time.LoadLocation(config.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", config.Timezone, err)
+	}
+	return loc, nil
+}
+
+// configCurrency returns the account's reporting currency, defaulting to the
+// service-wide one
+func (s *service) configCurrency(config types.AccountConfig) string {
+	if code := strings.ToUpper(strings.TrimSpace(config.Currency)); code != "" {
+		return code
+	}
+	return s.currency
+}
+
+// MemoryConfigStore is a ConfigStore held in memory, for tests and single
+// instance deployments
+type MemoryConfigStore struct {
+	mu      sync.RWMutex
+	configs map[string]types.AccountConfig
+}
+
+func NewMemoryConfigStore() *MemoryConfigStore {
+	return &MemoryConfigStore{configs: make(map[string]types.AccountConfig)}
+}
+
+func (m *MemoryConfigStore) LoadConfig(ctx context.Context, accountID string) (*types.AccountConfig, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	config, ok := m.configs[accountID]
+	if !ok {
+		return nil, fmt.Errorf("account %s: %w", accountID, ErrConfigNotFound)
+	}
+	return &config, nil
+}
+
+func (m *MemoryConfigStore) SaveConfig(ctx context.Context, config types.AccountConfig) error {
+	if config.AccountID == "" {
+		return errors.New("account ID is required")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.configs[config.AccountID] = config
+	return nil
+}
@@ -0,0 +1,86 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"server/types"
+	"testing"
+	"time"
+)
+
+func TestMemoryConfigStore(t *testing.T) {
+	store := NewMemoryConfigStore()
+	ctx := context.Background()
+
+	if _, err := store.LoadConfig(ctx, "1234567891"); !errors.Is(err, ErrConfigNotFound) {
+		t.Fatalf("LoadConfig() error = %v, want ErrConfigNotFound", err)
+	}
+
+	saved := types.AccountConfig{
+		AccountID:           "1234567891",
+		Budgets:             map[string]float64{"Food": 400},
+		EssentialCategories: []string{"Rent", "Utilities"},
+		Timezone:            "America/New_York",
+		WeekStart:           time.Monday,
+		Currency:            "EUR",
+	}
+	if err := store.SaveConfig(ctx, saved); err != nil {
+		t.Fatalf("SaveConfig() failed: %v", err)
+	}
+
+	got, err := store.LoadConfig(ctx, "1234567891")
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+	if got.Timezone != saved.Timezone || got.Currency != saved.Currency || got.Budgets["Food"] != 400 {
+		t.Errorf("LoadConfig() = %+v, want %+v", got, saved)
+	}
+}
+
+func TestAnalyzeTimePatternsUsesSavedTimezone(t *testing.T) {
+	store := NewMemoryConfigStore()
+	if err := store.SaveConfig(context.Background(), types.AccountConfig{
+		AccountID: "1234567891",
+		Timezone:  "America/New_York",
+	}); err != nil {
+		t.Fatalf("SaveConfig() failed: %v", err)
+	}
+
+	// 14:00 UTC on a January Monday is 09:00 in New York
+	coffee := txn("2025-01-06", -4.75, "Food", "Starbucks")
+	coffee.Date = coffee.Date.Add(2 * time.Hour)
+	svc := NewService(&fakeRepo{transactions: []types.Transaction{coffee}}, WithConfigStore(store))
+
+	got, err := svc.AnalyzeTimePatterns(context.Background(), "1234567891", coffee.Date.AddDate(0, 0, -1), coffee.Date.AddDate(0, 0, 1), PatternOptions{})
+	if err != nil {
+		t.Fatalf("AnalyzeTimePatterns() failed: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("AnalyzeTimePatterns() returned %d patterns, want 1", len(got))
+	}
+	if got[0].TimeOfDay != "09:00" || got[0].DayOfWeek != "Monday" {
+		t.Errorf("pattern at %s %s, want Monday 09:00", got[0].DayOfWeek, got[0].TimeOfDay)
+	}
+}
+
+func TestCheckCategoryAlertsUsesSavedBudgets(t *testing.T) {
+	store := NewMemoryConfigStore()
+	if err := store.SaveConfig(context.Background(), types.AccountConfig{
+		AccountID: "1234567891",
+		Budgets:   map[string]float64{"Food": 100},
+	}); err != nil {
+		t.Fatalf("SaveConfig() failed: %v", err)
+	}
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-04-03", -120, "Food", "Whole Foods"),
+	}}
+	svc := NewService(repo, fixedClock("2025-04-15"), WithConfigStore(store))
+
+	got, err := svc.CheckCategoryAlerts(context.Background(), "1234567891", nil)
+	if err != nil {
+		t.Fatalf("CheckCategoryAlerts() failed: %v", err)
+	}
+	if len(got) != 1 || got[0].Level != types.AlertLevelBreach {
+		t.Errorf("CheckCategoryAlerts() = %+v, want one Food breach", got)
+	}
+}
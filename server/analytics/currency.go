@@ -0,0 +1,98 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"strings"
+	"time"
+)
+
+// defaultCurrency is the reporting currency when none is configured
+const defaultCurrency = "USD"
+
+// WithCurrency sets the reporting currency used to format amounts
+func WithCurrency(code string) Option {
+	return func(s *service) {
+		if code = strings.ToUpper(strings.TrimSpace(code)); code != "" {
+			s.currency = code
+		}
+	}
+}
+
+// CurrencyPrecision returns the number of fractional digits amounts in the
+// given currency are reported with
+func CurrencyPrecision(currency string) int {
+	return types.CurrencyPrecision(currency)
+}
+
+// FormatAmount formats an amount with the fractional digits of its currency,
+// e.g. 1234.5 is "1234.50" in USD and "1235" in JPY
+func FormatAmount(amount float64, currency string) string {
+	return types.FormatAmount(amount, currency)
+}
+
+// RateProvider supplies the exchange rates used to convert transactions into
+// the reporting currency
+type RateProvider interface {
+	// Rate returns how much one unit of from was worth in to on the given day
+	Rate(ctx context.Context, from, to string, on time.Time) (float64, error)
+}
+
+// WithRateProvider converts every transaction into the account's reporting
+// currency, set by WithCurrency or the account config, before category
+// totals are summed or spending is predicted. Without a provider amounts are summed as they are,
+// whatever their currency.
+func WithRateProvider(p RateProvider) Option {
+	return func(s *service) {
+		s.rates = p
+	}
+}
+
+// convertCurrency returns a copy of transactions with every amount, splits
+// included, in base. Transactions with no currency are taken to be in base
+// already.
+func (s *service) convertCurrency(ctx context.Context, transactions []types.Transaction, base string) ([]types.Transaction, error) {
+	converted := make([]types.Transaction, len(transactions))
+	copy(converted, transactions)
+	for i, t := range converted {
+		from := strings.ToUpper(strings.TrimSpace(t.Currency))
+		if from == "" || from == base {
+			converted[i].Currency = base
+			continue
+		}
+		rate, err := s.rates.Rate(ctx, from, base, t.Date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s/%s exchange rate: %w", from, base, err)
+		}
+		converted[i].Amount = t.Amount * rate
+		converted[i].Currency = base
+		if len(t.Splits) > 0 {
+			converted[i].Splits = make([]types.Split, len(t.Splits))
+			for j, split := range t.Splits {
+				converted[i].Splits[j] = types.Split{Category: split.Category, Amount: split.Amount * rate}
+			}
+		}
+	}
+	return converted, nil
+}
+
+// inCurrency converts transactions into currency when a rate provider is set,
+// and returns them as they are otherwise
+func (s *service) inCurrency(ctx context.Context, transactions []types.Transaction, currency string) ([]types.Transaction, error) {
+	if s.rates == nil {
+		return transactions, nil
+	}
+	return s.convertCurrency(ctx, transactions, currency)
+}
+
+// expenseTotals sums the expenses in transactions by category
+func expenseTotals(transactions []types.Transaction) map[string]float64 {
+	totals := make(map[string]float64)
+	for _, t := range transactions {
+		if amount := expenseAmount(t); amount > 0 {
+			totals[t.Category] += amount
+		}
+	}
+	return totals
+}
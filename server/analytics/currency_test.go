@@ -0,0 +1,77 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+	"time"
+)
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		currency string
+		want     string
+	}{
+		{currency: "USD", want: "1234.57"},
+		{currency: "JPY", want: "1235"},
+		{currency: "jpy", want: "1235"},
+		{currency: "KWD", want: "1234.568"},
+		{currency: "", want: "1234.57"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.currency, func(t *testing.T) {
+			if got := FormatAmount(1234.5678, tt.currency); got != tt.want {
+				t.Errorf("FormatAmount(1234.5678, %q) = %q, want %q", tt.currency, got, tt.want)
+			}
+		})
+	}
+}
+
+// fixedRates quotes the same rate for a currency pair on every day
+type fixedRates map[string]float64
+
+func (r fixedRates) Rate(ctx context.Context, from, to string, on time.Time) (float64, error) {
+	return r[from+"/"+to], nil
+}
+
+func TestWithRateProvider(t *testing.T) {
+	inCurrency := func(t types.Transaction, currency string) types.Transaction {
+		t.Currency = currency
+		return t
+	}
+	repo := &fakeRepo{transactions: []types.Transaction{
+		inCurrency(txn("2025-04-02", -100, "Food", "Whole Foods"), "USD"),
+		inCurrency(txn("2025-04-03", -50, "Food", "Carrefour"), "EUR"),
+		txn("2025-04-04", -20, "Gas", "Shell"),
+	}}
+
+	for _, tt := range []struct {
+		name  string
+		opts  []Option
+		food  float64
+		total float64
+	}{
+		{name: "USD base", opts: []Option{WithRateProvider(fixedRates{"EUR/USD": 1.10})}, food: 155, total: 175},
+		{name: "EUR base", opts: []Option{WithCurrency("EUR"), WithRateProvider(fixedRates{"USD/EUR": 0.5})}, food: 100, total: 120},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(repo, append(tt.opts, fixedClock("2025-04-15"))...)
+
+			totals, err := svc.GetCategoryTotals(context.Background(), "1234567891", "1 month")
+			if err != nil {
+				t.Fatalf("GetCategoryTotals() failed: %v", err)
+			}
+			if !approxEqual(totals["Food"], tt.food) {
+				t.Errorf("GetCategoryTotals()[Food] = %v, want %v", totals["Food"], tt.food)
+			}
+
+			got, err := svc.GetSpendingAnalytics(context.Background(), "1234567891", "1 month", AnalyticsOptions{})
+			if err != nil {
+				t.Fatalf("GetSpendingAnalytics() failed: %v", err)
+			}
+			if !approxEqual(got.Data.TotalSpent, tt.total) {
+				t.Errorf("TotalSpent = %v, want %v", got.Data.TotalSpent, tt.total)
+			}
+		})
+	}
+}
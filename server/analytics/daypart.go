@@ -0,0 +1,56 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dayParts lists the parts of the day in the order summaries report them
+var dayParts = []string{"morning", "afternoon", "evening", "night"}
+
+// GetDayPartSummary totals the spend between start and end by part of the
+// day and names the part of the day and the day of the week with the most
+// spend. It is built from the hourly time patterns.
+func (s *service) GetDayPartSummary(ctx context.Context, accountID string, start, end time.Time) (*types.DayPartSummary, error) {
+	patterns, err := s.AnalyzeTimePatterns(ctx, accountID, start, end, PatternOptions{Granularity: types.PatternHourly})
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make(map[string]*types.DayPartSpend, len(dayParts))
+	summary := &types.DayPartSummary{DayParts: make([]types.DayPartSpend, len(dayParts))}
+	for i, part := range dayParts {
+		summary.DayParts[i].DayPart = part
+		parts[part] = &summary.DayParts[i]
+	}
+
+	days := make(map[string]float64)
+	for _, p := range patterns {
+		hour, err := strconv.Atoi(strings.SplitN(p.TimeOfDay, ":", 2)[0])
+		if err != nil {
+			continue
+		}
+		total := p.AverageSpend * float64(p.Frequency)
+		part := parts[dayPart(hour)]
+		part.Total += total
+		part.Count += p.Frequency
+		days[p.DayOfWeek] += total
+	}
+
+	for _, part := range summary.DayParts {
+		if part.Total > 0 && (summary.TopDayPart == "" || part.Total > parts[summary.TopDayPart].Total) {
+			summary.TopDayPart = part.DayPart
+		}
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if total := days[day.String()]; total > summary.TopDaySpend {
+			summary.TopDay = day.String()
+			summary.TopDaySpend = total
+		}
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,57 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+	"time"
+)
+
+func TestGetDayPartSummary(t *testing.T) {
+	at := func(date string, hour int, amount float64, merchant string) types.Transaction {
+		t := txn(date, amount, "Food", merchant)
+		t.Date = t.Date.Add(time.Duration(hour-12) * time.Hour)
+		return t
+	}
+	repo := &fakeRepo{transactions: []types.Transaction{
+		// Friday nights out
+		at("2025-03-07", 19, -65, "Bar Centro"),
+		at("2025-03-07", 21, -30, "Late Slice"),
+		at("2025-03-14", 20, -80, "Bar Centro"),
+		at("2025-03-21", 19, -55, "Bar Centro"),
+		// Weekday coffee and lunch
+		at("2025-03-04", 8, -5, "Starbucks"),
+		at("2025-03-11", 8, -5, "Starbucks"),
+		at("2025-03-12", 13, -14, "Chipotle"),
+		at("2025-03-16", 2, -18, "Diner"),
+	}}
+	svc := NewService(repo, fixedClock("2025-03-25"))
+	start, end := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	got, err := svc.GetDayPartSummary(context.Background(), "1234567891", start, end)
+	if err != nil {
+		t.Fatalf("GetDayPartSummary() failed: %v", err)
+	}
+
+	if got.TopDay != "Friday" || !approxEqual(got.TopDaySpend, 230) {
+		t.Errorf("top day = %s at %v, want Friday at 230", got.TopDay, got.TopDaySpend)
+	}
+	if got.TopDayPart != "evening" {
+		t.Errorf("TopDayPart = %q, want evening", got.TopDayPart)
+	}
+	want := []types.DayPartSpend{
+		{DayPart: "morning", Total: 10, Count: 2},
+		{DayPart: "afternoon", Total: 14, Count: 1},
+		{DayPart: "evening", Total: 230, Count: 4},
+		{DayPart: "night", Total: 18, Count: 1},
+	}
+	if len(got.DayParts) != len(want) {
+		t.Fatalf("DayParts = %+v, want %+v", got.DayParts, want)
+	}
+	for i, w := range want {
+		p := got.DayParts[i]
+		if p.DayPart != w.DayPart || !approxEqual(p.Total, w.Total) || p.Count != w.Count {
+			t.Errorf("DayParts[%d] = %+v, want %+v", i, p, w)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"sort"
+	"time"
+)
+
+// GetDormantCategories lists categories the account used to spend in but
+// hasn't in the last inactiveDays days, as candidates to prune from a budget.
+// History is read back a year, or twice the inactive window if that's longer.
+func (s *service) GetDormantCategories(ctx context.Context, accountID string, inactiveDays int) ([]string, error) {
+	if inactiveDays <= 0 {
+		return nil, fmt.Errorf("inactive days must be positive, got %d", inactiveDays)
+	}
+
+	timeRange := "1 year"
+	if inactiveDays*2 > 365 {
+		timeRange = fmt.Sprintf("%d days", inactiveDays*2)
+	}
+	transactions, err := s.loadTransactions(ctx, accountID, timeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	cutoff := s.now().AddDate(0, 0, -inactiveDays)
+	historical, recent := categorySets(transactions, cutoff)
+
+	dormant := make([]string, 0)
+	for category := range historical {
+		if !recent[category] {
+			dormant = append(dormant, category)
+		}
+	}
+	sort.Strings(dormant)
+	return dormant, nil
+}
+
+// categorySets splits the categories with spend into those seen before cutoff
+// and those seen on or after it
+func categorySets(transactions []types.Transaction, cutoff time.Time) (before, after map[string]bool) {
+	before, after = make(map[string]bool), make(map[string]bool)
+	for _, t := range transactions {
+		if expenseAmount(t) == 0 {
+			continue
+		}
+		if t.Date.Before(cutoff) {
+			before[t.Category] = true
+		} else {
+			after[t.Category] = true
+		}
+	}
+	return before, after
+}
@@ -0,0 +1,35 @@
+package analytics
+
+import (
+	"context"
+	"reflect"
+	"server/types"
+	"testing"
+)
+
+func TestGetDormantCategories(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		// Gym membership cancelled after January
+		txn("2024-11-03", -50, "Fitness", "Equinox"),
+		txn("2024-12-03", -50, "Fitness", "Equinox"),
+		txn("2025-01-03", -50, "Fitness", "Equinox"),
+		// Still active
+		txn("2024-12-12", -84.20, "Food", "Whole Foods"),
+		txn("2025-04-02", -60, "Food", "Whole Foods"),
+		// Only a credit since the cutoff doesn't count as activity
+		txn("2024-12-20", -120, "Shopping", "Amazon"),
+		txn("2025-04-01", 30, "Shopping", "Amazon"),
+		// Income is never budgeted spend
+		txn("2024-12-15", 3500, "Income", "Employer"),
+	}}
+	svc := NewService(repo, fixedClock("2025-04-15"))
+
+	got, err := svc.GetDormantCategories(context.Background(), "1234567891", 60)
+	if err != nil {
+		t.Fatalf("GetDormantCategories() failed: %v", err)
+	}
+	want := []string{"Fitness", "Shopping"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetDormantCategories() = %v, want %v", got, want)
+	}
+}
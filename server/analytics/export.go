@@ -0,0 +1,84 @@
+package analytics
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"server/types"
+	"strconv"
+)
+
+// ErrUnsupportedFormat is returned when analytics are exported in a format
+// other than "json" or "csv"
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+// ExportAnalytics writes analytics to w as "json" or "csv". JSON is the
+// struct as the API serves it, stamped with its schema version. CSV flattens the top categories and the
+// predictions into labelled sections, each with a header row, which are still
+// written when there is nothing to list.
+func ExportAnalytics(analytics *types.SpendingAnalytics, format string, w io.Writer) error {
+	export := types.SpendingAnalytics{}
+	if analytics != nil {
+		export = *analytics
+	}
+	// Stamp the schema so the export can be read back with MigrateAnalytics
+	if export.SchemaVersion == 0 {
+		export.SchemaVersion = SchemaVersion
+	}
+	// Encode empty lists as [] like the API does, rather than null
+	if export.TopCategories == nil {
+		export.TopCategories = []types.CategorySpend{}
+	}
+	if export.SpendingPatterns == nil {
+		export.SpendingPatterns = []types.TimePattern{}
+	}
+	if export.PredictedSpending == nil {
+		export.PredictedSpending = []types.PredictedSpend{}
+	}
+
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(export); err != nil {
+			return fmt.Errorf("failed to encode analytics: %w", err)
+		}
+		return nil
+	case "csv":
+		return exportCSV(export, w)
+	default:
+		return fmt.Errorf("%w %q", ErrUnsupportedFormat, format)
+	}
+}
+
+// exportCSV writes the CSV sections of ExportAnalytics
+func exportCSV(analytics types.SpendingAnalytics, w io.Writer) error {
+	records := [][]string{
+		{"Top categories"},
+		{"category", "total", "percentage"},
+	}
+	for _, c := range analytics.TopCategories {
+		records = append(records, []string{c.Category, c.TotalSpent, c.Percentage})
+	}
+
+	records = append(records,
+		[]string{},
+		[]string{"Predictions"},
+		[]string{"category", "likelihood", "predicted date"},
+	)
+	for _, p := range analytics.PredictedSpending {
+		records = append(records, []string{
+			p.Category,
+			strconv.FormatFloat(p.Likelihood, 'f', 2, 64),
+			p.PredictedDate.Format("2006-01-02"),
+		})
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(records); err != nil {
+		return fmt.Errorf("failed to write analytics CSV: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package analytics
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"server/types"
+	"testing"
+	"time"
+)
+
+func TestExportAnalytics(t *testing.T) {
+	analytics := &types.SpendingAnalytics{
+		TopCategories: []types.CategorySpend{
+			{Category: "Food", TotalSpent: "120.00", Percentage: "60.00", Amount: 120, Share: 60},
+			{Category: "Travel, Leisure", TotalSpent: "80.00", Percentage: "40.00", Amount: 80, Share: 40},
+		},
+		PredictedSpending: []types.PredictedSpend{
+			{Category: "Food", Likelihood: 0.854, PredictedDate: time.Date(2025, 4, 20, 12, 0, 0, 0, time.UTC), PredictedAmount: 40, AmountLow: 30, AmountHigh: 50, DaysUntil: 5, Cadence: types.CadenceWeekly},
+		},
+		TotalSpent:     200,
+		MonthlyAverage: 200,
+	}
+
+	tests := []struct {
+		name      string
+		analytics *types.SpendingAnalytics
+		format    string
+		golden    string
+	}{
+		{name: "json", analytics: analytics, format: "json", golden: "export.json"},
+		{name: "csv", analytics: analytics, format: "csv", golden: "export.csv"},
+		{name: "empty json", analytics: nil, format: "json", golden: "export_empty.json"},
+		{name: "empty csv", analytics: &types.SpendingAnalytics{}, format: "csv", golden: "export_empty.csv"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := ExportAnalytics(tt.analytics, tt.format, &buf); err != nil {
+				t.Fatalf("ExportAnalytics() failed: %v", err)
+			}
+			want, err := os.ReadFile(filepath.Join("testdata", tt.golden))
+			if err != nil {
+				t.Fatalf("failed to read golden file: %v", err)
+			}
+			if got := buf.String(); got != string(want) {
+				t.Errorf("ExportAnalytics() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+
+	if err := ExportAnalytics(analytics, "xml", &bytes.Buffer{}); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("ExportAnalytics(xml) error = %v, want ErrUnsupportedFormat", err)
+	}
+}
@@ -0,0 +1,74 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"strings"
+)
+
+// feesCategory is the consolidated category fee-like transactions are reported under
+const feesCategory = "Fees"
+
+// defaultFeeKeywords match the merchant or category text banks typically use
+// for fees and interest
+var defaultFeeKeywords = []string{
+	"fee",
+	"atm",
+	"overdraft",
+	"interest charge",
+	"finance charge",
+	"service charge",
+	"late charge",
+}
+
+// WithFeeKeywords replaces the keywords used to recognise fee transactions.
+// Matching is case-insensitive against the merchant and category.
+func WithFeeKeywords(keywords ...string) Option {
+	return func(s *service) {
+		s.feeKeywords = make([]string, 0, len(keywords))
+		for _, k := range keywords {
+			if k = strings.ToLower(strings.TrimSpace(k)); k != "" {
+				s.feeKeywords = append(s.feeKeywords, k)
+			}
+		}
+	}
+}
+
+// GetFeeSummary consolidates bank fees, ATM fees and interest charges that are
+// scattered across categories into a single Fees total
+func (s *service) GetFeeSummary(ctx context.Context, accountID string, timeRange string) (*types.FeeSummary, error) {
+	transactions, err := s.loadTransactions(ctx, accountID, timeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	summary := &types.FeeSummary{
+		Category:     feesCategory,
+		ByCategory:   make(map[string]float64),
+		Transactions: make([]types.Transaction, 0),
+	}
+	for _, t := range transactions {
+		amount := expenseAmount(t)
+		if amount == 0 || !s.isFee(t) {
+			continue
+		}
+		summary.Total += amount
+		summary.Count++
+		summary.ByCategory[t.Category] += amount
+		summary.Transactions = append(summary.Transactions, t)
+	}
+
+	return summary, nil
+}
+
+// isFee reports whether a transaction looks like a fee or interest charge
+func (s *service) isFee(t types.Transaction) bool {
+	text := strings.ToLower(t.Merchant + " " + t.Category)
+	for _, keyword := range s.feeKeywords {
+		if strings.Contains(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+)
+
+func TestGetFeeSummary(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-03-02", -3.50, "Cash", "ATM Withdrawal Fee"),
+		txn("2025-03-05", -35, "Bill Payment", "Overdraft Fee"),
+		txn("2025-03-10", -18.42, "Credit Card", "Interest Charge"),
+		txn("2025-03-12", -2.99, "Shopping", "FX Markup"),
+		txn("2025-03-14", -120, "Groceries", "Whole Foods"),
+		txn("2025-03-15", 35, "Bill Payment", "Overdraft Fee Reversal"),
+	}}
+
+	tests := []struct {
+		name      string
+		opts      []Option
+		wantTotal float64
+		wantCount int
+	}{
+		{
+			name:      "default keywords",
+			wantTotal: 56.92,
+			wantCount: 3,
+		},
+		{
+			name:      "custom keywords",
+			opts:      []Option{WithFeeKeywords("fee", "interest", "FX")},
+			wantTotal: 59.91,
+			wantCount: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(repo, tt.opts...)
+			got, err := svc.GetFeeSummary(context.Background(), "1234567891", "1 month")
+			if err != nil {
+				t.Fatalf("GetFeeSummary() failed: %v", err)
+			}
+			if !approxEqual(got.Total, tt.wantTotal) || got.Count != tt.wantCount {
+				t.Errorf("GetFeeSummary() total, count = %v, %d, want %v, %d", got.Total, got.Count, tt.wantTotal, tt.wantCount)
+			}
+			if got.Category != "Fees" {
+				t.Errorf("Category = %q, want Fees", got.Category)
+			}
+			if !approxEqual(got.ByCategory["Bill Payment"], 35) {
+				t.Errorf("ByCategory[Bill Payment] = %v, want 35", got.ByCategory["Bill Payment"])
+			}
+		})
+	}
+}
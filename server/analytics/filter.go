@@ -0,0 +1,68 @@
+package analytics
+
+import (
+	"server/types"
+	"strings"
+)
+
+// TransactionFilter narrows the transactions an analysis looks at. Every
+// condition that is set must hold for a transaction to be kept; the zero
+// value keeps everything.
+type TransactionFilter struct {
+	// MinAmount and MaxAmount bound the size of a transaction, whichever way
+	// the money went. Zero means no bound.
+	MinAmount float64
+	MaxAmount float64
+	// Categories keeps only transactions in these categories. Empty means all
+	// of them.
+	Categories []string
+	// ExcludeCategories drops transactions in these categories, e.g. Rent
+	// when it would dominate the totals
+	ExcludeCategories []string
+}
+
+// IsZero reports whether the filter keeps everything
+func (f TransactionFilter) IsZero() bool {
+	return f.MinAmount == 0 && f.MaxAmount == 0 && len(f.Categories) == 0 && len(f.ExcludeCategories) == 0
+}
+
+// Match reports whether a transaction passes every condition of the filter.
+// Categories are compared case-insensitively.
+func (f TransactionFilter) Match(t types.Transaction) bool {
+	size := abs(t.Amount)
+	if f.MinAmount > 0 && size < f.MinAmount {
+		return false
+	}
+	if f.MaxAmount > 0 && size > f.MaxAmount {
+		return false
+	}
+	if len(f.Categories) > 0 && !containsFold(f.Categories, t.Category) {
+		return false
+	}
+	return !containsFold(f.ExcludeCategories, t.Category)
+}
+
+// Apply returns the transactions that match the filter. Unfiltered
+// transactions are returned as they are.
+func (f TransactionFilter) Apply(transactions []types.Transaction) []types.Transaction {
+	if f.IsZero() {
+		return transactions
+	}
+	kept := make([]types.Transaction, 0, len(transactions))
+	for _, t := range transactions {
+		if f.Match(t) {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+// containsFold reports whether values holds s, ignoring case
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), s) {
+			return true
+		}
+	}
+	return false
+}
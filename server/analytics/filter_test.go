@@ -0,0 +1,70 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"testing"
+	"time"
+)
+
+func TestTransactionFilterExcludesCategory(t *testing.T) {
+	var transactions []types.Transaction
+	for month := 1; month <= 3; month++ {
+		transactions = append(transactions,
+			txn(fmt.Sprintf("2025-%02d-01", month), -1800, "Rent", "Landlord"),
+			txn(fmt.Sprintf("2025-%02d-05", month), -60, "Food", "Whole Foods"),
+			txn(fmt.Sprintf("2025-%02d-19", month), -45, "Food", "Whole Foods"),
+		)
+	}
+	svc := NewService(&fakeRepo{transactions: transactions}, fixedClock("2025-03-25"))
+	filter := TransactionFilter{ExcludeCategories: []string{"rent"}}
+
+	got, err := svc.GetSpendingAnalytics(context.Background(), "1234567891", "3 months", AnalyticsOptions{Filter: filter})
+	if err != nil {
+		t.Fatalf("GetSpendingAnalytics() failed: %v", err)
+	}
+	for _, c := range got.Data.TopCategories {
+		if c.Category == "Rent" {
+			t.Errorf("TopCategories = %+v, want Rent excluded", got.Data.TopCategories)
+		}
+	}
+	if !approxEqual(got.Data.TotalSpent, 315) {
+		t.Errorf("TotalSpent = %v, want 315 without rent", got.Data.TotalSpent)
+	}
+	for _, p := range got.Data.PredictedSpending {
+		if p.Category == "Rent" {
+			t.Errorf("PredictedSpending = %+v, want Rent excluded", got.Data.PredictedSpending)
+		}
+	}
+
+	predictions, err := svc.PredictFutureSpending(context.Background(), "1234567891", PredictionOptions{Filter: filter})
+	if err != nil {
+		t.Fatalf("PredictFutureSpending() failed: %v", err)
+	}
+	if len(predictions) != 1 || predictions[0].Category != "Food" {
+		t.Errorf("PredictFutureSpending() = %+v, want only Food", predictions)
+	}
+}
+
+func TestTransactionFilterMinAmountPatterns(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		// Weekday coffees under the threshold
+		txn("2025-03-03", -4.50, "Food", "Starbucks"),
+		txn("2025-03-04", -4.50, "Food", "Starbucks"),
+		txn("2025-03-05", -4.50, "Food", "Starbucks"),
+		// Saturday shopping trips above it
+		txn("2025-03-08", -120, "Shopping", "Target"),
+		txn("2025-03-15", -95, "Shopping", "Target"),
+	}}
+	svc := NewService(repo, fixedClock("2025-03-25"))
+	start, end := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	got, err := svc.AnalyzeTimePatterns(context.Background(), "1234567891", start, end, PatternOptions{Filter: TransactionFilter{MinAmount: 20, Categories: []string{"Shopping", "Food"}}})
+	if err != nil {
+		t.Fatalf("AnalyzeTimePatterns() failed: %v", err)
+	}
+	if len(got) != 1 || got[0].DayOfWeek != "Saturday" || got[0].Frequency != 2 {
+		t.Errorf("AnalyzeTimePatterns() = %+v, want only the two Saturday trips", got)
+	}
+}
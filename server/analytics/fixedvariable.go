@@ -0,0 +1,43 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+)
+
+// GetFixedVariableSplit splits spend in the range into the fixed part locked
+// in by recurring charges, such as rent and subscriptions, and the variable
+// rest, so users can see how much of their outflow is already committed
+func (s *service) GetFixedVariableSplit(ctx context.Context, accountID string, timeRange string) (*types.FixedVariableSplit, error) {
+	transactions, err := s.loadTransactions(ctx, accountID, timeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	// Recurring detection needs a longer history than the window itself
+	history, err := s.loadTransactions(ctx, accountID, "1 year")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction history: %w", err)
+	}
+	isRecurring := recurringSet(s.detectRecurring(history))
+
+	split := &types.FixedVariableSplit{}
+	for _, t := range transactions {
+		amount := expenseAmount(t)
+		if amount == 0 {
+			continue
+		}
+		if isRecurring[keyOf(t)] {
+			split.Fixed += amount
+		} else {
+			split.Variable += amount
+		}
+	}
+	split.Total = split.Fixed + split.Variable
+	if split.Total > 0 {
+		split.FixedRatio = split.Fixed / split.Total
+	}
+
+	return split, nil
+}
@@ -0,0 +1,46 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+)
+
+func TestGetFixedVariableSplit(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-01-01", -1500, "Rent", "Park Avenue Apartments"),
+		txn("2025-02-01", -1500, "Rent", "Park Avenue Apartments"),
+		txn("2025-03-01", -1500, "Rent", "Park Avenue Apartments"),
+		txn("2025-01-05", -15, "Entertainment", "Netflix"),
+		txn("2025-02-05", -15, "Entertainment", "Netflix"),
+		txn("2025-03-05", -15, "Entertainment", "Netflix"),
+		txn("2025-01-18", -240, "Food", "Whole Foods"),
+		txn("2025-02-09", -85, "Food", "Trader Joe's"),
+		txn("2025-03-21", -300, "Shopping", "Target"),
+		txn("2025-03-25", 4000, "Income", "Payroll"),
+	}}
+	svc := NewService(repo, fixedClock("2025-03-28"))
+
+	got, err := svc.GetFixedVariableSplit(context.Background(), "1234567891", "3 months")
+	if err != nil {
+		t.Fatalf("GetFixedVariableSplit() failed: %v", err)
+	}
+
+	if !approxEqual(got.Fixed, 4545) || !approxEqual(got.Variable, 625) || !approxEqual(got.Total, 5170) {
+		t.Errorf("Fixed, Variable, Total = %v, %v, %v, want 4545, 625, 5170", got.Fixed, got.Variable, got.Total)
+	}
+	if !approxEqual(got.FixedRatio, 4545.0/5170) {
+		t.Errorf("FixedRatio = %v, want %v", got.FixedRatio, 4545.0/5170)
+	}
+}
+
+func TestGetFixedVariableSplitNoSpend(t *testing.T) {
+	svc := NewService(&fakeRepo{})
+	got, err := svc.GetFixedVariableSplit(context.Background(), "1234567891", "1 month")
+	if err != nil {
+		t.Fatalf("GetFixedVariableSplit() failed: %v", err)
+	}
+	if got.Total != 0 || got.FixedRatio != 0 {
+		t.Errorf("GetFixedVariableSplit() = %+v, want all zero", got)
+	}
+}
@@ -0,0 +1,192 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"time"
+)
+
+// GetCurrentMonthForecast projects the current month's final spend from the
+// spend so far. Rather than extrapolating linearly, it scales spend-to-date by
+// the share of a month's spend the account has historically made by this point
+// in the month, so charges that land late in the month (rent, card payments)
+// are accounted for. The history is the last 12 complete months.
+func (s *service) GetCurrentMonthForecast(ctx context.Context, accountID string) (*types.MonthForecast, error) {
+	now := s.now()
+	start := monthStart(now)
+	lookback := start.AddDate(0, -12, 0)
+	transactions, err := s.repo.GetTransactions(ctx, accountID, types.DateRange{Start: lookback, End: now})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	daysInMonth := start.AddDate(0, 1, -1).Day()
+	dayOfMonth := now.Day()
+	// Today counts as elapsed
+	elapsed := float64(dayOfMonth) / float64(daysInMonth)
+
+	// Spend to date this month, plus per-month totals and the portion of each
+	// past month's spend that happened by the same fraction of that month, so
+	// months of different lengths line up
+	var spentToDate float64
+	type monthSpend struct {
+		total      float64
+		throughDay float64
+	}
+	history := make(map[time.Time]*monthSpend)
+	for _, t := range transactions {
+		amount := expenseAmount(t)
+		if amount == 0 {
+			continue
+		}
+		date := t.Date.In(now.Location())
+		if date.Before(lookback) {
+			continue
+		}
+		if !date.Before(start) {
+			if !date.After(now) {
+				spentToDate += amount
+			}
+			continue
+		}
+
+		key := monthStart(date)
+		m, exists := history[key]
+		if !exists {
+			m = &monthSpend{}
+			history[key] = m
+		}
+		m.total += amount
+		// A day's spend is spread across that day, so the day the fraction
+		// lands in counts in part
+		through := elapsed * float64(key.AddDate(0, 1, -1).Day())
+		switch d := float64(date.Day()); {
+		case d <= through:
+			m.throughDay += amount
+		case d-1 < through:
+			m.throughDay += amount * (through - (d - 1))
+		}
+	}
+
+	linear := spentToDate * float64(daysInMonth) / float64(dayOfMonth)
+
+	// Pool the history so one unusual month doesn't swing the curve
+	var pooledTotal, pooledThroughDay float64
+	for _, m := range history {
+		pooledTotal += m.total
+		pooledThroughDay += m.throughDay
+	}
+
+	projected := linear
+	if pooledTotal > 0 && pooledThroughDay > 0 {
+		projected = spentToDate * pooledTotal / pooledThroughDay
+	}
+
+	// Known one-off expenses still to come this month sit on top of the curve
+	planned := s.plannedBetween(accountID, now, start.AddDate(0, 1, 0))
+	projected += planned
+
+	return &types.MonthForecast{
+		Year:             now.Year(),
+		Month:            now.Month(),
+		AsOf:             now,
+		DaysElapsed:      dayOfMonth,
+		DaysInMonth:      daysInMonth,
+		SpentToDate:      spentToDate,
+		LinearProjection: linear,
+		Projected:        projected,
+		Planned:          planned,
+		HistoricalMonths: len(history),
+	}, nil
+}
+
+// GetNextMonthForecast projects next month's total spend: the account's
+// typical variable spend per month, its active recurring charges at their
+// monthly cost, and any planned expenses registered for that month
+func (s *service) GetNextMonthForecast(ctx context.Context, accountID string) (*types.MonthForecast, error) {
+	transactions, err := s.loadTransactions(ctx, accountID, "1 year")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	now := s.now()
+	thisMonth := monthStart(now)
+	nextMonth := thisMonth.AddDate(0, 1, 0)
+
+	recurring := activeRecurring(s.detectRecurring(transactions), now)
+	isRecurring := recurringSet(recurring)
+
+	// Average variable spend over the complete months we have data for
+	var variable float64
+	months := make(map[time.Time]bool)
+	for _, t := range transactions {
+		date := t.Date.In(now.Location())
+		if !date.Before(thisMonth) {
+			continue
+		}
+		months[monthStart(date)] = true
+		if !isRecurring[keyOf(t)] {
+			variable += expenseAmount(t)
+		}
+	}
+
+	var baseline float64
+	if len(months) > 0 {
+		baseline = variable / float64(len(months))
+	}
+	for _, g := range recurring {
+		baseline += g.charge.MonthlyAmount
+	}
+
+	planned := s.plannedBetween(accountID, nextMonth, nextMonth.AddDate(0, 1, 0))
+
+	return &types.MonthForecast{
+		Year:             nextMonth.Year(),
+		Month:            nextMonth.Month(),
+		AsOf:             now,
+		DaysInMonth:      nextMonth.AddDate(0, 1, -1).Day(),
+		LinearProjection: baseline,
+		Projected:        baseline + planned,
+		Planned:          planned,
+		HistoricalMonths: len(months),
+	}, nil
+}
+
+// RegisterPlannedExpense records a known upcoming one-off expense so that
+// forecasts covering its date include it
+func (s *service) RegisterPlannedExpense(ctx context.Context, accountID string, expense types.PlannedExpense) error {
+	if accountID == "" {
+		return fmt.Errorf("account ID is required")
+	}
+	if expense.Amount <= 0 {
+		return fmt.Errorf("planned expense amount must be positive")
+	}
+	if expense.Date.Before(s.now()) {
+		return fmt.Errorf("planned expense date %s is in the past", expense.Date.Format("2006-01-02"))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.planned[accountID] = append(s.planned[accountID], expense)
+	return nil
+}
+
+// plannedBetween totals the planned expenses dated in [from, to)
+func (s *service) plannedBetween(accountID string, from, to time.Time) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var total float64
+	for _, e := range s.planned[accountID] {
+		if !e.Date.Before(from) && e.Date.Before(to) {
+			total += e.Amount
+		}
+	}
+	return total
+}
+
+// monthStart returns midnight on the first day of t's month, in t's location
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,123 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+	"time"
+)
+
+func TestGetCurrentMonthForecast(t *testing.T) {
+	var transactions []types.Transaction
+	for _, month := range []string{"2025-01", "2025-02", "2025-03"} {
+		transactions = append(transactions,
+			txn(month+"-05", -100, "Groceries", "Whole Foods"),
+			txn(month+"-12", -100, "Groceries", "Whole Foods"),
+			txn(month+"-28", -1000, "Rent", "Park Avenue Apartments"),
+		)
+	}
+	transactions = append(transactions,
+		txn("2025-04-05", -100, "Groceries", "Whole Foods"),
+		txn("2025-04-12", -100, "Groceries", "Whole Foods"),
+		txn("2025-04-10", 2500, "Income", "Tech Corp Inc"),
+	)
+
+	svc := NewService(&fakeRepo{transactions: transactions}, fixedClock("2025-04-15"))
+	got, err := svc.GetCurrentMonthForecast(context.Background(), "1234567891")
+	if err != nil {
+		t.Fatalf("GetCurrentMonthForecast() failed: %v", err)
+	}
+
+	if !approxEqual(got.SpentToDate, 200) {
+		t.Errorf("SpentToDate = %v, want 200", got.SpentToDate)
+	}
+	if !approxEqual(got.LinearProjection, 400) {
+		t.Errorf("LinearProjection = %v, want 400", got.LinearProjection)
+	}
+	// Rent always lands on the 28th, so by the 15th only a sixth of the month is spent
+	if !approxEqual(got.Projected, 1200) {
+		t.Errorf("Projected = %v, want 1200", got.Projected)
+	}
+	if got.Projected <= got.LinearProjection {
+		t.Errorf("Projected = %v, want more than linear projection %v", got.Projected, got.LinearProjection)
+	}
+	if got.HistoricalMonths != 3 {
+		t.Errorf("HistoricalMonths = %d, want 3", got.HistoricalMonths)
+	}
+}
+
+func TestGetCurrentMonthForecastFlatSpend(t *testing.T) {
+	// 10 a day, every day, for well over a year
+	var transactions []types.Transaction
+	for date := txn("2024-01-01", 0, "", "").Date; !date.After(txn("2025-04-15", 0, "", "").Date); date = date.AddDate(0, 0, 1) {
+		tx := txn("2024-01-01", -10, "Groceries", "Whole Foods")
+		tx.Date = date
+		transactions = append(transactions, tx)
+	}
+
+	repo := &fakeRepo{transactions: transactions}
+	svc := NewService(repo, fixedClock("2025-04-15"))
+	got, err := svc.GetCurrentMonthForecast(context.Background(), "1234567891")
+	if err != nil {
+		t.Fatalf("GetCurrentMonthForecast() failed: %v", err)
+	}
+
+	if !approxEqual(got.Projected, 300) || !approxEqual(got.Projected, got.LinearProjection) {
+		t.Errorf("Projected = %v, want the linear projection 300 (LinearProjection = %v)", got.Projected, got.LinearProjection)
+	}
+	if got.HistoricalMonths != 12 {
+		t.Errorf("HistoricalMonths = %d, want 12", got.HistoricalMonths)
+	}
+	want := types.DateRange{Start: time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC), End: txn("2025-04-15", 0, "", "").Date}
+	if len(repo.ranges) != 1 || !repo.ranges[0].Start.Equal(want.Start) || !repo.ranges[0].End.Equal(want.End) {
+		t.Errorf("ranges = %v, want %v", repo.ranges, want)
+	}
+}
+
+func TestGetNextMonthForecastIncludesPlannedExpense(t *testing.T) {
+	var transactions []types.Transaction
+	for _, month := range []string{"2025-01", "2025-02", "2025-03"} {
+		transactions = append(transactions,
+			txn(month+"-01", -1000, "Rent", "Park Avenue Apartments"),
+			txn(month+"-14", -300, "Groceries", "Whole Foods"),
+		)
+	}
+	repo := &fakeRepo{transactions: transactions}
+	svc := NewService(repo, fixedClock("2025-04-10"))
+	ctx := context.Background()
+
+	before, err := svc.GetNextMonthForecast(ctx, "1234567891")
+	if err != nil {
+		t.Fatalf("GetNextMonthForecast() failed: %v", err)
+	}
+	if !approxEqual(before.Projected, 1300) {
+		t.Errorf("Projected before planning = %v, want 1300", before.Projected)
+	}
+
+	flight := types.PlannedExpense{
+		Description: "Flight to Lisbon",
+		Category:    "Travel",
+		Amount:      1200,
+		Date:        time.Date(2025, 5, 20, 0, 0, 0, 0, time.UTC),
+	}
+	if err := svc.RegisterPlannedExpense(ctx, "1234567891", flight); err != nil {
+		t.Fatalf("RegisterPlannedExpense() failed: %v", err)
+	}
+
+	after, err := svc.GetNextMonthForecast(ctx, "1234567891")
+	if err != nil {
+		t.Fatalf("GetNextMonthForecast() failed: %v", err)
+	}
+	if after.Month != time.May {
+		t.Errorf("Month = %v, want May", after.Month)
+	}
+	if !approxEqual(after.Planned, 1200) || !approxEqual(after.Projected, before.Projected+1200) {
+		t.Errorf("Planned, Projected = %v, %v, want 1200, %v", after.Planned, after.Projected, before.Projected+1200)
+	}
+
+	// A planned expense for another account doesn't leak in
+	other, _ := svc.GetNextMonthForecast(ctx, "9999999999")
+	if other.Planned != 0 {
+		t.Errorf("Planned for other account = %v, want 0", other.Planned)
+	}
+}
@@ -0,0 +1,101 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"sort"
+	"strings"
+)
+
+const (
+	// foreignFeeWindow is how long after a purchase its foreign fee may post
+	foreignFeeWindow = 3 * day
+	// maxForeignFeeRate is the largest fee, as a fraction of the purchase,
+	// still taken to belong to it; card networks charge around 1-3%
+	maxForeignFeeRate = 0.05
+)
+
+// foreignFeeKeywords match the merchant or category text banks use for
+// foreign-transaction fees
+var foreignFeeKeywords = []string{
+	"foreign transaction",
+	"foreign fee",
+	"foreign exchange fee",
+	"international transaction",
+	"intl transaction",
+	"intl fee",
+	"fx fee",
+	"currency conversion",
+}
+
+// GetForeignFeeSummary totals the foreign-transaction fees paid over the range
+// and links each to the cross-border purchase it was charged on: the closest
+// earlier expense at the same merchant within a few days that the fee is a
+// small fraction of. Highest fees first.
+func (s *service) GetForeignFeeSummary(ctx context.Context, accountID string, timeRange string) (*types.ForeignFeeSummary, error) {
+	transactions, err := s.loadTransactions(ctx, accountID, timeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	var fees, purchases []types.Transaction
+	for _, t := range transactions {
+		if expenseAmount(t) == 0 {
+			continue
+		}
+		if isForeignFee(t) {
+			fees = append(fees, t)
+		} else {
+			purchases = append(purchases, t)
+		}
+	}
+
+	summary := &types.ForeignFeeSummary{Fees: make([]types.ForeignFee, 0, len(fees))}
+	for _, fee := range fees {
+		amount := expenseAmount(fee)
+		summary.Total += amount
+		summary.Count++
+
+		match := types.ForeignFee{Fee: fee}
+		for i, p := range purchases {
+			gap := fee.Date.Sub(p.Date)
+			if merchantKey(p.Merchant) != merchantKey(fee.Merchant) || gap < 0 || gap > foreignFeeWindow {
+				continue
+			}
+			if amount > expenseAmount(p)*maxForeignFeeRate {
+				continue
+			}
+			if match.Purchase == nil || p.Date.After(match.Purchase.Date) {
+				match.Purchase = &purchases[i]
+			}
+		}
+		if match.Purchase != nil {
+			match.Rate = amount / expenseAmount(*match.Purchase)
+		} else {
+			summary.Unmatched++
+		}
+		summary.Fees = append(summary.Fees, match)
+	}
+
+	sort.Slice(summary.Fees, func(i, j int) bool {
+		ai, aj := expenseAmount(summary.Fees[i].Fee), expenseAmount(summary.Fees[j].Fee)
+		if ai == aj {
+			return summary.Fees[i].Fee.Date.Before(summary.Fees[j].Fee.Date)
+		}
+		return ai > aj
+	})
+
+	return summary, nil
+}
+
+// isForeignFee reports whether a transaction looks like a foreign-transaction fee
+func isForeignFee(t types.Transaction) bool {
+	text := strings.ToLower(t.Merchant + " " + t.Category)
+	for _, keyword := range foreignFeeKeywords {
+		if strings.Contains(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
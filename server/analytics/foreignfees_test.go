@@ -0,0 +1,39 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+)
+
+func TestGetForeignFeeSummary(t *testing.T) {
+	purchase := txn("2025-03-04", -200, "Travel", "Hotel Lutetia Paris")
+	repo := &fakeRepo{transactions: []types.Transaction{
+		purchase,
+		txn("2025-03-05", -6, "Foreign Transaction Fee", "Hotel Lutetia Paris"),
+		txn("2025-03-06", -40, "Food", "Cafe de Flore"),
+		// Same merchant, but too long after to be the fee on that meal
+		txn("2025-03-20", -1.20, "Foreign Transaction Fee", "Cafe de Flore"),
+		txn("2025-03-10", -84.20, "Food", "Whole Foods"),
+	}}
+	svc := NewService(repo)
+
+	got, err := svc.GetForeignFeeSummary(context.Background(), "1234567891", "1 month")
+	if err != nil {
+		t.Fatalf("GetForeignFeeSummary() failed: %v", err)
+	}
+
+	if got.Count != 2 || !approxEqual(got.Total, 7.20) || got.Unmatched != 1 {
+		t.Errorf("Count, Total, Unmatched = %d, %v, %d, want 2, 7.20, 1", got.Count, got.Total, got.Unmatched)
+	}
+	hotel := got.Fees[0]
+	if hotel.Purchase == nil || hotel.Purchase.Merchant != purchase.Merchant || !hotel.Purchase.Date.Equal(purchase.Date) {
+		t.Fatalf("hotel fee purchase = %+v, want the hotel stay", hotel.Purchase)
+	}
+	if !approxEqual(hotel.Rate, 0.03) {
+		t.Errorf("hotel fee Rate = %v, want 0.03", hotel.Rate)
+	}
+	if got.Fees[1].Purchase != nil {
+		t.Errorf("cafe fee purchase = %+v, want none", got.Fees[1].Purchase)
+	}
+}
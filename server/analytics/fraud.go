@@ -0,0 +1,154 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"sort"
+	"time"
+)
+
+// fraudSignalWeights sum to 1, so a transaction tripping every signal scores 1
+var fraudSignalWeights = map[types.FraudSignal]float64{
+	types.FraudSignalUnusualAmount: 0.4,
+	types.FraudSignalNewMerchant:   0.3,
+	types.FraudSignalUnusualTime:   0.3,
+}
+
+const (
+	// minAmountBaseline is how many earlier charges in a category we need
+	// before calling an amount unusual
+	minAmountBaseline = 3
+	// unusualAmountDeviations is how many standard deviations above the
+	// category mean an amount must be to count as unusual
+	unusualAmountDeviations = 3
+	// minTimeBaseline is how many earlier charges we need before calling a
+	// time of day unusual
+	minTimeBaseline = 10
+	// unusualTimeWindow is how close to an earlier charge's time of day a
+	// charge must be to count as habitual
+	unusualTimeWindow = time.Hour
+)
+
+// ScoreFraudRisk scores each expense in the range by how many fraud signals it
+// trips: an amount far outside its category's history, a merchant never seen
+// before and a time of day the account doesn't normally spend at. Each
+// transaction is judged against the year of history before it. Riskiest first.
+func (s *service) ScoreFraudRisk(ctx context.Context, accountID string, timeRange string) ([]types.FraudScore, error) {
+	transactions, err := s.loadTransactions(ctx, accountID, timeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+	history, err := s.loadTransactions(ctx, accountID, "1 year")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction history: %w", err)
+	}
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].Date.Before(history[j].Date)
+	})
+
+	scores := make([]types.FraudScore, 0)
+	for _, t := range transactions {
+		if expenseAmount(t) == 0 {
+			continue
+		}
+		baseline := expensesBefore(history, t.Date)
+
+		score := types.FraudScore{Transaction: t, Signals: make([]types.FraudSignal, 0)}
+		if isUnusualAmount(t, baseline) {
+			score.Signals = append(score.Signals, types.FraudSignalUnusualAmount)
+		}
+		if isNewMerchant(t, baseline) {
+			score.Signals = append(score.Signals, types.FraudSignalNewMerchant)
+		}
+		if isUnusualTime(t, baseline) {
+			score.Signals = append(score.Signals, types.FraudSignalUnusualTime)
+		}
+		for _, signal := range score.Signals {
+			score.Score += fraudSignalWeights[signal]
+		}
+		scores = append(scores, score)
+	}
+
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score == scores[j].Score {
+			return scores[i].Transaction.Date.After(scores[j].Transaction.Date)
+		}
+		return scores[i].Score > scores[j].Score
+	})
+
+	return scores, nil
+}
+
+// expensesBefore returns the expenses in date-sorted history strictly before t
+func expensesBefore(history []types.Transaction, t time.Time) []types.Transaction {
+	n := sort.Search(len(history), func(i int) bool {
+		return !history[i].Date.Before(t)
+	})
+	expenses := make([]types.Transaction, 0, n)
+	for _, h := range history[:n] {
+		if expenseAmount(h) > 0 {
+			expenses = append(expenses, h)
+		}
+	}
+	return expenses
+}
+
+// isUnusualAmount reports whether t is far above what the account normally
+// spends in its category
+func isUnusualAmount(t types.Transaction, baseline []types.Transaction) bool {
+	var amounts []float64
+	for _, b := range baseline {
+		if b.Category == t.Category {
+			amounts = append(amounts, expenseAmount(b))
+		}
+	}
+	if len(amounts) < minAmountBaseline {
+		return false
+	}
+	m, sd := mean(amounts), stddev(amounts)
+	if sd == 0 {
+		// Identical past charges: anything well above them stands out
+		return expenseAmount(t) > 2*m
+	}
+	return expenseAmount(t) > m+unusualAmountDeviations*sd
+}
+
+// isNewMerchant reports whether the account has never paid t's merchant before
+func isNewMerchant(t types.Transaction, baseline []types.Transaction) bool {
+	key := merchantKey(t.Merchant)
+	for _, b := range baseline {
+		if merchantKey(b.Merchant) == key {
+			return false
+		}
+	}
+	return true
+}
+
+// isUnusualTime reports whether t falls at a time of day the account has no
+// history of spending at
+func isUnusualTime(t types.Transaction, baseline []types.Transaction) bool {
+	if len(baseline) < minTimeBaseline {
+		return false
+	}
+	for _, b := range baseline {
+		if timeOfDayDistance(t.Date, b.Date) <= unusualTimeWindow {
+			return false
+		}
+	}
+	return true
+}
+
+// timeOfDayDistance is the gap between two clock times, ignoring the date and
+// wrapping around midnight
+func timeOfDayDistance(a, b time.Time) time.Duration {
+	clock := func(t time.Time) time.Duration {
+		h, m, sec := t.Clock()
+		return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(sec)*time.Second
+	}
+	d := absDuration(clock(a) - clock(b))
+	if d > 12*time.Hour {
+		d = 24*time.Hour - d
+	}
+	return d
+}
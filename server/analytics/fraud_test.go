@@ -0,0 +1,51 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+	"time"
+)
+
+func TestScoreFraudRisk(t *testing.T) {
+	var transactions []types.Transaction
+	for _, date := range []string{
+		"2025-01-04", "2025-01-11", "2025-01-18", "2025-01-25",
+		"2025-02-01", "2025-02-08", "2025-02-15", "2025-02-22",
+		"2025-03-01", "2025-03-08", "2025-03-15", "2025-03-22",
+	} {
+		transactions = append(transactions, txn(date, -85, "Food", "Whole Foods"))
+	}
+
+	// Tripping all three signals: new merchant, huge amount, 3am
+	allSignals := txn("2025-03-28", -1900, "Food", "Unknown Market")
+	allSignals.Date = allSignals.Date.Add(-9 * time.Hour)
+	// Tripping only the new-merchant signal
+	oneSignal := txn("2025-03-27", -82, "Food", "Trader Joe's")
+	transactions = append(transactions, allSignals, oneSignal)
+
+	svc := NewService(&fakeRepo{transactions: transactions})
+	got, err := svc.ScoreFraudRisk(context.Background(), "1234567891", "1 month")
+	if err != nil {
+		t.Fatalf("ScoreFraudRisk() failed: %v", err)
+	}
+
+	scores := make(map[string]types.FraudScore)
+	for _, s := range got {
+		scores[s.Transaction.Merchant] = s
+	}
+	high, low := scores["Unknown Market"], scores["Trader Joe's"]
+
+	if len(high.Signals) != 3 {
+		t.Errorf("all-signal transaction tripped %v, want 3 signals", high.Signals)
+	}
+	if len(low.Signals) != 1 || low.Signals[0] != types.FraudSignalNewMerchant {
+		t.Errorf("one-signal transaction tripped %v, want [%s]", low.Signals, types.FraudSignalNewMerchant)
+	}
+	if high.Score <= low.Score {
+		t.Errorf("all-signal score %v should exceed one-signal score %v", high.Score, low.Score)
+	}
+	if got[0].Transaction.Merchant != "Unknown Market" {
+		t.Errorf("riskiest transaction = %s, want Unknown Market", got[0].Transaction.Merchant)
+	}
+}
@@ -0,0 +1,26 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+)
+
+// GetSpendingGini measures how evenly spending is spread across the
+// transactions in the range, as the Gini coefficient of expense amounts: 0
+// when every purchase is the same size, approaching 1 when a few large
+// purchases make up most of the spend
+func (s *service) GetSpendingGini(ctx context.Context, accountID string, timeRange string) (float64, error) {
+	transactions, err := s.loadTransactions(ctx, accountID, timeRange)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	amounts := make([]float64, 0, len(transactions))
+	for _, t := range transactions {
+		if amount := expenseAmount(t); amount > 0 {
+			amounts = append(amounts, amount)
+		}
+	}
+
+	return gini(amounts), nil
+}
@@ -0,0 +1,59 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+)
+
+func TestGetSpendingGini(t *testing.T) {
+	tests := []struct {
+		name         string
+		transactions []types.Transaction
+		wantMin      float64
+		wantMax      float64
+	}{
+		{
+			name: "even spread",
+			transactions: []types.Transaction{
+				txn("2025-03-01", -50, "Food", "Whole Foods"),
+				txn("2025-03-08", -50, "Food", "Whole Foods"),
+				txn("2025-03-15", -50, "Food", "Whole Foods"),
+				txn("2025-03-22", -50, "Food", "Whole Foods"),
+				txn("2025-03-23", 2000, "Income", "Payroll"),
+			},
+			wantMin: 0,
+			wantMax: 0.01,
+		},
+		{
+			name: "one large purchase dominates",
+			transactions: []types.Transaction{
+				txn("2025-03-01", -5, "Food", "Starbucks"),
+				txn("2025-03-02", -5, "Food", "Starbucks"),
+				txn("2025-03-03", -5, "Food", "Starbucks"),
+				txn("2025-03-04", -5, "Food", "Starbucks"),
+				txn("2025-03-10", -2400, "Travel", "Delta"),
+			},
+			wantMin: 0.75,
+			wantMax: 1,
+		},
+		{
+			name:         "no spending",
+			transactions: nil,
+			wantMin:      0,
+			wantMax:      0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(&fakeRepo{transactions: tt.transactions})
+			got, err := svc.GetSpendingGini(context.Background(), "1234567891", "1 month")
+			if err != nil {
+				t.Fatalf("GetSpendingGini() failed: %v", err)
+			}
+			if got < tt.wantMin || got > tt.wantMax {
+				t.Errorf("GetSpendingGini() = %v, want between %v and %v", got, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"server/types"
+	"time"
+)
+
+// TrackGoal measures progress towards a savings goal from the account's net
+// cash flow since the goal started, and whether saving at the same monthly
+// pace until the deadline reaches the target. A goal whose deadline has
+// passed is completed or missed by what was saved by then. With less than a
+// month to go, the whole remaining amount is required this month.
+func (s *service) TrackGoal(ctx context.Context, accountID string, goal types.SavingsGoal) (*types.GoalProgress, error) {
+	if goal.Target <= 0 {
+		return nil, fmt.Errorf("savings target must be positive, got %v", goal.Target)
+	}
+	if goal.Deadline.IsZero() {
+		return nil, fmt.Errorf("savings goal needs a deadline")
+	}
+	if goal.Start.IsZero() {
+		goal.Start = time.Date(goal.Deadline.Year(), time.January, 1, 0, 0, 0, 0, goal.Deadline.Location())
+	}
+
+	now := s.now()
+	end := now
+	if goal.Deadline.Before(end) {
+		end = goal.Deadline
+	}
+	progress := &types.GoalProgress{Goal: goal}
+	if goal.Start.Before(end) {
+		transactions, err := s.repo.GetTransactions(ctx, accountID, types.DateRange{Start: goal.Start, End: end})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get transactions: %w", err)
+		}
+		progress.Saved = s.cashFlow(transactions).Net
+		// A goal only just started saves at its first month's rate
+		elapsed := math.Max(spanMonths(types.DateRange{Start: goal.Start, End: end}), 1)
+		progress.MonthlyPace = progress.Saved / elapsed
+	}
+	progress.Remaining = math.Max(goal.Target-progress.Saved, 0)
+
+	if !goal.Deadline.After(now) {
+		progress.Projected = progress.Saved
+		progress.Surplus = progress.Saved - goal.Target
+		progress.OnTrack = progress.Remaining == 0
+		progress.Status = types.GoalMissed
+		if progress.OnTrack {
+			progress.Status = types.GoalCompleted
+		}
+		return progress, nil
+	}
+
+	progress.MonthsRemaining = spanMonths(types.DateRange{Start: now, End: goal.Deadline})
+	progress.RequiredMonthly = progress.Remaining
+	if progress.MonthsRemaining >= 1 {
+		progress.RequiredMonthly = progress.Remaining / progress.MonthsRemaining
+	}
+	progress.Projected = progress.Saved + progress.MonthlyPace*progress.MonthsRemaining
+	progress.Surplus = progress.Projected - goal.Target
+	progress.OnTrack = progress.Surplus >= 0
+	switch {
+	case progress.Remaining == 0:
+		progress.Status = types.GoalCompleted
+	case progress.OnTrack:
+		progress.Status = types.GoalOnTrack
+	default:
+		progress.Status = types.GoalBehind
+	}
+
+	return progress, nil
+}
@@ -0,0 +1,101 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"server/types"
+	"testing"
+	"time"
+)
+
+// goalHistory pays 3000 in and spends 2500 each month from January to March
+func goalHistory() []types.Transaction {
+	var transactions []types.Transaction
+	for month := 1; month <= 3; month++ {
+		transactions = append(transactions,
+			txn(fmt.Sprintf("2025-%02d-01", month), 3000, "Income", "Employer"),
+			txn(fmt.Sprintf("2025-%02d-05", month), -1800, "Rent", "Landlord"),
+			txn(fmt.Sprintf("2025-%02d-15", month), -700, "Food", "Whole Foods"),
+		)
+	}
+	return transactions
+}
+
+func TestTrackGoal(t *testing.T) {
+	svc := NewService(&fakeRepo{transactions: goalHistory()}, fixedClock("2025-04-01"))
+	deadline := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tt := range []struct {
+		target float64
+		status types.GoalStatus
+	}{
+		{target: 5000, status: types.GoalOnTrack},
+		{target: 8000, status: types.GoalBehind},
+		{target: 1200, status: types.GoalCompleted},
+	} {
+		t.Run(string(tt.status), func(t *testing.T) {
+			got, err := svc.TrackGoal(context.Background(), "1234567891", types.SavingsGoal{Target: tt.target, Deadline: deadline})
+			if err != nil {
+				t.Fatalf("TrackGoal() failed: %v", err)
+			}
+			if !approxEqual(got.Saved, 1500) {
+				t.Errorf("Saved = %v, want the 1500 net since January", got.Saved)
+			}
+			if got.Status != tt.status || got.OnTrack != (tt.status != types.GoalBehind) {
+				t.Errorf("TrackGoal() = %s (on track %v), want %s", got.Status, got.OnTrack, tt.status)
+			}
+			if !approxEqual(got.RequiredMonthly*got.MonthsRemaining, got.Remaining) {
+				t.Errorf("RequiredMonthly = %v over %v months, want %v in total", got.RequiredMonthly, got.MonthsRemaining, got.Remaining)
+			}
+			if !approxEqual(got.Surplus, got.Projected-tt.target) {
+				t.Errorf("Surplus = %v, want Projected %v less the target", got.Surplus, got.Projected)
+			}
+		})
+	}
+}
+
+func TestTrackGoalDeadlinePassed(t *testing.T) {
+	svc := NewService(&fakeRepo{transactions: goalHistory()}, fixedClock("2025-06-01"))
+	deadline := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tt := range []struct {
+		target float64
+		status types.GoalStatus
+	}{
+		{target: 1000, status: types.GoalCompleted},
+		{target: 2000, status: types.GoalMissed},
+	} {
+		got, err := svc.TrackGoal(context.Background(), "1234567891", types.SavingsGoal{Target: tt.target, Deadline: deadline})
+		if err != nil {
+			t.Fatalf("TrackGoal() failed: %v", err)
+		}
+		if got.Status != tt.status || got.MonthsRemaining != 0 || got.RequiredMonthly != 0 {
+			t.Errorf("TrackGoal(%v) = %+v, want %s with nothing left to save for", tt.target, got, tt.status)
+		}
+		if !approxEqual(got.Surplus, 1500-tt.target) {
+			t.Errorf("TrackGoal(%v) Surplus = %v, want %v", tt.target, got.Surplus, 1500-tt.target)
+		}
+	}
+}
+
+func TestTrackGoalDeadlineThisMonth(t *testing.T) {
+	svc := NewService(&fakeRepo{transactions: goalHistory()}, fixedClock("2025-04-01"))
+
+	got, err := svc.TrackGoal(context.Background(), "1234567891", types.SavingsGoal{
+		Target:   2000,
+		Start:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Deadline: time.Date(2025, 4, 1, 18, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("TrackGoal() failed: %v", err)
+	}
+	for name, v := range map[string]float64{"RequiredMonthly": got.RequiredMonthly, "Projected": got.Projected, "Surplus": got.Surplus} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			t.Errorf("%s = %v, want a finite number", name, v)
+		}
+	}
+	if !approxEqual(got.RequiredMonthly, 500) || got.Status != types.GoalBehind {
+		t.Errorf("TrackGoal() = %+v, want the remaining 500 required now and the goal behind", got)
+	}
+}
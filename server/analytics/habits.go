@@ -0,0 +1,47 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrNoRecurringCharge is returned when a merchant has no recurring charge to
+// project
+var ErrNoRecurringCharge = errors.New("no recurring charge at merchant")
+
+// ProjectHabitCost projects what a recurring charge would grow to if its
+// monthly cost were invested instead for the given number of years at an
+// assumed annual return (0.07 for 7%), compounded monthly with each month's
+// contribution made at the end of the month
+func (s *service) ProjectHabitCost(ctx context.Context, accountID string, merchant string, years int, annualReturn float64) (float64, error) {
+	if years <= 0 {
+		return 0, fmt.Errorf("years must be positive, got %d", years)
+	}
+	if annualReturn <= -1 {
+		return 0, fmt.Errorf("annual return must be above -100%%, got %v", annualReturn)
+	}
+
+	history, err := s.loadTransactions(ctx, accountID, "1 year")
+	if err != nil {
+		return 0, fmt.Errorf("failed to get transaction history: %w", err)
+	}
+
+	key := merchantKey(merchant)
+	for _, g := range s.detectRecurring(history) {
+		if merchantKey(g.charge.Merchant) == key {
+			return futureValueOfAnnuity(g.charge.MonthlyAmount, annualReturn/12, years*12), nil
+		}
+	}
+	return 0, fmt.Errorf("%s: %w", merchant, ErrNoRecurringCharge)
+}
+
+// futureValueOfAnnuity returns what a payment made at the end of each of
+// periods periods grows to at rate per period
+func futureValueOfAnnuity(payment, rate float64, periods int) float64 {
+	if rate == 0 {
+		return payment * float64(periods)
+	}
+	return payment * (math.Pow(1+rate, float64(periods)) - 1) / rate
+}
@@ -0,0 +1,43 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"server/types"
+	"testing"
+)
+
+func TestProjectHabitCost(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-01-05", -100, "Fitness", "Equinox"),
+		txn("2025-02-05", -100, "Fitness", "Equinox"),
+		txn("2025-03-05", -100, "Fitness", "Equinox"),
+		txn("2025-02-14", -80, "Dining", "Le Bernardin"),
+	}}
+	svc := NewService(repo, fixedClock("2025-03-20"))
+
+	tests := []struct {
+		name         string
+		annualReturn float64
+		want         float64
+	}{
+		// 100 * ((1 + 0.06/12)^120 - 1) / (0.06/12)
+		{name: "6% for 10 years", annualReturn: 0.06, want: 16387.93},
+		{name: "no return", annualReturn: 0, want: 12000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.ProjectHabitCost(context.Background(), "1234567891", "equinox", 10, tt.annualReturn)
+			if err != nil {
+				t.Fatalf("ProjectHabitCost() failed: %v", err)
+			}
+			if !approxEqual(got, tt.want) {
+				t.Errorf("ProjectHabitCost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := svc.ProjectHabitCost(context.Background(), "1234567891", "Le Bernardin", 10, 0.06); !errors.Is(err, ErrNoRecurringCharge) {
+		t.Errorf("ProjectHabitCost() for a one-off error = %v, want ErrNoRecurringCharge", err)
+	}
+}
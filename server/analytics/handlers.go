@@ -2,7 +2,11 @@ package analytics
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"server/types"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -40,14 +44,63 @@ func (h *Handler) handleAnalytics(w http.ResponseWriter, r *http.Request) {
 		timeRange = "1 month" // Default time range
 	}
 
-	analytics, err := h.service.GetSpendingAnalytics(r.Context(), accountID, timeRange)
+	opts := AnalyticsOptions{
+		TopN:    DefaultTopN,
+		Ranking: Ranking(r.URL.Query().Get("rank")),
+		Splits:  SplitMode(r.URL.Query().Get("splits")),
+	}
+	if top := r.URL.Query().Get("top"); top != "" {
+		parsed, err := strconv.Atoi(top)
+		if err != nil || parsed < 0 {
+			http.Error(w, "top must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		opts.TopN = parsed
+	}
+	if order := r.URL.Query().Get("order"); order != "" {
+		opts.CategoryOrder = strings.Split(order, ",")
+	}
+	if includeIncome := r.URL.Query().Get("includeIncome"); includeIncome != "" {
+		parsed, err := strconv.ParseBool(includeIncome)
+		if err != nil {
+			http.Error(w, "includeIncome must be true or false", http.StatusBadRequest)
+			return
+		}
+		opts.IncludeIncome = parsed
+	}
+	if includeHistory := r.URL.Query().Get("includeHistory"); includeHistory != "" {
+		parsed, err := strconv.ParseBool(includeHistory)
+		if err != nil {
+			http.Error(w, "includeHistory must be true or false", http.StatusBadRequest)
+			return
+		}
+		opts.IncludeHistory = parsed
+	}
+	// An explicit start and end replace the relative time range. Both days
+	// are included, so the window runs to the last moment of end.
+	if start, end := r.URL.Query().Get("start"), r.URL.Query().Get("end"); start != "" || end != "" {
+		parsedStart, startErr := time.Parse("2006-01-02", start)
+		parsedEnd, endErr := time.Parse("2006-01-02", end)
+		if startErr != nil || endErr != nil {
+			http.Error(w, "start and end must both be dates in YYYY-MM-DD form", http.StatusBadRequest)
+			return
+		}
+		opts.Window = types.DateRange{Start: parsedStart, End: parsedEnd.AddDate(0, 0, 1).Add(-time.Nanosecond)}
+		timeRange = ""
+	}
+
+	response, err := h.service.GetSpendingAnalytics(r.Context(), accountID, timeRange, opts)
+	if errors.Is(err, ErrInvalidTimeRange) || errors.Is(err, ErrInvalidRanking) || errors.Is(err, ErrInvalidSplitMode) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(analytics)
+	json.NewEncoder(w).Encode(response)
 }
 
 func (h *Handler) handlePatterns(w http.ResponseWriter, r *http.Request) {
@@ -80,7 +133,40 @@ func (h *Handler) handlePatterns(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	patterns, err := h.service.AnalyzeTimePatterns(r.Context(), accountID, startDate, endDate)
+	var opts PatternOptions
+	if minFrequency := r.URL.Query().Get("minFrequency"); minFrequency != "" {
+		parsed, err := strconv.Atoi(minFrequency)
+		if err != nil || parsed < 1 {
+			http.Error(w, "minFrequency must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		opts.MinFrequency = parsed
+	}
+	if includeCredits := r.URL.Query().Get("includeCredits"); includeCredits != "" {
+		parsed, err := strconv.ParseBool(includeCredits)
+		if err != nil {
+			http.Error(w, "includeCredits must be true or false", http.StatusBadRequest)
+			return
+		}
+		opts.IncludeCredits = parsed
+	}
+	if granularity := r.URL.Query().Get("granularity"); granularity != "" {
+		opts.Granularity = types.PatternGranularity(granularity)
+	}
+	if tz := r.URL.Query().Get("tz"); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			http.Error(w, "tz must be an IANA timezone such as America/New_York", http.StatusBadRequest)
+			return
+		}
+		opts.Location = loc
+	}
+
+	patterns, err := h.service.AnalyzeTimePatterns(r.Context(), accountID, startDate, endDate, opts)
+	if errors.Is(err, ErrInvalidGranularity) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -102,7 +188,42 @@ func (h *Handler) handlePredictions(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	predictions, err := h.service.PredictFutureSpending(r.Context(), accountID)
+	var opts PredictionOptions
+	if include := r.URL.Query().Get("includeTransactions"); include != "" {
+		parsed, err := strconv.ParseBool(include)
+		if err != nil {
+			http.Error(w, "includeTransactions must be true or false", http.StatusBadRequest)
+			return
+		}
+		opts.IncludeTransactions = parsed
+	}
+	opts.Average = AmountAverage(r.URL.Query().Get("average"))
+
+	switch groupBy := r.URL.Query().Get("groupBy"); groupBy {
+	case "":
+	case "week":
+		weeks, err := h.service.PredictSpendingByWeek(r.Context(), accountID, opts)
+		if errors.Is(err, ErrInvalidAverage) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(weeks)
+		return
+	default:
+		http.Error(w, "groupBy must be week", http.StatusBadRequest)
+		return
+	}
+
+	predictions, err := h.service.PredictFutureSpending(r.Context(), accountID, opts)
+	if errors.Is(err, ErrInvalidAverage) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
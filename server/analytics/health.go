@@ -0,0 +1,107 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+)
+
+// healthMonths is how many complete months of history category health is
+// judged on
+const healthMonths = 6
+
+// HealthThresholds decide when a category needs attention. Each concern a
+// category raises adds to its score: 0 is green, 1 is yellow, 2 or more is
+// red. Running over budget counts twice.
+type HealthThresholds struct {
+	// RisingTrend is the month-over-month growth, as a fraction of average
+	// monthly spend, above which spend counts as rising
+	RisingTrend float64
+	// Volatility is the coefficient of variation of monthly spend above which
+	// a category counts as volatile
+	Volatility float64
+	// BudgetWarning is the fraction of budget used last month that raises a
+	// concern; anything over the budget itself is a breach
+	BudgetWarning float64
+}
+
+// DefaultHealthThresholds flag 5% monthly growth, 30% variation or last
+// month's spend above 90% of budget
+var DefaultHealthThresholds = HealthThresholds{
+	RisingTrend:   0.05,
+	Volatility:    0.3,
+	BudgetWarning: 0.9,
+}
+
+// WithHealthThresholds overrides the thresholds used to grade category health
+func WithHealthThresholds(thresholds HealthThresholds) Option {
+	return func(s *service) {
+		s.healthThresholds = thresholds
+	}
+}
+
+// GetCategoryHealth grades each category red, yellow or green from its spend
+// trend, its month-to-month volatility and, where the account has a saved
+// budget, how much of it was used last month
+func (s *service) GetCategoryHealth(ctx context.Context, accountID string) (map[string]types.CategoryHealth, error) {
+	config, err := s.accountConfig(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := s.loadTransactions(ctx, accountID, "1 year")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	budgets := s.canonicalBudgets(config.Budgets)
+	health := make(map[string]types.CategoryHealth)
+	for category, series := range monthlySpendSeries(transactions, s.now(), healthMonths) {
+		health[category] = gradeCategory(series, budgets[category], s.healthThresholds)
+	}
+	return health, nil
+}
+
+// gradeCategory grades one category's monthly spend series, oldest first,
+// against its budget. A budget of 0 means the category isn't budgeted.
+func gradeCategory(series []float64, budget float64, thresholds HealthThresholds) types.CategoryHealth {
+	health := types.CategoryHealth{Reasons: make([]string, 0)}
+
+	avg := mean(series)
+	if avg > 0 {
+		slope, _ := linearFit(series)
+		health.Trend = slope / avg
+		health.Volatility = stddev(series) / avg
+	}
+
+	score := 0
+	if health.Trend > thresholds.RisingTrend {
+		score++
+		health.Reasons = append(health.Reasons, fmt.Sprintf("spending rising %.0f%% a month", health.Trend*100))
+	}
+	if health.Volatility > thresholds.Volatility {
+		score++
+		health.Reasons = append(health.Reasons, fmt.Sprintf("spending varies %.0f%% month to month", health.Volatility*100))
+	}
+	if budget > 0 {
+		health.BudgetUsage = series[len(series)-1] / budget
+		switch {
+		case health.BudgetUsage > 1:
+			score += 2
+			health.Reasons = append(health.Reasons, fmt.Sprintf("last month was %.0f%% of budget", health.BudgetUsage*100))
+		case health.BudgetUsage >= thresholds.BudgetWarning:
+			score++
+			health.Reasons = append(health.Reasons, fmt.Sprintf("last month was %.0f%% of budget", health.BudgetUsage*100))
+		}
+	}
+
+	switch {
+	case score >= 2:
+		health.Status = types.HealthRed
+	case score == 1:
+		health.Status = types.HealthYellow
+	default:
+		health.Status = types.HealthGreen
+	}
+	return health
+}
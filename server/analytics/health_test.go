@@ -0,0 +1,67 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+)
+
+func TestGetCategoryHealth(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		// Dining: rising, erratic and over its 300 budget last month
+		txn("2024-10-10", -80, "Dining", "Chipotle"),
+		txn("2024-11-10", -210, "Dining", "Chipotle"),
+		txn("2024-12-10", -120, "Dining", "Chipotle"),
+		txn("2025-01-10", -340, "Dining", "Chipotle"),
+		txn("2025-02-10", -260, "Dining", "Chipotle"),
+		txn("2025-03-10", -520, "Dining", "Chipotle"),
+		// Groceries: steady and well inside its 500 budget
+		txn("2024-10-12", -400, "Groceries", "Whole Foods"),
+		txn("2024-11-12", -410, "Groceries", "Whole Foods"),
+		txn("2024-12-12", -395, "Groceries", "Whole Foods"),
+		txn("2025-01-12", -405, "Groceries", "Whole Foods"),
+		txn("2025-02-12", -400, "Groceries", "Whole Foods"),
+		txn("2025-03-12", -398, "Groceries", "Whole Foods"),
+	}}
+	store := NewMemoryConfigStore()
+	if err := store.SaveConfig(context.Background(), types.AccountConfig{
+		AccountID: "1234567891",
+		Budgets:   map[string]float64{"Dining": 300, "Groceries": 500},
+	}); err != nil {
+		t.Fatalf("SaveConfig() failed: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		opts          []Option
+		wantDining    types.HealthStatus
+		wantGroceries types.HealthStatus
+	}{
+		{
+			name:          "default thresholds",
+			wantDining:    types.HealthRed,
+			wantGroceries: types.HealthGreen,
+		},
+		{
+			name:          "strict budget warning",
+			opts:          []Option{WithHealthThresholds(HealthThresholds{RisingTrend: 0.05, Volatility: 0.3, BudgetWarning: 0.75})},
+			wantDining:    types.HealthRed,
+			wantGroceries: types.HealthYellow,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{fixedClock("2025-04-15"), WithConfigStore(store)}, tt.opts...)
+			got, err := NewService(repo, opts...).GetCategoryHealth(context.Background(), "1234567891")
+			if err != nil {
+				t.Fatalf("GetCategoryHealth() failed: %v", err)
+			}
+			if got["Dining"].Status != tt.wantDining {
+				t.Errorf("Dining = %+v, want %s", got["Dining"], tt.wantDining)
+			}
+			if got["Groceries"].Status != tt.wantGroceries {
+				t.Errorf("Groceries = %+v, want %s", got["Groceries"], tt.wantGroceries)
+			}
+		})
+	}
+}
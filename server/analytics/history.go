@@ -0,0 +1,67 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"time"
+)
+
+// sparklineMonths is how many months of spend each category row's sparkline
+// covers, the current month to date included
+const sparklineMonths = 6
+
+// priorWindow is the window of the same length ending where window starts
+func priorWindow(window types.DateRange) types.DateRange {
+	return types.DateRange{Start: window.Start.Add(-window.End.Sub(window.Start)), End: window.Start.Add(-time.Nanosecond)}
+}
+
+// attachHistory gives each category row a monthly sparkline of its recent
+// spend and its change against the period just before window, so a category
+// table can be rendered without further requests. current is the totals the
+// rows were built from.
+func (s *service) attachHistory(ctx context.Context, accountID string, window types.DateRange, current map[string]float64, splits SplitMode, categories []types.CategorySpend) error {
+	now := s.now()
+	prior := priorWindow(window)
+	start := monthStart(now).AddDate(0, -(sparklineMonths - 1), 0)
+	if prior.Start.Before(start) {
+		start = prior.Start
+	}
+	transactions, err := s.repo.GetTransactions(ctx, accountID, types.DateRange{Start: start, End: now})
+	if err != nil {
+		return fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	priorTotals, err := LoadCategoryTotals(ctx, s.repo, accountID, prior)
+	if err != nil {
+		return err
+	}
+	// Compare like with like: the current totals have had splits applied
+	if splits != SplitByPrimary {
+		var priorTransactions []types.Transaction
+		for _, t := range transactions {
+			if prior.Contains(t.Date) {
+				priorTransactions = append(priorTransactions, t)
+			}
+		}
+		priorTotals = ApplySplits(priorTotals, priorTransactions)
+	}
+
+	series := monthlySpendSeries(transactions, monthStart(now).AddDate(0, 1, 0), sparklineMonths)
+	for i := range categories {
+		category := categories[i].Category
+		sparkline := series[category]
+		if sparkline == nil {
+			sparkline = make([]float64, sparklineMonths)
+		}
+		categories[i].Sparkline = sparkline
+		categories[i].Delta = &types.CategoryDelta{
+			Category:      category,
+			Current:       current[category],
+			Prior:         priorTotals[category],
+			Change:        current[category] - priorTotals[category],
+			PercentChange: percentChange(current[category], priorTotals[category]),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package analytics
+
+import "time"
+
+// HolidayCalendar tells the service which days are holidays, so the spending
+// spikes that come with them, like Black Friday or the run-up to Christmas,
+// aren't mistaken for unusual behaviour
+type HolidayCalendar interface {
+	IsHoliday(date time.Time) bool
+}
+
+// HolidaySet is a HolidayCalendar of fixed calendar days
+type HolidaySet map[string]bool
+
+// NewHolidaySet builds a HolidaySet from the given days; the time of day is
+// ignored
+func NewHolidaySet(days ...time.Time) HolidaySet {
+	set := make(HolidaySet, len(days))
+	for _, d := range days {
+		set[d.Format("2006-01-02")] = true
+	}
+	return set
+}
+
+// IsHoliday reports whether date falls on one of the set's days
+func (h HolidaySet) IsHoliday(date time.Time) bool {
+	return h[date.Format("2006-01-02")]
+}
+
+// WithHolidayCalendar sets the calendar used to recognise holidays. Anomaly
+// detection leaves holiday spending out of both the baseline and the results.
+func WithHolidayCalendar(cal HolidayCalendar) Option {
+	return func(s *service) {
+		s.holidays = cal
+	}
+}
+
+// isHoliday reports whether date is a holiday on the configured calendar
+func (s *service) isHoliday(date time.Time) bool {
+	return s.holidays != nil && s.holidays.IsHoliday(date)
+}
@@ -0,0 +1,69 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+)
+
+// GetBreakEvenIncome returns the monthly income needed to exactly cover the
+// account's average monthly expenses. Recurring charges are counted at their
+// normalized monthly cost, so an annual bill that happened to land inside the
+// window isn't counted as if it were paid every month.
+func (s *service) GetBreakEvenIncome(ctx context.Context, accountID string, timeRange string) (float64, error) {
+	transactions, err := s.loadTransactions(ctx, accountID, timeRange)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	// Recurring detection needs a longer history than the window itself
+	history, err := s.loadTransactions(ctx, accountID, "1 year")
+	if err != nil {
+		return 0, fmt.Errorf("failed to get transaction history: %w", err)
+	}
+	recurring := activeRecurring(s.detectRecurring(history), s.now())
+	isRecurring := recurringSet(recurring)
+
+	var variable float64
+	for _, t := range transactions {
+		if !isRecurring[keyOf(t)] {
+			variable += expenseAmount(t)
+		}
+	}
+
+	monthly := variable / timeRangeToMonths(timeRange)
+	for _, g := range recurring {
+		monthly += g.charge.MonthlyAmount
+	}
+
+	return monthly, nil
+}
+
+// GetCashFlow reports the income received and the spending over the time
+// range, the net flow between them and the savings rate, the share of income
+// left over. A deficit gives a negative rate; with no income the rate is zero
+// rather than undefined. Refunds are neither income nor spending.
+func (s *service) GetCashFlow(ctx context.Context, accountID string, timeRange string) (*types.CashFlow, error) {
+	transactions, err := s.loadTransactions(ctx, accountID, timeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+	return s.cashFlow(transactions), nil
+}
+
+// cashFlow separates the income in transactions from the spending and works
+// out the net flow and savings rate
+func (s *service) cashFlow(transactions []types.Transaction) *types.CashFlow {
+	result := &types.CashFlow{}
+	for _, t := range transactions {
+		if t.Amount > 0 && s.isIncome(t) {
+			result.Income += t.Amount
+		}
+		result.Expenses += expenseAmount(t)
+	}
+	result.Net = result.Income - result.Expenses
+	if result.Income > 0 {
+		result.SavingsRate = result.Net / result.Income
+	}
+	return result
+}
@@ -0,0 +1,87 @@
+package analytics
+
+import (
+	"context"
+	"math"
+	"server/types"
+	"testing"
+)
+
+func TestGetBreakEvenIncome(t *testing.T) {
+	repo := &fakeRepo{}
+	for _, month := range []string{"2025-01", "2025-02", "2025-03"} {
+		repo.transactions = append(repo.transactions,
+			txn(month+"-01", -1000, "Rent", "Park Avenue Apartments"),
+			txn(month+"-03", -15, "Entertainment", "Netflix"),
+			txn(month+"-15", 3000, "Income", "Tech Corp Inc"),
+		)
+	}
+	repo.transactions = append(repo.transactions,
+		txn("2025-01-09", -120, "Groceries", "Whole Foods"),
+		txn("2025-02-14", -80, "Groceries", "Trader Joe's"),
+		txn("2025-03-08", -100, "Groceries", "Whole Foods"),
+	)
+
+	svc := NewService(repo, fixedClock("2025-03-20"))
+	got, err := svc.GetBreakEvenIncome(context.Background(), "1234567891", "3 months")
+	if err != nil {
+		t.Fatalf("GetBreakEvenIncome() failed: %v", err)
+	}
+
+	// 1000 rent + 15 Netflix each month plus 300 groceries over 3 months
+	if want := 1115.0; !approxEqual(got, want) {
+		t.Errorf("GetBreakEvenIncome() = %v, want %v", got, want)
+	}
+}
+
+func TestGetCashFlow(t *testing.T) {
+	tests := []struct {
+		name         string
+		transactions []types.Transaction
+		want         types.CashFlow
+	}{
+		{
+			name: "saving",
+			transactions: []types.Transaction{
+				txn("2025-04-01", 4000, "Income", "Employer"),
+				txn("2025-04-03", -1500, "Rent", "Landlord"),
+				txn("2025-04-08", -500, "Food", "Whole Foods"),
+				// A refund is money back, not income
+				txn("2025-04-10", 40, "Shopping", "Amazon"),
+			},
+			want: types.CashFlow{Income: 4000, Expenses: 2000, Net: 2000, SavingsRate: 0.5},
+		},
+		{
+			name: "deficit",
+			transactions: []types.Transaction{
+				txn("2025-04-01", 2000, "Income", "Employer"),
+				txn("2025-04-03", -1500, "Rent", "Landlord"),
+				txn("2025-04-12", -1000, "Travel", "Delta"),
+			},
+			want: types.CashFlow{Income: 2000, Expenses: 2500, Net: -500, SavingsRate: -0.25},
+		},
+		{
+			name: "no income",
+			transactions: []types.Transaction{
+				txn("2025-04-03", -1500, "Rent", "Landlord"),
+			},
+			want: types.CashFlow{Expenses: 1500, Net: -1500},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(&fakeRepo{transactions: tt.transactions}, fixedClock("2025-04-15"))
+			got, err := svc.GetCashFlow(context.Background(), "1234567891", "1 month")
+			if err != nil {
+				t.Fatalf("GetCashFlow() failed: %v", err)
+			}
+			if math.IsNaN(got.SavingsRate) || math.IsInf(got.SavingsRate, 0) {
+				t.Fatalf("SavingsRate = %v, want a finite rate", got.SavingsRate)
+			}
+			if !approxEqual(got.Income, tt.want.Income) || !approxEqual(got.Expenses, tt.want.Expenses) ||
+				!approxEqual(got.Net, tt.want.Net) || !approxEqual(got.SavingsRate, tt.want.SavingsRate) {
+				t.Errorf("GetCashFlow() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"sort"
+	"time"
+)
+
+// incomeSpendWindowDays is how many days from a deposit, the deposit day
+// included, count as spend following it
+const incomeSpendWindowDays = 3
+
+// incomeSpendLift is how many times the usual spend over the same number of
+// days the spend after a source's deposits must reach for that source to be
+// said to fuel spending
+const incomeSpendLift = 1.5
+
+// GetIncomeSourceSpend compares the spend in the days after each income
+// source's deposits with the spend over the rest of the period, so users with
+// several income streams can see which of them drives discretionary spend,
+// e.g. a side-gig payment that is spent straight away while the salary isn't.
+// Sources are taken from the merchant on income deposits and returned with the
+// largest lift first.
+func (s *service) GetIncomeSourceSpend(ctx context.Context, accountID string, months int) ([]types.IncomeSourceSpend, error) {
+	if months < 1 {
+		return nil, fmt.Errorf("at least 1 month is needed to relate spend to income, got %d", months)
+	}
+
+	window, err := s.dateRange(fmt.Sprintf("%d months", months))
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := s.repo.GetTransactions(ctx, accountID, window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	loc := window.End.Location()
+	first, last := dayOf(window.Start, loc), dayOf(window.End, loc)
+	var days []time.Time
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		days = append(days, d)
+	}
+
+	daily := make(map[time.Time]float64)
+	type source struct {
+		name     string
+		deposits []time.Time
+		total    float64
+	}
+	sources := make(map[string]*source)
+	for _, t := range transactions {
+		if !window.Contains(t.Date) {
+			continue
+		}
+		if amount := expenseAmount(t); amount > 0 {
+			daily[dayOf(t.Date, loc)] += amount
+			continue
+		}
+		if t.Amount <= 0 || !s.isIncome(t) {
+			continue
+		}
+		key := merchantKey(t.Merchant)
+		if _, exists := sources[key]; !exists {
+			sources[key] = &source{name: t.Merchant}
+		}
+		sources[key].deposits = append(sources[key].deposits, dayOf(t.Date, loc))
+		sources[key].total += t.Amount
+	}
+
+	result := make([]types.IncomeSourceSpend, 0, len(sources))
+	for _, src := range sources {
+		// Mark the days following this source's deposits
+		after := make(map[time.Time]bool)
+		for _, d := range src.deposits {
+			for i := 0; i < incomeSpendWindowDays; i++ {
+				after[d.AddDate(0, 0, i)] = true
+			}
+		}
+
+		var windows []float64
+		for _, d := range src.deposits {
+			var spent float64
+			for i := 0; i < incomeSpendWindowDays; i++ {
+				spent += daily[d.AddDate(0, 0, i)]
+			}
+			windows = append(windows, spent)
+		}
+
+		var indicator, spend, others []float64
+		for _, d := range days {
+			spend = append(spend, daily[d])
+			if after[d] {
+				indicator = append(indicator, 1)
+			} else {
+				indicator = append(indicator, 0)
+				others = append(others, daily[d])
+			}
+		}
+
+		entry := types.IncomeSourceSpend{
+			Source:            src.name,
+			Deposits:          len(src.deposits),
+			AverageDeposit:    src.total / float64(len(src.deposits)),
+			AverageSpendAfter: mean(windows),
+			BaselineSpend:     mean(others) * incomeSpendWindowDays,
+			Correlation:       pearson(indicator, spend),
+		}
+		if entry.BaselineSpend > 0 {
+			entry.Lift = entry.AverageSpendAfter / entry.BaselineSpend
+		}
+		entry.FuelsSpending = entry.Lift >= incomeSpendLift
+		result = append(result, entry)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Lift == result[j].Lift {
+			return result[i].Source < result[j].Source
+		}
+		return result[i].Lift > result[j].Lift
+	})
+
+	return result, nil
+}
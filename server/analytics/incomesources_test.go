@@ -0,0 +1,52 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+	"time"
+)
+
+func TestGetIncomeSourceSpend(t *testing.T) {
+	var transactions []types.Transaction
+	start := time.Date(2025, 1, 16, 12, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 4, 15, 12, 0, 0, 0, time.UTC)
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		transactions = append(transactions, types.Transaction{Date: d, Amount: -20, Category: "Food", Merchant: "Whole Foods"})
+		switch d.Day() {
+		case 1:
+			// The salary isn't followed by any extra spend
+			transactions = append(transactions, types.Transaction{Date: d, Amount: 3000, Category: "Income", Merchant: "Employer"})
+		case 20:
+			// The side gig is spent within a couple of days
+			transactions = append(transactions, types.Transaction{Date: d, Amount: 400, Category: "Income", Merchant: "Upwork"})
+			transactions = append(transactions, types.Transaction{Date: d.AddDate(0, 0, 1), Amount: -250, Category: "Shopping", Merchant: "Best Buy"})
+		}
+	}
+
+	svc := NewService(&fakeRepo{transactions: transactions}, fixedClock("2025-04-15"))
+	got, err := svc.GetIncomeSourceSpend(context.Background(), "1234567891", 3)
+	if err != nil {
+		t.Fatalf("GetIncomeSourceSpend() failed: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("GetIncomeSourceSpend() = %+v, want both income sources", got)
+	}
+
+	gig, salary := got[0], got[1]
+	if gig.Source != "Upwork" || salary.Source != "Employer" {
+		t.Fatalf("sources = %q, %q, want Upwork ahead of Employer", gig.Source, salary.Source)
+	}
+	if gig.Deposits != 3 || !approxEqual(gig.AverageDeposit, 400) {
+		t.Errorf("Upwork Deposits, AverageDeposit = %d, %v, want 3, 400", gig.Deposits, gig.AverageDeposit)
+	}
+	if !approxEqual(gig.AverageSpendAfter, 310) {
+		t.Errorf("Upwork AverageSpendAfter = %v, want 310", gig.AverageSpendAfter)
+	}
+	if !gig.FuelsSpending || gig.Correlation <= 0 {
+		t.Errorf("Upwork = %+v, want it to fuel spending with a positive correlation", gig)
+	}
+	if salary.FuelsSpending || salary.Correlation >= 0 {
+		t.Errorf("Employer = %+v, want no spending lift after the salary", salary)
+	}
+}
@@ -0,0 +1,72 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"sort"
+)
+
+const (
+	// weeksPerMonth converts weekly habits to monthly figures
+	weeksPerMonth = 52.0 / 12
+	// minWeeklyFrequency is how often a category must be used to count as a
+	// habit worth framing in monthly terms
+	minWeeklyFrequency = 1.0
+)
+
+// GetMarginalSpend frames frequent small purchases in monthly terms: for each
+// category used at least weekly, what the habit costs a month and a year, and
+// what one more transaction a week would add
+func (s *service) GetMarginalSpend(ctx context.Context, accountID string, timeRange string) ([]types.MarginalSpend, error) {
+	transactions, err := s.loadTransactions(ctx, accountID, timeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	totals := make(map[string]float64)
+	counts := make(map[string]int)
+	for _, t := range transactions {
+		amount := expenseAmount(t)
+		if amount == 0 {
+			continue
+		}
+		totals[t.Category] += amount
+		counts[t.Category]++
+	}
+
+	weeks := timeRangeToMonths(timeRange) * weeksPerMonth
+	result := make([]types.MarginalSpend, 0)
+	for category, count := range counts {
+		perWeek := float64(count) / weeks
+		if perWeek < minWeeklyFrequency {
+			continue
+		}
+		perTransaction := totals[category] / float64(count)
+		result = append(result, marginalSpend(category, perTransaction, perWeek))
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].HabitMonthly == result[j].HabitMonthly {
+			return result[i].Category < result[j].Category
+		}
+		return result[i].HabitMonthly > result[j].HabitMonthly
+	})
+
+	return result, nil
+}
+
+// marginalSpend scales a per-transaction amount at a weekly frequency to
+// monthly and annual costs
+func marginalSpend(category string, perTransaction, perWeek float64) types.MarginalSpend {
+	marginalMonthly := perTransaction * weeksPerMonth
+	return types.MarginalSpend{
+		Category:        category,
+		PerTransaction:  perTransaction,
+		WeeklyFrequency: perWeek,
+		HabitMonthly:    marginalMonthly * perWeek,
+		HabitAnnual:     marginalMonthly * perWeek * 12,
+		MarginalMonthly: marginalMonthly,
+		MarginalAnnual:  marginalMonthly * 12,
+	}
+}
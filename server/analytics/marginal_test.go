@@ -0,0 +1,55 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+	"time"
+)
+
+func TestGetMarginalSpend(t *testing.T) {
+	// A $5 coffee twice a week for the 13 weeks of a quarter, plus rent
+	var transactions []types.Transaction
+	monday := time.Date(2025, 1, 6, 8, 0, 0, 0, time.UTC)
+	for week := 0; week < 13; week++ {
+		for _, offset := range []int{0, 3} {
+			transactions = append(transactions, types.Transaction{
+				Date:     monday.AddDate(0, 0, week*7+offset),
+				Amount:   -5,
+				Category: "Coffee",
+				Merchant: "Starbucks",
+			})
+		}
+	}
+	for _, date := range []string{"2025-01-01", "2025-02-01", "2025-03-01"} {
+		transactions = append(transactions, txn(date, -2260, "Rent", "Park Avenue Apartments"))
+	}
+
+	svc := NewService(&fakeRepo{transactions: transactions})
+	got, err := svc.GetMarginalSpend(context.Background(), "1234567891", "3 months")
+	if err != nil {
+		t.Fatalf("GetMarginalSpend() failed: %v", err)
+	}
+	if len(got) != 1 || got[0].Category != "Coffee" {
+		t.Fatalf("GetMarginalSpend() = %+v, want only the Coffee habit", got)
+	}
+
+	coffee := got[0]
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{name: "PerTransaction", got: coffee.PerTransaction, want: 5},
+		{name: "WeeklyFrequency", got: coffee.WeeklyFrequency, want: 2},
+		{name: "HabitMonthly", got: coffee.HabitMonthly, want: 43.33},
+		{name: "HabitAnnual", got: coffee.HabitAnnual, want: 520},
+		{name: "MarginalMonthly", got: coffee.MarginalMonthly, want: 21.67},
+		{name: "MarginalAnnual", got: coffee.MarginalAnnual, want: 260},
+	}
+	for _, tt := range tests {
+		if !approxEqual(tt.got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"sort"
+	"time"
+)
+
+const (
+	// loyaltySlope is the change in visits per month that counts as a merchant
+	// being visited more or less often
+	loyaltySlope = 0.5
+	// churnSilentMonths is how many months without a visit a regular merchant
+	// must go to count as churned
+	churnSilentMonths = 2
+	// churnMinVisits is the monthly visit rate, before going silent, that makes
+	// a merchant a regular worth flagging
+	churnMinVisits = 2.0
+)
+
+// GetMerchantTrends tracks monthly spend and visits per merchant over the last
+// few complete months, flagging merchants visited increasingly often and
+// regulars that have gone silent. Merchants with a single visit are left out.
+// Churned merchants come first, then by visits.
+func (s *service) GetMerchantTrends(ctx context.Context, accountID string, months int) ([]types.MerchantTrend, error) {
+	if months <= churnSilentMonths {
+		return nil, fmt.Errorf("more than %d months are needed to track merchant trends, got %d", churnSilentMonths, months)
+	}
+
+	transactions, err := s.loadTransactions(ctx, accountID, fmt.Sprintf("%d months", months+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	now := s.now()
+	last := monthStart(now)
+	first := last.AddDate(0, -months, 0)
+
+	trends := make([]types.MerchantTrend, 0)
+	for _, txns := range s.groupByMerchant(transactions) {
+		trend := types.MerchantTrend{
+			MonthlySpend:  make([]float64, months),
+			MonthlyVisits: make([]int, months),
+		}
+		var visits int
+		for _, t := range txns {
+			date := t.Date.In(now.Location())
+			if date.Before(first) || !date.Before(last) {
+				continue
+			}
+			idx := (date.Year()-first.Year())*12 + int(date.Month()) - int(first.Month())
+			trend.MonthlySpend[idx] += expenseAmount(t)
+			trend.MonthlyVisits[idx]++
+			visits++
+			if t.Date.After(trend.LastVisit) {
+				trend.Merchant = t.Merchant
+				trend.LastVisit = t.Date
+			}
+		}
+		if visits < 2 {
+			continue
+		}
+
+		counts := make([]float64, months)
+		for i, v := range trend.MonthlyVisits {
+			counts[i] = float64(v)
+		}
+		trend.VisitSlope, _ = linearFit(counts)
+		trend.Status = merchantStatus(counts, trend.VisitSlope)
+		trends = append(trends, trend)
+	}
+
+	sort.Slice(trends, func(i, j int) bool {
+		ci, cj := trends[i].Status == types.MerchantChurned, trends[j].Status == types.MerchantChurned
+		if ci != cj {
+			return ci
+		}
+		vi, vj := sumInts(trends[i].MonthlyVisits), sumInts(trends[j].MonthlyVisits)
+		if vi == vj {
+			return trends[i].Merchant < trends[j].Merchant
+		}
+		return vi > vj
+	})
+
+	return trends, nil
+}
+
+// unknownMerchant labels spend on transactions with no merchant name
+const unknownMerchant = "Unknown"
+
+// GetMerchantAnalytics totals spend per merchant over the time range, the
+// merchant-level counterpart of the category breakdown, largest total first.
+// Transactions without a merchant are grouped as "Unknown". limit keeps the
+// top merchants only; zero keeps them all.
+func (s *service) GetMerchantAnalytics(ctx context.Context, accountID string, timeRange string, limit int) ([]types.MerchantSpend, error) {
+	transactions, err := s.loadTransactions(ctx, accountID, timeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	merchants := make([]types.MerchantSpend, 0)
+	for key, txns := range s.groupByMerchant(transactions) {
+		spend := types.MerchantSpend{Merchant: unknownMerchant, Count: len(txns)}
+		var latest time.Time
+		for _, t := range txns {
+			spend.TotalSpent += expenseAmount(t)
+			// Similar names are grouped together, so show the latest spelling
+			if key != "" && t.Date.After(latest) {
+				spend.Merchant = t.Merchant
+				latest = t.Date
+			}
+		}
+		spend.AverageSpend = spend.TotalSpent / float64(spend.Count)
+		merchants = append(merchants, spend)
+	}
+
+	sort.Slice(merchants, func(i, j int) bool {
+		if merchants[i].TotalSpent == merchants[j].TotalSpent {
+			return merchants[i].Merchant < merchants[j].Merchant
+		}
+		return merchants[i].TotalSpent > merchants[j].TotalSpent
+	})
+	if limit > 0 && len(merchants) > limit {
+		merchants = merchants[:limit]
+	}
+
+	return merchants, nil
+}
+
+// merchantStatus classifies a merchant from its monthly visit counts
+func merchantStatus(visits []float64, slope float64) types.MerchantTrendStatus {
+	silent := visits[len(visits)-churnSilentMonths:]
+	if mean(silent) == 0 && mean(visits[:len(visits)-churnSilentMonths]) >= churnMinVisits {
+		return types.MerchantChurned
+	}
+	switch {
+	case slope >= loyaltySlope:
+		return types.MerchantRising
+	case slope <= -loyaltySlope:
+		return types.MerchantDeclining
+	default:
+		return types.MerchantSteady
+	}
+}
+
+// sumInts adds up values
+func sumInts(values []int) int {
+	var total int
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
@@ -0,0 +1,102 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"testing"
+)
+
+func TestGetMerchantTrends(t *testing.T) {
+	var transactions []types.Transaction
+	// Visits to the coffee shop rise from 1 to 6 a month
+	for month, visits := range []int{1, 2, 4, 6} {
+		for d := 0; d < visits; d++ {
+			transactions = append(transactions, txn(fmt.Sprintf("2025-%02d-%02d", month+1, d*4+1), -4.75, "Food", "Starbucks"))
+		}
+	}
+	// The gym was visited weekly until February, then never again
+	for _, date := range []string{"2025-01-06", "2025-01-13", "2025-01-20", "2025-02-03", "2025-02-10", "2025-02-17"} {
+		transactions = append(transactions, txn(date, -10, "Fitness", "Climbing Gym"))
+	}
+	// Groceries twice a month, month after month
+	for month := 1; month <= 4; month++ {
+		for _, day := range []int{3, 17} {
+			transactions = append(transactions, txn(fmt.Sprintf("2025-%02d-%02d", month, day), -84.20, "Food", "Whole Foods"))
+		}
+	}
+	svc := NewService(&fakeRepo{transactions: transactions}, fixedClock("2025-05-10"))
+
+	got, err := svc.GetMerchantTrends(context.Background(), "1234567891", 4)
+	if err != nil {
+		t.Fatalf("GetMerchantTrends() failed: %v", err)
+	}
+
+	want := map[string]types.MerchantTrendStatus{
+		"Climbing Gym": types.MerchantChurned,
+		"Starbucks":    types.MerchantRising,
+		"Whole Foods":  types.MerchantSteady,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("GetMerchantTrends() = %+v, want %d merchants", got, len(want))
+	}
+	if got[0].Merchant != "Climbing Gym" {
+		t.Errorf("first trend = %s, want the churned gym first", got[0].Merchant)
+	}
+	for _, trend := range got {
+		if trend.Status != want[trend.Merchant] {
+			t.Errorf("%s status = %s, want %s (visits %v)", trend.Merchant, trend.Status, want[trend.Merchant], trend.MonthlyVisits)
+		}
+	}
+	if fmt.Sprint(got[1].MonthlyVisits) != "[1 2 4 6]" {
+		t.Errorf("Starbucks visits = %v, want [1 2 4 6]", got[1].MonthlyVisits)
+	}
+}
+
+func TestGetMerchantTrendsNeedsMonths(t *testing.T) {
+	svc := NewService(&fakeRepo{})
+	if _, err := svc.GetMerchantTrends(context.Background(), "1234567891", 2); err == nil {
+		t.Error("GetMerchantTrends() over 2 months succeeded, want an error")
+	}
+}
+
+func TestGetMerchantAnalytics(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-04-02", -120, "Shopping", "Amazon"),
+		txn("2025-04-06", -200, "Shopping", "amazon "),
+		txn("2025-04-09", -20, "Shopping", "Amazon"),
+		txn("2025-04-03", -45, "Food", "Chipotle"),
+		txn("2025-04-04", -30, "Food", ""),
+		txn("2025-04-08", -30, "Food", " "),
+		// Refunds aren't spending
+		txn("2025-04-10", 20, "Shopping", "Amazon"),
+	}}
+	svc := NewService(repo, fixedClock("2025-04-15"))
+
+	got, err := svc.GetMerchantAnalytics(context.Background(), "1234567891", "1 month", 0)
+	if err != nil {
+		t.Fatalf("GetMerchantAnalytics() failed: %v", err)
+	}
+	want := []types.MerchantSpend{
+		{Merchant: "Amazon", TotalSpent: 340, Count: 3, AverageSpend: 340.0 / 3},
+		{Merchant: unknownMerchant, TotalSpent: 60, Count: 2, AverageSpend: 30},
+		{Merchant: "Chipotle", TotalSpent: 45, Count: 1, AverageSpend: 45},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("GetMerchantAnalytics() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].Merchant != want[i].Merchant || got[i].Count != want[i].Count ||
+			!approxEqual(got[i].TotalSpent, want[i].TotalSpent) || !approxEqual(got[i].AverageSpend, want[i].AverageSpend) {
+			t.Errorf("merchant %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	got, err = svc.GetMerchantAnalytics(context.Background(), "1234567891", "1 month", 1)
+	if err != nil {
+		t.Fatalf("GetMerchantAnalytics() failed: %v", err)
+	}
+	if len(got) != 1 || got[0].Merchant != "Amazon" {
+		t.Errorf("GetMerchantAnalytics() with limit 1 = %+v, want only Amazon", got)
+	}
+}
@@ -0,0 +1,143 @@
+package analytics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"server/types"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupportedSchema is returned for a snapshot written by a newer schema
+// than this build knows how to read
+var ErrUnsupportedSchema = errors.New("unsupported analytics schema version")
+
+// analyticsMigrations upgrade a snapshot from the version it is keyed by to
+// the next one. Version 3 only renamed a key, which is done on the raw
+// snapshot by renameLegacyAmounts, so it has no entry.
+var analyticsMigrations = map[int]func(*types.SpendingAnalytics) error{
+	1: migrateAnalyticsV1,
+	2: migrateAnalyticsV2,
+}
+
+// MigrateAnalytics reads a serialized SpendingAnalytics snapshot of any schema
+// version and upgrades it to SchemaVersion. Snapshots from before the version
+// was recorded are read as version 1.
+func MigrateAnalytics(raw []byte) (*types.SpendingAnalytics, error) {
+	var header struct {
+		SchemaVersion int `json:"schemaVersion"`
+	}
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return nil, fmt.Errorf("failed to parse analytics snapshot: %w", err)
+	}
+	version := header.SchemaVersion
+	if version == 0 {
+		version = 1
+	}
+	if version > SchemaVersion {
+		return nil, fmt.Errorf("%w: snapshot is version %d, newest supported is %d", ErrUnsupportedSchema, version, SchemaVersion)
+	}
+
+	// Old keys wouldn't decode into the current types, so they are dealt with
+	// before the snapshot is parsed
+	if version < 4 {
+		var err error
+		if raw, err = editPredictions(raw, renameLegacyAmounts); err != nil {
+			return nil, fmt.Errorf("failed to parse analytics snapshot: %w", err)
+		}
+	}
+	if version < 3 {
+		var err error
+		if raw, err = editPredictions(raw, stripLegacyWarnings); err != nil {
+			return nil, fmt.Errorf("failed to parse analytics snapshot: %w", err)
+		}
+	}
+
+	var analytics types.SpendingAnalytics
+	if err := json.Unmarshal(raw, &analytics); err != nil {
+		return nil, fmt.Errorf("failed to parse analytics snapshot: %w", err)
+	}
+	for ; version < SchemaVersion; version++ {
+		migrate, ok := analyticsMigrations[version]
+		if !ok {
+			continue
+		}
+		if err := migrate(&analytics); err != nil {
+			return nil, fmt.Errorf("failed to migrate analytics from version %d: %w", version, err)
+		}
+	}
+	analytics.SchemaVersion = SchemaVersion
+	return &analytics, nil
+}
+
+// migrateAnalyticsV1 derives the numeric category amount and share that
+// version 2 added from the formatted strings version 1 carried
+func migrateAnalyticsV1(analytics *types.SpendingAnalytics) error {
+	for i := range analytics.TopCategories {
+		c := &analytics.TopCategories[i]
+		amount, err := strconv.ParseFloat(strings.TrimSpace(c.TotalSpent), 64)
+		if err != nil {
+			return fmt.Errorf("invalid total %q for category %q: %w", c.TotalSpent, c.Category, err)
+		}
+		share, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(c.Percentage), "%"), 64)
+		if err != nil {
+			return fmt.Errorf("invalid percentage %q for category %q: %w", c.Percentage, c.Category, err)
+		}
+		c.Amount, c.Share = amount, share
+	}
+	return nil
+}
+
+// editPredictions applies edit to each prediction of a raw snapshot, as a
+// map of its JSON keys
+func editPredictions(raw []byte, edit func(map[string]json.RawMessage)) ([]byte, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	predicted, ok := doc["predictedSpending"]
+	if !ok {
+		return raw, nil
+	}
+	var predictions []map[string]json.RawMessage
+	if err := json.Unmarshal(predicted, &predictions); err != nil {
+		return nil, err
+	}
+	for _, p := range predictions {
+		edit(p)
+	}
+	edited, err := json.Marshal(predictions)
+	if err != nil {
+		return nil, err
+	}
+	doc["predictedSpending"] = edited
+	return json.Marshal(doc)
+}
+
+// stripLegacyWarnings drops the preformatted warning string versions 1 and 2
+// put on a prediction, which won't decode into the structured warning.
+// migrateAnalyticsV2 rebuilds it.
+func stripLegacyWarnings(prediction map[string]json.RawMessage) {
+	delete(prediction, "warning")
+}
+
+// renameLegacyAmounts moves a prediction's amount to the predictedAmount key
+// version 4 renamed it to
+func renameLegacyAmounts(prediction map[string]json.RawMessage) {
+	if amount, ok := prediction["amount"]; ok {
+		prediction["predictedAmount"] = amount
+		delete(prediction, "amount")
+	}
+}
+
+// migrateAnalyticsV2 rebuilds the structured prediction warnings version 3
+// replaced the preformatted strings with, from the predictions themselves.
+// Snapshots don't record their currency, so the warnings are left without
+// one, as the dollar strings they replace were.
+func migrateAnalyticsV2(analytics *types.SpendingAnalytics) error {
+	for i := range analytics.PredictedSpending {
+		analytics.PredictedSpending[i].Warning = predictionWarning(analytics.PredictedSpending[i], "")
+	}
+	return nil
+}
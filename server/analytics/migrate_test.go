@@ -0,0 +1,87 @@
+package analytics
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestMigrateAnalytics(t *testing.T) {
+	v1 := []byte(`{
+		"topCategories": [
+			{"category": "Rent", "totalSpent": "1500.00", "percentage": "75.00"},
+			{"category": "Food", "totalSpent": "500.00", "percentage": "25.00"}
+		],
+		"spendingPatterns": [],
+		"predictedSpending": [],
+		"totalSpent": 2000,
+		"monthlyAverage": 2000
+	}`)
+
+	got, err := MigrateAnalytics(v1)
+	if err != nil {
+		t.Fatalf("MigrateAnalytics() failed: %v", err)
+	}
+	if got.SchemaVersion != SchemaVersion {
+		t.Errorf("SchemaVersion = %d, want %d", got.SchemaVersion, SchemaVersion)
+	}
+	if len(got.TopCategories) != 2 {
+		t.Fatalf("TopCategories = %+v, want both categories", got.TopCategories)
+	}
+	rent := got.TopCategories[0]
+	if !approxEqual(rent.Amount, 1500) || !approxEqual(rent.Share, 75) || rent.TotalSpent != "1500.00" {
+		t.Errorf("Rent = %+v, want amount 1500 and share 75 alongside the original strings", rent)
+	}
+	if !approxEqual(got.TotalSpent, 2000) {
+		t.Errorf("TotalSpent = %v, want 2000", got.TotalSpent)
+	}
+
+	v2 := []byte(`{
+		"schemaVersion": 2,
+		"topCategories": [],
+		"predictedSpending": [
+			{"category": "Rent", "likelihood": 0.9, "predictedDate": "2025-04-01T12:00:00Z", "amount": 2260, "amountLow": 2200, "amountHigh": 2320,
+			 "warning": "High likelihood (90%) of spending $2260.00 ($2200.00-$2320.00) in Rent category around Apr 01"},
+			{"category": "Food", "likelihood": 0.4, "predictedDate": "2025-04-03T12:00:00Z", "amount": 40}
+		]
+	}`)
+	got, err = MigrateAnalytics(v2)
+	if err != nil {
+		t.Fatalf("MigrateAnalytics() of a version 2 snapshot failed: %v", err)
+	}
+	if len(got.PredictedSpending) != 2 {
+		t.Fatalf("PredictedSpending = %+v, want both predictions", got.PredictedSpending)
+	}
+	rentWarning := got.PredictedSpending[0].Warning
+	if rentWarning == nil || rentWarning.String() != "High likelihood (90%) of spending $2260.00 ($2200.00-$2320.00) in Rent category around Apr 01" {
+		t.Errorf("Rent warning = %+v, want it rebuilt from the prediction", rentWarning)
+	}
+	if got.PredictedSpending[1].Warning != nil {
+		t.Errorf("Food warning = %+v, want none", got.PredictedSpending[1].Warning)
+	}
+
+	v3 := []byte(`{
+		"schemaVersion": 3,
+		"topCategories": [],
+		"predictedSpending": [
+			{"category": "Food", "likelihood": 0.4, "predictedDate": "2025-04-03T12:00:00Z", "amount": 40, "amountLow": 30, "amountHigh": 50}
+		]
+	}`)
+	got, err = MigrateAnalytics(v3)
+	if err != nil {
+		t.Fatalf("MigrateAnalytics() of a version 3 snapshot failed: %v", err)
+	}
+	if len(got.PredictedSpending) != 1 || got.PredictedSpending[0].PredictedAmount != 40 || got.PredictedSpending[0].AmountLow != 30 {
+		t.Errorf("PredictedSpending = %+v, want the amount carried over as PredictedAmount", got.PredictedSpending)
+	}
+
+	if _, err := MigrateAnalytics([]byte(`{"schemaVersion": 99}`)); !errors.Is(err, ErrUnsupportedSchema) {
+		t.Errorf("MigrateAnalytics() of a newer snapshot error = %v, want ErrUnsupportedSchema", err)
+	}
+	if _, err := MigrateAnalytics([]byte(`{"topCategories": [{"category": "Food", "totalSpent": "lots"}]}`)); err == nil {
+		t.Error("MigrateAnalytics() with an unparseable total succeeded, want an error")
+	}
+	if AnalyticsVersion != strconv.Itoa(SchemaVersion) {
+		t.Errorf("AnalyticsVersion = %q, want it to match SchemaVersion %d", AnalyticsVersion, SchemaVersion)
+	}
+}
@@ -0,0 +1,78 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"sort"
+)
+
+const (
+	// momentumMonths is the length of the moving average momentum compares against
+	momentumMonths = 3
+	// momentumBand is how far from 1 the ratio must move to count as up or down
+	momentumBand = 0.1
+)
+
+// GetCategoryMomentum compares each category's spend this month with its
+// 3-month moving average, as a ratio, so users can see which categories are
+// heating up relative to their own recent baseline. To keep a partial month
+// comparable, both sides cover the month only up to today's day of month.
+// Categories with no spend in the baseline months have no momentum and are
+// left out. Strongest momentum first.
+func (s *service) GetCategoryMomentum(ctx context.Context, accountID string) ([]types.CategoryMomentum, error) {
+	transactions, err := s.loadTransactions(ctx, accountID, fmt.Sprintf("%d months", momentumMonths+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	now := s.now()
+	start := monthStart(now)
+	first := start.AddDate(0, -momentumMonths, 0)
+
+	current := make(map[string]float64)
+	baseline := make(map[string]float64)
+	for _, t := range transactions {
+		amount := expenseAmount(t)
+		if amount == 0 {
+			continue
+		}
+		date := t.Date.In(now.Location())
+		switch {
+		case date.Before(first) || date.After(now):
+		case !date.Before(start):
+			current[t.Category] += amount
+		case date.Day() <= now.Day():
+			baseline[t.Category] += amount
+		}
+	}
+
+	result := make([]types.CategoryMomentum, 0, len(baseline))
+	for category, total := range baseline {
+		average := total / momentumMonths
+		ratio := current[category] / average
+		direction := types.MomentumFlat
+		switch {
+		case ratio > 1+momentumBand:
+			direction = types.MomentumUp
+		case ratio < 1-momentumBand:
+			direction = types.MomentumDown
+		}
+		result = append(result, types.CategoryMomentum{
+			Category:  category,
+			Current:   current[category],
+			Average:   average,
+			Ratio:     ratio,
+			Direction: direction,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Ratio == result[j].Ratio {
+			return result[i].Category < result[j].Category
+		}
+		return result[i].Ratio > result[j].Ratio
+	})
+
+	return result, nil
+}
@@ -0,0 +1,51 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+)
+
+func TestGetCategoryMomentum(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		// Dining runs about 100 by mid-month, but 250 this month
+		txn("2025-01-10", -100, "Dining", "Restaurant"),
+		txn("2025-02-10", -90, "Dining", "Restaurant"),
+		txn("2025-03-10", -110, "Dining", "Restaurant"),
+		txn("2025-04-05", -150, "Dining", "Restaurant"),
+		txn("2025-04-12", -100, "Dining", "Restaurant"),
+		// Late-month spend doesn't count against this month's first half
+		txn("2025-03-28", -500, "Dining", "Restaurant"),
+		// Groceries are steady, Travel has cooled off
+		txn("2025-01-08", -200, "Food", "Whole Foods"),
+		txn("2025-02-08", -200, "Food", "Whole Foods"),
+		txn("2025-03-08", -200, "Food", "Whole Foods"),
+		txn("2025-04-08", -205, "Food", "Whole Foods"),
+		txn("2025-02-03", -600, "Travel", "Delta"),
+	}}
+	svc := NewService(repo, fixedClock("2025-04-15"))
+
+	got, err := svc.GetCategoryMomentum(context.Background(), "1234567891")
+	if err != nil {
+		t.Fatalf("GetCategoryMomentum() failed: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("GetCategoryMomentum() = %+v, want 3 categories", got)
+	}
+
+	want := []struct {
+		category  string
+		ratio     float64
+		direction types.MomentumDirection
+	}{
+		{category: "Dining", ratio: 2.5, direction: types.MomentumUp},
+		{category: "Food", ratio: 1.025, direction: types.MomentumFlat},
+		{category: "Travel", ratio: 0, direction: types.MomentumDown},
+	}
+	for i, w := range want {
+		if got[i].Category != w.category || !approxEqual(got[i].Ratio, w.ratio) || got[i].Direction != w.direction {
+			t.Errorf("momentum[%d] = %s %.3f %s, want %s %.3f %s", i,
+				got[i].Category, got[i].Ratio, got[i].Direction, w.category, w.ratio, w.direction)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+)
+
+// GetNetSpend reports spend net of the cashback and rewards earned on it, so
+// users see what their purchases really cost. ByCategory holds the net spend
+// per category.
+func (s *service) GetNetSpend(ctx context.Context, accountID string, timeRange string) (*types.NetSpend, error) {
+	transactions, err := s.loadTransactions(ctx, accountID, timeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	result := &types.NetSpend{ByCategory: make(map[string]float64)}
+	for _, t := range transactions {
+		amount := expenseAmount(t)
+		if amount == 0 {
+			continue
+		}
+		result.Gross += amount
+		result.Rewards += t.Rewards
+		result.ByCategory[t.Category] += amount - t.Rewards
+	}
+	result.Net = result.Gross - result.Rewards
+
+	return result, nil
+}
@@ -0,0 +1,45 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+)
+
+func TestGetNetSpend(t *testing.T) {
+	withRewards := func(t types.Transaction, rewards float64) types.Transaction {
+		t.Rewards = rewards
+		return t
+	}
+	repo := &fakeRepo{transactions: []types.Transaction{
+		withRewards(txn("2025-03-03", -200, "Travel", "Delta Airlines"), 10),
+		withRewards(txn("2025-03-07", -84.20, "Food", "Whole Foods"), 2.53),
+		txn("2025-03-12", -45.30, "Food", "Chipotle"),
+		txn("2025-03-15", 3500, "Income", "Employer"),
+	}}
+
+	got, err := NewService(repo).GetNetSpend(context.Background(), "1234567891", "1 month")
+	if err != nil {
+		t.Fatalf("GetNetSpend() failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{name: "Gross", got: got.Gross, want: 329.50},
+		{name: "Rewards", got: got.Rewards, want: 12.53},
+		{name: "Net", got: got.Net, want: 316.97},
+		{name: "ByCategory[Travel]", got: got.ByCategory["Travel"], want: 190},
+		{name: "ByCategory[Food]", got: got.ByCategory["Food"], want: 126.97},
+	}
+	for _, tt := range tests {
+		if !approxEqual(tt.got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+	if got.Net >= got.Gross {
+		t.Errorf("Net = %v, want below Gross %v", got.Net, got.Gross)
+	}
+}
@@ -0,0 +1,65 @@
+package analytics
+
+import (
+	"server/types"
+	"sort"
+	"time"
+)
+
+// WithDailyNetting makes time-pattern and anomaly analysis work on each day's
+// net amount per category rather than on individual transactions, so a
+// purchase refunded the same day cancels out instead of showing up as both a
+// spend and a credit
+func WithDailyNetting() Option {
+	return func(s *service) {
+		s.dailyNetting = true
+	}
+}
+
+// netDaily collapses transactions into one per category per day in loc,
+// carrying their net amount and the time of the day's first transaction.
+// Days that net to zero are dropped. Transactions are returned unchanged
+// unless daily netting is on.
+func (s *service) netDaily(transactions []types.Transaction, loc *time.Location) []types.Transaction {
+	if !s.dailyNetting {
+		return transactions
+	}
+
+	type dayKey struct {
+		day      time.Time
+		category string
+	}
+	netted := make(map[dayKey]*types.Transaction)
+	for _, t := range transactions {
+		key := dayKey{dayOf(t.Date, loc), t.Category}
+		n, ok := netted[key]
+		if !ok {
+			n = &types.Transaction{AccountID: t.AccountID, Date: t.Date, Category: t.Category, Merchant: t.Merchant}
+			netted[key] = n
+		}
+		n.Amount += t.Amount
+		if t.Date.Before(n.Date) {
+			n.Date = t.Date
+		}
+		// A day spread over several merchants has no one merchant
+		if merchantKey(n.Merchant) != merchantKey(t.Merchant) {
+			n.Merchant = ""
+		}
+	}
+
+	result := make([]types.Transaction, 0, len(netted))
+	for _, n := range netted {
+		// Round away float noise so a refunded day nets to exactly zero
+		if abs(n.Amount) < 0.005 {
+			continue
+		}
+		result = append(result, *n)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Date.Equal(result[j].Date) {
+			return result[i].Category < result[j].Category
+		}
+		return result[i].Date.Before(result[j].Date)
+	})
+	return result
+}
@@ -0,0 +1,76 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"testing"
+	"time"
+)
+
+func TestWithDailyNetting(t *testing.T) {
+	var transactions []types.Transaction
+	for i := 1; i <= 10; i++ {
+		transactions = append(transactions, txn(fmt.Sprintf("2025-03-%02d", i), -10, "Shopping", "Target"))
+	}
+	// A large purchase returned a few hours later
+	purchase := txn("2025-03-20", -500, "Shopping", "Best Buy")
+	refund := purchase
+	refund.Amount = 500
+	refund.Date = purchase.Date.Add(3 * time.Hour)
+	transactions = append(transactions, purchase, refund)
+
+	start, end := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		name      string
+		opts      []Option
+		anomalies int
+		spendOn20 bool
+	}{
+		{name: "per transaction", anomalies: 1, spendOn20: true},
+		{name: "daily netting", opts: []Option{WithDailyNetting()}, anomalies: 0, spendOn20: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(&fakeRepo{transactions: transactions}, append(tt.opts, fixedClock("2025-03-25"))...)
+
+			anomalies, err := svc.DetectAnomalies(context.Background(), "1234567891", "1 month", AnomalyOptions{})
+			if err != nil {
+				t.Fatalf("DetectAnomalies() failed: %v", err)
+			}
+			if len(anomalies) != tt.anomalies {
+				t.Errorf("DetectAnomalies() = %+v, want %d anomalies", anomalies, tt.anomalies)
+			}
+
+			patterns, err := svc.AnalyzeTimePatterns(context.Background(), "1234567891", start, end, PatternOptions{Granularity: types.PatternDayOfMonth})
+			if err != nil {
+				t.Fatalf("AnalyzeTimePatterns() failed: %v", err)
+			}
+			var spendOn20 bool
+			for _, p := range patterns {
+				spendOn20 = spendOn20 || p.DayOfMonth == 20
+			}
+			if spendOn20 != tt.spendOn20 {
+				t.Errorf("AnalyzeTimePatterns() = %+v, spend on the 20th %v, want %v", patterns, spendOn20, tt.spendOn20)
+			}
+		})
+	}
+}
+
+func TestNetDaily(t *testing.T) {
+	purchase := txn("2025-03-20", -500, "Shopping", "Best Buy")
+	refund := purchase
+	refund.Amount = 500
+	coffee := txn("2025-03-20", -4.50, "Food", "Starbucks")
+	lunch := txn("2025-03-20", -12, "Food", "Chipotle")
+	lunch.Date = lunch.Date.Add(-2 * time.Hour)
+
+	svc := NewService(&fakeRepo{}, WithDailyNetting()).(*service)
+	got := svc.netDaily([]types.Transaction{purchase, refund, coffee, lunch}, time.UTC)
+	if len(got) != 1 {
+		t.Fatalf("netDaily() = %+v, want only the Food day", got)
+	}
+	food := got[0]
+	if food.Category != "Food" || !approxEqual(food.Amount, -16.50) || !food.Date.Equal(lunch.Date) || food.Merchant != "" {
+		t.Errorf("netDaily() = %+v, want -16.50 of Food at the time of lunch with no single merchant", food)
+	}
+}
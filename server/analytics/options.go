@@ -0,0 +1,40 @@
+package analytics
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Option configures optional behaviour of the analytics service
+type Option func(*service)
+
+// WithClock overrides the time source used wherever the service needs "now",
+// e.g. to work out how far into the current month we are
+func WithClock(now func() time.Time) Option {
+	return func(s *service) {
+		if now != nil {
+			s.now = now
+		}
+	}
+}
+
+// defaultSeed keeps predictions reproducible when no random source is supplied
+const defaultSeed = 1
+
+// WithRandSource seeds the sampling in predictions from r. The seed is drawn
+// once, so a seeded source makes results reproducible across runs.
+func WithRandSource(r *rand.Rand) Option {
+	return func(s *service) {
+		if r != nil {
+			s.seed = r.Int63()
+		}
+	}
+}
+
+// WithSeed seeds the sampling in predictions. Each call samples from a fresh
+// source with this seed, so identical calls give identical results.
+func WithSeed(seed int64) Option {
+	return func(s *service) {
+		s.seed = seed
+	}
+}
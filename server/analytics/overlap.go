@@ -0,0 +1,105 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"sort"
+	"strings"
+)
+
+// defaultServiceTypes maps well-known subscription merchants to the function
+// they serve, so paying for several of the same kind can be spotted
+var defaultServiceTypes = map[string]string{
+	"netflix":          "streaming",
+	"hulu":             "streaming",
+	"disney plus":      "streaming",
+	"hbo max":          "streaming",
+	"paramount plus":   "streaming",
+	"peacock":          "streaming",
+	"apple tv":         "streaming",
+	"prime video":      "streaming",
+	"spotify":          "music",
+	"apple music":      "music",
+	"youtube music":    "music",
+	"tidal":            "music",
+	"pandora":          "music",
+	"dropbox":          "cloud storage",
+	"google one":       "cloud storage",
+	"icloud":           "cloud storage",
+	"onedrive":         "cloud storage",
+	"planet fitness":   "gym",
+	"equinox":          "gym",
+	"peloton":          "gym",
+	"la fitness":       "gym",
+	"audible":          "audiobooks",
+	"kindle unlimited": "audiobooks",
+}
+
+// WithServiceTypes replaces the merchant to service-type mapping used to
+// detect overlapping subscriptions, e.g. {"Netflix": "streaming"}. A merchant
+// matches when its name, ignoring store numbers and punctuation, equals or
+// starts with a key.
+func WithServiceTypes(serviceTypes map[string]string) Option {
+	return func(s *service) {
+		s.serviceTypes = make(map[string]string, len(serviceTypes))
+		for merchant, serviceType := range serviceTypes {
+			if key := fuzzyName(merchantKey(merchant)); key != "" && serviceType != "" {
+				s.serviceTypes[key] = serviceType
+			}
+		}
+	}
+}
+
+// DetectSubscriptionOverlap groups the account's active recurring charges by
+// service type and reports every type paid for more than once, such as three
+// streaming services at the same time. Largest monthly total first.
+func (s *service) DetectSubscriptionOverlap(ctx context.Context, accountID string) ([]types.SubscriptionOverlap, error) {
+	history, err := s.loadTransactions(ctx, accountID, "1 year")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction history: %w", err)
+	}
+
+	byType := make(map[string]*types.SubscriptionOverlap)
+	for _, g := range activeRecurring(s.detectRecurring(history), s.now()) {
+		serviceType, ok := s.serviceTypeOf(g.charge.Merchant)
+		if !ok {
+			continue
+		}
+		o, ok := byType[serviceType]
+		if !ok {
+			o = &types.SubscriptionOverlap{ServiceType: serviceType}
+			byType[serviceType] = o
+		}
+		o.Subscriptions = append(o.Subscriptions, g.charge)
+		o.MonthlyTotal += g.charge.MonthlyAmount
+	}
+
+	overlaps := make([]types.SubscriptionOverlap, 0)
+	for _, o := range byType {
+		if len(o.Subscriptions) > 1 {
+			overlaps = append(overlaps, *o)
+		}
+	}
+	sort.Slice(overlaps, func(i, j int) bool {
+		if overlaps[i].MonthlyTotal == overlaps[j].MonthlyTotal {
+			return overlaps[i].ServiceType < overlaps[j].ServiceType
+		}
+		return overlaps[i].MonthlyTotal > overlaps[j].MonthlyTotal
+	})
+
+	return overlaps, nil
+}
+
+// serviceTypeOf looks up the service type of a merchant, preferring the
+// longest matching key so "apple music" isn't mistaken for "apple tv"
+func (s *service) serviceTypeOf(merchant string) (string, bool) {
+	name := fuzzyName(merchantKey(merchant))
+	var best, serviceType string
+	for key, t := range s.serviceTypes {
+		if len(key) > len(best) && (name == key || strings.HasPrefix(name, key+" ")) {
+			best, serviceType = key, t
+		}
+	}
+	return serviceType, best != ""
+}
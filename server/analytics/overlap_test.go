@@ -0,0 +1,63 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+)
+
+func TestDetectSubscriptionOverlap(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-01-03", -15.49, "Entertainment", "Netflix.com"),
+		txn("2025-02-03", -15.49, "Entertainment", "Netflix.com"),
+		txn("2025-03-03", -15.49, "Entertainment", "Netflix.com"),
+		txn("2025-01-10", -7.99, "Entertainment", "Hulu"),
+		txn("2025-02-10", -7.99, "Entertainment", "Hulu"),
+		txn("2025-03-10", -7.99, "Entertainment", "Hulu"),
+		txn("2025-01-18", -13.99, "Entertainment", "Disney Plus"),
+		txn("2025-02-18", -13.99, "Entertainment", "Disney Plus"),
+		txn("2025-03-18", -13.99, "Entertainment", "Disney Plus"),
+		txn("2025-01-05", -10.99, "Entertainment", "Spotify"),
+		txn("2025-02-05", -10.99, "Entertainment", "Spotify"),
+		txn("2025-03-05", -10.99, "Entertainment", "Spotify"),
+	}}
+	svc := NewService(repo, fixedClock("2025-03-25"))
+
+	got, err := svc.DetectSubscriptionOverlap(context.Background(), "1234567891")
+	if err != nil {
+		t.Fatalf("DetectSubscriptionOverlap() failed: %v", err)
+	}
+
+	if len(got) != 1 {
+		t.Fatalf("DetectSubscriptionOverlap() = %+v, want only streaming flagged", got)
+	}
+	if got[0].ServiceType != "streaming" || len(got[0].Subscriptions) != 3 {
+		t.Errorf("overlap = %s with %d subscriptions, want streaming with 3", got[0].ServiceType, len(got[0].Subscriptions))
+	}
+	if !approxEqual(got[0].MonthlyTotal, 15.49+7.99+13.99) {
+		t.Errorf("MonthlyTotal = %v, want %v", got[0].MonthlyTotal, 15.49+7.99+13.99)
+	}
+}
+
+func TestSubscriptionOverlapCustomServiceTypes(t *testing.T) {
+	svc := NewService(&fakeRepo{}, WithServiceTypes(map[string]string{
+		"Apple":       "bundle",
+		"Apple Music": "music",
+	})).(*service)
+
+	tests := []struct {
+		merchant string
+		want     string
+		ok       bool
+	}{
+		{merchant: "APPLE MUSIC #1234", want: "music", ok: true},
+		{merchant: "Apple.com/bill", want: "bundle", ok: true},
+		{merchant: "Netflix", ok: false},
+	}
+	for _, tt := range tests {
+		got, ok := svc.serviceTypeOf(tt.merchant)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("serviceTypeOf(%q) = %q, %v, want %q, %v", tt.merchant, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"server/types"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeTimePatternsMinFrequency(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		// Habitual Monday lunch
+		txn("2025-03-03", -12.50, "Food", "Chipotle"),
+		txn("2025-03-10", -13.10, "Food", "Chipotle"),
+		txn("2025-03-17", -11.90, "Food", "Chipotle"),
+		// One-off Saturday purchase
+		txn("2025-03-08", -240, "Shopping", "Best Buy"),
+	}}
+	svc := NewService(repo)
+	start, end := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		opts PatternOptions
+		want int
+	}{
+		{name: "default keeps every bucket", opts: PatternOptions{}, want: 2},
+		{name: "min frequency 1", opts: PatternOptions{MinFrequency: 1}, want: 2},
+		{name: "min frequency 2 drops one-off", opts: PatternOptions{MinFrequency: 2}, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.AnalyzeTimePatterns(context.Background(), "1234567891", start, end, tt.opts)
+			if err != nil {
+				t.Fatalf("AnalyzeTimePatterns() failed: %v", err)
+			}
+			if len(got) != tt.want {
+				t.Fatalf("AnalyzeTimePatterns() returned %d patterns, want %d", len(got), tt.want)
+			}
+			for _, p := range got {
+				if p.Frequency < tt.opts.MinFrequency {
+					t.Errorf("pattern %+v below MinFrequency %d", p, tt.opts.MinFrequency)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeTimePatternsIgnoresCredits(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-03-03", -12.50, "Food", "Chipotle"),
+		// Friday payday deposit
+		txn("2025-03-14", 3200, "Income", "Payroll"),
+	}}
+	svc := NewService(repo)
+	start, end := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	got, err := svc.AnalyzeTimePatterns(context.Background(), "1234567891", start, end, PatternOptions{})
+	if err != nil {
+		t.Fatalf("AnalyzeTimePatterns() failed: %v", err)
+	}
+	if len(got) != 1 || got[0].DayOfWeek != "Monday" || !approxEqual(got[0].AverageSpend, 12.50) {
+		t.Errorf("AnalyzeTimePatterns() = %+v, want only the Monday lunch", got)
+	}
+
+	got, err = svc.AnalyzeTimePatterns(context.Background(), "1234567891", start, end, PatternOptions{IncludeCredits: true})
+	if err != nil {
+		t.Fatalf("AnalyzeTimePatterns() failed: %v", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("AnalyzeTimePatterns() with credits = %+v, want the deposit too", got)
+	}
+}
+
+func TestAnalyzeTimePatternsDayOfMonth(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		// Rent on the 1st, whatever the weekday
+		txn("2025-01-01", -1500, "Rent", "Landlord"),
+		txn("2025-02-01", -1500, "Rent", "Landlord"),
+		txn("2025-03-01", -1600, "Rent", "Landlord"),
+		txn("2025-02-15", -40, "Food", "Chipotle"),
+	}}
+	svc := NewService(repo)
+	start, end := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	got, err := svc.AnalyzeTimePatterns(context.Background(), "1234567891", start, end, PatternOptions{Granularity: types.PatternDayOfMonth})
+	if err != nil {
+		t.Fatalf("AnalyzeTimePatterns() failed: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("AnalyzeTimePatterns() = %+v, want the 1st and the 15th", got)
+	}
+	if got[0].DayOfMonth != 1 || got[0].Frequency != 3 || !approxEqual(got[0].AverageSpend, 1533.33) {
+		t.Errorf("first pattern = %+v, want 3 rent payments on the 1st averaging 1533.33", got[0])
+	}
+	if got[0].DayOfWeek != "" || got[0].TimeOfDay != "" {
+		t.Errorf("first pattern = %+v, want no weekday or time for day-of-month buckets", got[0])
+	}
+	if got[1].DayOfMonth != 15 || got[1].Frequency != 1 {
+		t.Errorf("second pattern = %+v, want the one-off on the 15th", got[1])
+	}
+}
+
+func TestAnalyzeTimePatternsDayPart(t *testing.T) {
+	at := func(date string, hour, minute int, amount float64) types.Transaction {
+		tx := txn(date, amount, "Food", "Cafe")
+		tx.Date = time.Date(tx.Date.Year(), tx.Date.Month(), tx.Date.Day(), hour, minute, 0, 0, time.UTC)
+		return tx
+	}
+	repo := &fakeRepo{transactions: []types.Transaction{
+		// Two Monday breakfasts at different hours share the morning bucket
+		at("2025-03-03", 8, 10, -5),
+		at("2025-03-10", 10, 50, -7),
+		at("2025-03-03", 19, 30, -30),
+		at("2025-03-03", 23, 15, -12),
+	}}
+	svc := NewService(repo)
+	start, end := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	got, err := svc.AnalyzeTimePatterns(context.Background(), "1234567891", start, end, PatternOptions{Granularity: types.PatternDayPart})
+	if err != nil {
+		t.Fatalf("AnalyzeTimePatterns() failed: %v", err)
+	}
+	want := []types.TimePattern{
+		{DayOfWeek: "Monday", TimeOfDay: "morning", Frequency: 2, AverageSpend: 6},
+		{DayOfWeek: "Monday", TimeOfDay: "evening", Frequency: 1, AverageSpend: 30},
+		{DayOfWeek: "Monday", TimeOfDay: "night", Frequency: 1, AverageSpend: 12},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("AnalyzeTimePatterns() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].DayOfWeek != want[i].DayOfWeek || got[i].TimeOfDay != want[i].TimeOfDay ||
+			got[i].Frequency != want[i].Frequency || !approxEqual(got[i].AverageSpend, want[i].AverageSpend) {
+			t.Errorf("pattern %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	got, err = svc.AnalyzeTimePatterns(context.Background(), "1234567891", start, end, PatternOptions{Granularity: types.PatternQuarterHour})
+	if err != nil {
+		t.Fatalf("AnalyzeTimePatterns() failed: %v", err)
+	}
+	if len(got) != 4 || got[0].TimeOfDay != "19:30" {
+		t.Errorf("AnalyzeTimePatterns() quarter-hour = %+v, want 4 windows led by 19:30", got)
+	}
+
+	if _, err := svc.AnalyzeTimePatterns(context.Background(), "1234567891", start, end, PatternOptions{Granularity: "fortnightly"}); !errors.Is(err, ErrInvalidGranularity) {
+		t.Errorf("AnalyzeTimePatterns() error = %v, want ErrInvalidGranularity", err)
+	}
+}
+
+func TestAnalyzeTimePatternsLocation(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	at := func(utc string, amount float64) types.Transaction {
+		date, err := time.Parse(time.RFC3339, utc)
+		if err != nil {
+			t.Fatalf("bad test date %q: %v", utc, err)
+		}
+		return types.Transaction{Date: date, Amount: amount, Category: "Food", Merchant: "Diner"}
+	}
+
+	tests := []struct {
+		name         string
+		transactions []types.Transaction
+		want         []types.TimePattern
+	}{
+		{
+			name:         "late evening lands on the local day",
+			transactions: []types.Transaction{at("2024-01-01T02:00:00Z", -30)},
+			want:         []types.TimePattern{{DayOfWeek: "Sunday", TimeOfDay: "21:00", Frequency: 1, AverageSpend: 30}},
+		},
+		{
+			// Clocks jump from 02:00 to 03:00, so an hour apart in UTC skips a
+			// local hour
+			name: "spring forward",
+			transactions: []types.Transaction{
+				at("2024-03-10T06:30:00Z", -10),
+				at("2024-03-10T07:30:00Z", -20),
+			},
+			want: []types.TimePattern{
+				{DayOfWeek: "Sunday", TimeOfDay: "03:00", Frequency: 1, AverageSpend: 20},
+				{DayOfWeek: "Sunday", TimeOfDay: "01:00", Frequency: 1, AverageSpend: 10},
+			},
+		},
+		{
+			// Clocks fall back from 02:00 to 01:00, so 01:30 happens twice
+			name: "fall back",
+			transactions: []types.Transaction{
+				at("2024-11-03T05:30:00Z", -10),
+				at("2024-11-03T06:30:00Z", -20),
+			},
+			want: []types.TimePattern{{DayOfWeek: "Sunday", TimeOfDay: "01:00", Frequency: 2, AverageSpend: 15}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(&fakeRepo{transactions: tt.transactions})
+			start, end := tt.transactions[0].Date.AddDate(0, 0, -1), tt.transactions[0].Date.AddDate(0, 0, 1)
+			got, err := svc.AnalyzeTimePatterns(context.Background(), "1234567891", start, end, PatternOptions{Location: newYork})
+			if err != nil {
+				t.Fatalf("AnalyzeTimePatterns() failed: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("AnalyzeTimePatterns() = %+v, want %+v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i].DayOfWeek != tt.want[i].DayOfWeek || got[i].TimeOfDay != tt.want[i].TimeOfDay ||
+					got[i].Frequency != tt.want[i].Frequency || !approxEqual(got[i].AverageSpend, tt.want[i].AverageSpend) {
+					t.Errorf("pattern %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeTimePatternsCancelled(t *testing.T) {
+	var transactions []types.Transaction
+	for i := 0; i < 5000; i++ {
+		transactions = append(transactions, txn("2025-03-03", -12.50, "Food", "Chipotle"))
+	}
+	svc := NewService(&fakeRepo{transactions: transactions}, fixedClock("2025-04-15"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start, end := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	got, err := svc.AnalyzeTimePatterns(ctx, "1234567891", start, end, PatternOptions{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("AnalyzeTimePatterns() error = %v, want context.Canceled", err)
+	}
+	if got != nil {
+		t.Errorf("AnalyzeTimePatterns() = %v, want no patterns", got)
+	}
+}
@@ -0,0 +1,75 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"sort"
+)
+
+const (
+	// nearZeroShare is how low, as a share of the paycheck that ends it, a
+	// pay period's lowest balance must fall to count as running out of money
+	nearZeroShare = 0.1
+	// paycheckToPaycheckShare is the share of pay periods that must run
+	// close to zero for the account to be living paycheck to paycheck
+	paycheckToPaycheckShare = 0.75
+)
+
+// IsPaycheckToPaycheck works out whether the balance keeps running down to
+// nearly nothing before each payday. Past balances are rebuilt from the
+// current balance by undoing each transaction, newest first, then the lowest
+// balance in every pay period over the last six months is compared with the
+// paycheck that ends it.
+func (s *service) IsPaycheckToPaycheck(ctx context.Context, accountID string) (*types.P2PStatus, error) {
+	transactions, err := s.loadTransactions(ctx, accountID, "6 months")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+	balance, err := s.repo.GetBalance(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+
+	txns := append([]types.Transaction(nil), transactions...)
+	sort.SliceStable(txns, func(i, j int) bool {
+		return txns[i].Date.Before(txns[j].Date)
+	})
+	after := make([]float64, len(txns))
+	for i := len(txns) - 1; i >= 0; i-- {
+		after[i] = balance
+		balance -= txns[i].Amount
+	}
+
+	// A deposit on the same day as the last one is part of the same paycheck
+	status := &types.P2PStatus{}
+	var lows, low float64
+	lastPayday := -1
+	for i, t := range txns {
+		if t.Amount <= 0 || !s.isIncome(t) {
+			if lastPayday >= 0 && after[i] < low {
+				low = after[i]
+			}
+			continue
+		}
+		if lastPayday >= 0 && calendarDays(txns[lastPayday].Date, t.Date) > 0 {
+			if before := after[i] - t.Amount; before < low {
+				low = before
+			}
+			status.PayPeriods++
+			lows += low
+			if low <= t.Amount*nearZeroShare {
+				status.NearZeroPeriods++
+			}
+		}
+		lastPayday = i
+		low = after[i]
+	}
+
+	if status.PayPeriods < 2 {
+		return nil, fmt.Errorf("need at least 2 pay periods: %w", ErrInsufficientHistory)
+	}
+	status.AveragePrePaydayLow = lows / float64(status.PayPeriods)
+	status.PaycheckToPaycheck = float64(status.NearZeroPeriods) >= float64(status.PayPeriods)*paycheckToPaycheckShare
+	return status, nil
+}
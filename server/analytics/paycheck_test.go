@@ -0,0 +1,53 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"server/types"
+	"testing"
+)
+
+func TestIsPaycheckToPaycheck(t *testing.T) {
+	// Paid 2,000 on the 1st, all of it spent on rent and food by the next
+	// deposit every month
+	var transactions []types.Transaction
+	for _, month := range []string{"2025-01", "2025-02", "2025-03", "2025-04"} {
+		transactions = append(transactions,
+			txn(month+"-01", 2000, "Income", "Acme Payroll"),
+			txn(month+"-03", -1500, "Rent", "Park Avenue Apartments"),
+			txn(month+"-20", -500, "Food", "Whole Foods"),
+		)
+	}
+
+	tests := []struct {
+		name    string
+		balance float64
+		want    bool
+		wantLow float64
+	}{
+		// Each month spends the whole paycheck, so every pay period bottoms
+		// out at whatever is left after April's spending
+		{name: "runs down to zero", balance: 50, want: true, wantLow: 50},
+		{name: "keeps a cushion", balance: 5000, want: false, wantLow: 5000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(&fakeRepo{transactions: transactions, balance: tt.balance}, fixedClock("2025-04-25"))
+			got, err := svc.IsPaycheckToPaycheck(context.Background(), "1234567891")
+			if err != nil {
+				t.Fatalf("IsPaycheckToPaycheck() failed: %v", err)
+			}
+			if got.PaycheckToPaycheck != tt.want || got.PayPeriods != 3 {
+				t.Errorf("IsPaycheckToPaycheck() = %+v, want %v over 3 pay periods", got, tt.want)
+			}
+			if !approxEqual(got.AveragePrePaydayLow, tt.wantLow) {
+				t.Errorf("AveragePrePaydayLow = %v, want %v", got.AveragePrePaydayLow, tt.wantLow)
+			}
+		})
+	}
+
+	svc := NewService(&fakeRepo{transactions: transactions[:3], balance: 100}, fixedClock("2025-04-25"))
+	if _, err := svc.IsPaycheckToPaycheck(context.Background(), "1234567891"); !errors.Is(err, ErrInsufficientHistory) {
+		t.Errorf("IsPaycheckToPaycheck() with one payday error = %v, want ErrInsufficientHistory", err)
+	}
+}
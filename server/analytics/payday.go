@@ -0,0 +1,121 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"sort"
+	"time"
+)
+
+// paydaySpikeThreshold is how many times the usual spend for a weekday a
+// post-payday weekday must reach to count as a spike
+const paydaySpikeThreshold = 1.5
+
+// weekdayOrdinals names the occurrence of a weekday after payday
+var weekdayOrdinals = []string{"first", "second", "third", "fourth", "fifth"}
+
+// DetectPaydayWeekdaySpikes looks for a particular weekday after payday that
+// consistently sees heavier spend, such as the first Friday after being paid.
+// Each day is compared with the same weekday at other points in the pay
+// period, so a habit of spending on Fridays in general isn't mistaken for a
+// payday effect. Spikes are returned largest first.
+func (s *service) DetectPaydayWeekdaySpikes(ctx context.Context, accountID string, months int) ([]types.PaydaySpike, error) {
+	if months < 2 {
+		return nil, fmt.Errorf("at least 2 months are needed to detect payday spikes, got %d", months)
+	}
+
+	transactions, err := s.loadTransactions(ctx, accountID, fmt.Sprintf("%d months", months+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	end := monthStart(s.now())
+	periods := s.payPeriods(transactions, end.AddDate(0, -months, 0), end)
+	if len(periods) < 2 {
+		return nil, fmt.Errorf("need at least 2 pay periods to compare: %w", ErrInsufficientHistory)
+	}
+
+	daily := make(map[time.Time]float64)
+	for _, t := range transactions {
+		if amount := expenseAmount(t); amount > 0 {
+			daily[dayOf(t.Date, end.Location())] += amount
+		}
+	}
+
+	// Spend on each day of each pay period, keyed by which occurrence of its
+	// weekday since payday it was
+	type slot struct {
+		weekday    time.Weekday
+		occurrence int
+	}
+	spend := make(map[slot][]float64)
+	for _, p := range periods {
+		var seen [7]int
+		for d := p.start; d.Before(p.end); d = d.AddDate(0, 0, 1) {
+			seen[d.Weekday()]++
+			key := slot{d.Weekday(), seen[d.Weekday()]}
+			spend[key] = append(spend[key], daily[dayOf(d, end.Location())])
+		}
+	}
+
+	spikes := make([]types.PaydaySpike, 0)
+	for key, values := range spend {
+		if len(values) < 2 || key.occurrence > len(weekdayOrdinals) {
+			continue
+		}
+
+		var others []float64
+		for other, v := range spend {
+			if other.weekday == key.weekday && other.occurrence != key.occurrence {
+				others = append(others, v...)
+			}
+		}
+		baseline := mean(others)
+		avg := mean(values)
+		if avg == 0 || baseline == 0 {
+			continue
+		}
+
+		spiking := 0
+		for _, v := range values {
+			if v >= baseline*paydaySpikeThreshold {
+				spiking++
+			}
+		}
+		magnitude := avg / baseline
+		if magnitude < paydaySpikeThreshold || spiking*2 <= len(values) {
+			continue
+		}
+
+		spikes = append(spikes, types.PaydaySpike{
+			Weekday:        key.weekday,
+			Occurrence:     key.occurrence,
+			AverageSpend:   avg,
+			BaselineSpend:  baseline,
+			Magnitude:      magnitude,
+			Periods:        len(values),
+			SpikingPeriods: spiking,
+			Description: fmt.Sprintf("Spending on the %s %s after payday is %.1fx a typical %s",
+				weekdayOrdinals[key.occurrence-1], key.weekday, magnitude, key.weekday),
+		})
+	}
+
+	sort.Slice(spikes, func(i, j int) bool {
+		if spikes[i].Magnitude == spikes[j].Magnitude {
+			if spikes[i].Weekday == spikes[j].Weekday {
+				return spikes[i].Occurrence < spikes[j].Occurrence
+			}
+			return spikes[i].Weekday < spikes[j].Weekday
+		}
+		return spikes[i].Magnitude > spikes[j].Magnitude
+	})
+
+	return spikes, nil
+}
+
+// dayOf truncates t to midnight of its calendar date in loc
+func dayOf(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
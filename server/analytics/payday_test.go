@@ -0,0 +1,47 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+	"time"
+)
+
+func TestDetectPaydayWeekdaySpikes(t *testing.T) {
+	var transactions []types.Transaction
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+	firstFriday := false
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		// Paid on the 1st and 15th
+		if d.Day() == 1 || d.Day() == 15 {
+			transactions = append(transactions, types.Transaction{Date: d, Amount: 2000, Category: "Income", Merchant: "Employer"})
+			firstFriday = true
+		}
+		transactions = append(transactions, types.Transaction{Date: d, Amount: -20, Category: "Food", Merchant: "Whole Foods"})
+		if firstFriday && d.Weekday() == time.Friday {
+			transactions = append(transactions, types.Transaction{Date: d, Amount: -180, Category: "Dining", Merchant: "Steakhouse"})
+			firstFriday = false
+		}
+	}
+
+	svc := NewService(&fakeRepo{transactions: transactions}, fixedClock("2025-04-15"))
+	got, err := svc.DetectPaydayWeekdaySpikes(context.Background(), "1234567891", 3)
+	if err != nil {
+		t.Fatalf("DetectPaydayWeekdaySpikes() failed: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("DetectPaydayWeekdaySpikes() = %+v, want only the first Friday", got)
+	}
+
+	spike := got[0]
+	if spike.Weekday != time.Friday || spike.Occurrence != 1 {
+		t.Errorf("spike on occurrence %d of %s, want the first Friday", spike.Occurrence, spike.Weekday)
+	}
+	if !approxEqual(spike.AverageSpend, 200) || !approxEqual(spike.BaselineSpend, 20) {
+		t.Errorf("AverageSpend, BaselineSpend = %v, %v, want 200, 20", spike.AverageSpend, spike.BaselineSpend)
+	}
+	if spike.SpikingPeriods != spike.Periods {
+		t.Errorf("SpikingPeriods = %d of %d, want every period", spike.SpikingPeriods, spike.Periods)
+	}
+}
@@ -0,0 +1,201 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"server/types"
+	"sort"
+	"time"
+)
+
+// The functions below are the stages GetSpendingAnalytics is built from:
+// load -> filter -> aggregate -> rank -> enrich. Each can be used on its own to
+// assemble a custom pipeline, e.g. category rankings without predictions.
+
+// LoadCategoryTotals is the load stage: it reads total spend per category for
+// the given dates
+func LoadCategoryTotals(ctx context.Context, repo Repository, accountID string, window types.DateRange) (map[string]float64, error) {
+	totals, err := repo.GetCategoryTotals(ctx, accountID, window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category totals: %w", err)
+	}
+	return totals, nil
+}
+
+// FilterCategoryTotals is the filter stage: it keeps the categories for which
+// keep returns true. A nil keep returns the totals unchanged.
+func FilterCategoryTotals(totals map[string]float64, keep func(category string, amount float64) bool) map[string]float64 {
+	if keep == nil {
+		return totals
+	}
+	filtered := make(map[string]float64, len(totals))
+	for category, amount := range totals {
+		if keep(category, amount) {
+			filtered[category] = amount
+		}
+	}
+	return filtered
+}
+
+// ApplySplits is a filter stage that moves the split portions of expenses out
+// of each transaction's own category and into the categories they were split
+// to, so totals, and the percentages built from them, still add up. Categories
+// left with nothing are dropped.
+func ApplySplits(totals map[string]float64, transactions []types.Transaction) map[string]float64 {
+	adjusted := make(map[string]float64, len(totals))
+	for category, amount := range totals {
+		adjusted[category] = amount
+	}
+	for _, t := range transactions {
+		if len(t.Splits) == 0 || expenseAmount(t) == 0 {
+			continue
+		}
+		for _, split := range t.Splits {
+			portion := -split.Amount
+			if portion <= 0 || split.Category == t.Category {
+				continue
+			}
+			adjusted[t.Category] -= portion
+			adjusted[split.Category] += portion
+		}
+	}
+	for category, amount := range adjusted {
+		if amount <= 0 {
+			delete(adjusted, category)
+		}
+	}
+	return adjusted
+}
+
+// AggregateCategories is the aggregate stage: it turns category totals into
+// CategorySpend rows formatted for the given currency and returns them with
+// the overall total
+func AggregateCategories(totals map[string]float64, currency string) ([]types.CategorySpend, float64) {
+	// Percentages need the final total, so sum everything before building rows
+	var totalSpent float64
+	for _, amount := range totals {
+		totalSpent += amount
+	}
+
+	categories := make([]types.CategorySpend, 0, len(totals))
+	for category, amount := range totals {
+		percentage := ratio(amount, totalSpent) * 100
+		categories = append(categories, types.CategorySpend{
+			Category:   category,
+			TotalSpent: FormatAmount(amount, currency),
+			Percentage: fmt.Sprintf("%.2f", percentage),
+			Amount:     amount,
+			Share:      percentage,
+		})
+	}
+	return categories, totalSpent
+}
+
+// RankCategories is the rank stage: it orders categories by amount spent and
+// keeps the top n. n <= 0 keeps every category.
+func RankCategories(categories []types.CategorySpend, n int) []types.CategorySpend {
+	return rankBy(categories, n, func(i, j int) bool {
+		if categories[i].Amount == categories[j].Amount {
+			return categories[i].Category < categories[j].Category
+		}
+		return categories[i].Amount > categories[j].Amount
+	})
+}
+
+// RankCategoriesByScore is an alternative rank stage: it orders categories by
+// a score per category, highest first, and keeps the top n. Categories without
+// a score rank last. n <= 0 keeps every category.
+func RankCategoriesByScore(categories []types.CategorySpend, scores map[string]float64, n int) []types.CategorySpend {
+	return rankBy(categories, n, func(i, j int) bool {
+		si, sj := scores[categories[i].Category], scores[categories[j].Category]
+		if si == sj {
+			return categories[i].Category < categories[j].Category
+		}
+		return si > sj
+	})
+}
+
+// PinCategories is a rank stage that moves the categories named in order to
+// the front, in that order, ahead of the rest in their existing order, then
+// keeps the top n. Names with no matching category are skipped. n <= 0 keeps
+// every category.
+func PinCategories(categories []types.CategorySpend, order []string, n int) []types.CategorySpend {
+	if len(order) > 0 {
+		position := make(map[string]int, len(order))
+		for i, category := range order {
+			if _, exists := position[category]; !exists {
+				position[category] = i
+			}
+		}
+		sort.SliceStable(categories, func(i, j int) bool {
+			pi, pinnedI := position[categories[i].Category]
+			pj, pinnedJ := position[categories[j].Category]
+			if pinnedI && pinnedJ {
+				return pi < pj
+			}
+			return pinnedI && !pinnedJ
+		})
+	}
+
+	if n > 0 && len(categories) > n {
+		categories = categories[:n]
+	}
+	return categories
+}
+
+// RecencyScores weights each category's spend by how recent it is, halving a
+// transaction's weight for every halfLife of age as of now, for use with
+// RankCategoriesByScore
+func RecencyScores(transactions []types.Transaction, now time.Time, halfLife time.Duration) map[string]float64 {
+	scores := make(map[string]float64)
+	for _, t := range transactions {
+		amount := expenseAmount(t)
+		if amount == 0 {
+			continue
+		}
+		age := now.Sub(t.Date)
+		if age < 0 {
+			age = 0
+		}
+		scores[t.Category] += amount * math.Pow(0.5, float64(age)/float64(halfLife))
+	}
+	return scores
+}
+
+// EnrichWithPatterns is an enrich stage: it attaches time-of-day and
+// day-of-week patterns for the given window
+func EnrichWithPatterns(ctx context.Context, svc Service, accountID string, startDate, endDate time.Time, analytics *types.SpendingAnalytics) error {
+	patterns, err := svc.AnalyzeTimePatterns(ctx, accountID, startDate, endDate, PatternOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to analyze time patterns: %w", err)
+	}
+	analytics.SpendingPatterns = patterns
+	return nil
+}
+
+// EnrichWithPredictions is an enrich stage: it attaches per-category spending
+// predictions
+func EnrichWithPredictions(ctx context.Context, svc Service, accountID string, analytics *types.SpendingAnalytics) error {
+	predictions, err := svc.PredictFutureSpending(ctx, accountID, PredictionOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to predict spending: %w", err)
+	}
+	analytics.PredictedSpending = predictions
+	return nil
+}
+
+// EnrichWithHealth is an enrich stage: it attaches a red/yellow/green health
+// grade to each category in the breakdown
+func EnrichWithHealth(ctx context.Context, svc Service, accountID string, analytics *types.SpendingAnalytics) error {
+	health, err := svc.GetCategoryHealth(ctx, accountID)
+	if err != nil {
+		return fmt.Errorf("failed to grade category health: %w", err)
+	}
+	for i := range analytics.TopCategories {
+		if h, ok := health[analytics.TopCategories[i].Category]; ok {
+			analytics.TopCategories[i].Health = &h
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+	"time"
+)
+
+func TestFilterCategoryTotals(t *testing.T) {
+	totals := map[string]float64{"Rent": 2260, "Dining": 310, "Books": 25}
+
+	got := FilterCategoryTotals(totals, func(category string, amount float64) bool {
+		return category != "Rent" && amount >= 50
+	})
+
+	if len(got) != 1 || got["Dining"] != 310 {
+		t.Errorf("FilterCategoryTotals() = %v, want only Dining", got)
+	}
+}
+
+func TestAggregateCategories(t *testing.T) {
+	totals := map[string]float64{"Rent": 2260, "Dining": 310.555, "Books": 25}
+
+	categories, total := AggregateCategories(totals, "USD")
+
+	if !approxEqual(total, 2595.555) {
+		t.Errorf("AggregateCategories() total = %v, want 2595.555", total)
+	}
+	if len(categories) != 3 {
+		t.Fatalf("AggregateCategories() returned %d rows, want 3", len(categories))
+	}
+	for _, c := range categories {
+		if c.Category == "Dining" && c.TotalSpent != "310.56" {
+			t.Errorf("Dining TotalSpent = %q, want 310.56", c.TotalSpent)
+		}
+	}
+
+	categories, _ = AggregateCategories(map[string]float64{"Dining": 3100.6}, "JPY")
+	if categories[0].TotalSpent != "3101" {
+		t.Errorf("JPY TotalSpent = %q, want 3101", categories[0].TotalSpent)
+	}
+}
+
+func TestAggregateCategoriesPercentages(t *testing.T) {
+	totals := map[string]float64{"Rent": 500, "Dining": 300, "Books": 200}
+	want := map[string]string{"Rent": "50.00", "Dining": "30.00", "Books": "20.00"}
+
+	// Map iteration order varies between runs, so try several
+	for run := 0; run < 20; run++ {
+		categories, _ := AggregateCategories(totals, "USD")
+		for _, c := range categories {
+			if c.Percentage != want[c.Category] {
+				t.Fatalf("run %d: %s Percentage = %s, want %s", run, c.Category, c.Percentage, want[c.Category])
+			}
+		}
+	}
+}
+
+func TestRankCategories(t *testing.T) {
+	categories := []types.CategorySpend{
+		{Category: "Books", TotalSpent: "25.00", Amount: 25},
+		{Category: "Rent", TotalSpent: "2260.00", Amount: 2260},
+		{Category: "Dining", TotalSpent: "310.00", Amount: 310},
+		{Category: "Coffee", TotalSpent: "25.00", Amount: 25},
+	}
+
+	tests := []struct {
+		name string
+		n    int
+		want []string
+	}{
+		{name: "top two", n: 2, want: []string{"Rent", "Dining"}},
+		{name: "all with ties by name", n: 0, want: []string{"Rent", "Dining", "Books", "Coffee"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RankCategories(append([]types.CategorySpend(nil), categories...), tt.n)
+			if len(got) != len(tt.want) {
+				t.Fatalf("RankCategories() returned %d rows, want %d", len(got), len(tt.want))
+			}
+			for i, c := range got {
+				if c.Category != tt.want[i] {
+					t.Errorf("RankCategories()[%d] = %s, want %s", i, c.Category, tt.want[i])
+				}
+			}
+		})
+	}
+
+	// Yen amounts are shown without decimals, which mustn't turn near
+	// amounts into ties
+	yen, _ := AggregateCategories(map[string]float64{"Books": 3100.6, "Dining": 3100.9}, "JPY")
+	if got := RankCategories(yen, 0); got[0].Category != "Dining" {
+		t.Errorf("RankCategories() of %+v put %s first, want Dining", yen, got[0].Category)
+	}
+}
+
+func TestPinCategories(t *testing.T) {
+	ranked := []types.CategorySpend{
+		{Category: "Rent", TotalSpent: "2260.00"},
+		{Category: "Dining", TotalSpent: "310.00"},
+		{Category: "Food", TotalSpent: "240.00"},
+		{Category: "Books", TotalSpent: "25.00"},
+		{Category: "Coffee", TotalSpent: "20.00"},
+	}
+
+	got := PinCategories(append([]types.CategorySpend(nil), ranked...), []string{"Coffee", "Food", "Travel"}, 0)
+	want := []string{"Coffee", "Food", "Rent", "Dining", "Books"}
+	if len(got) != len(want) {
+		t.Fatalf("PinCategories() returned %d rows, want %d", len(got), len(want))
+	}
+	for i, c := range got {
+		if c.Category != want[i] {
+			t.Errorf("PinCategories()[%d] = %s, want %s", i, c.Category, want[i])
+		}
+	}
+
+	// Pinned categories survive the cut even when they'd rank below it
+	if got := PinCategories(append([]types.CategorySpend(nil), ranked...), []string{"Coffee"}, 2); got[0].Category != "Coffee" || got[1].Category != "Rent" {
+		t.Errorf("PinCategories() top 2 = %s, %s, want Coffee, Rent", got[0].Category, got[1].Category)
+	}
+}
+
+func TestEnrichWithPredictions(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-01-01", -2260, "Rent", "Park Avenue Apartments"),
+		txn("2025-02-01", -2260, "Rent", "Park Avenue Apartments"),
+		txn("2025-03-01", -2260, "Rent", "Park Avenue Apartments"),
+	}}
+	svc := NewService(repo)
+
+	analytics := &types.SpendingAnalytics{}
+	if err := EnrichWithPredictions(context.Background(), svc, "1234567891", analytics); err != nil {
+		t.Fatalf("EnrichWithPredictions() failed: %v", err)
+	}
+	if len(analytics.PredictedSpending) != 1 || analytics.PredictedSpending[0].Category != "Rent" {
+		t.Errorf("PredictedSpending = %+v, want one Rent prediction", analytics.PredictedSpending)
+	}
+	if analytics.SpendingPatterns != nil {
+		t.Errorf("SpendingPatterns = %+v, want untouched", analytics.SpendingPatterns)
+	}
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := EnrichWithPatterns(context.Background(), svc, "1234567891", start, start.AddDate(0, 3, 0), analytics); err != nil {
+		t.Fatalf("EnrichWithPatterns() failed: %v", err)
+	}
+	if len(analytics.SpendingPatterns) == 0 {
+		t.Error("SpendingPatterns is empty, want patterns attached")
+	}
+}
@@ -0,0 +1,123 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"sort"
+)
+
+// transferWindow is how far apart the two legs of an internal transfer may post
+const transferWindow = 3 * day
+
+// PredictPortfolioSpending runs spending predictions for several accounts,
+// such as a household's, and combines them into one forecast per category.
+// Money moved between the accounts would otherwise be predicted twice, as
+// spend leaving one and arriving in the other, so internal transfers are
+// dropped before predicting. Largest category first.
+func (s *service) PredictPortfolioSpending(ctx context.Context, accountIDs []string) (*types.PortfolioForecast, error) {
+	byAccount := make(map[string][]types.Transaction, len(accountIDs))
+	for _, accountID := range accountIDs {
+		transactions, err := s.loadTransactions(ctx, accountID, s.predictionConfig.Lookback)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get transactions for account %s: %w", accountID, err)
+		}
+		byAccount[accountID] = transactions
+	}
+
+	transfers := internalTransfers(accountIDs, byAccount)
+
+	forecast := &types.PortfolioForecast{
+		AccountIDs:        accountIDs,
+		Categories:        make([]types.PortfolioCategoryForecast, 0),
+		TransfersExcluded: len(transfers) / 2,
+	}
+	byCategory := make(map[string]*types.PortfolioCategoryForecast)
+	for _, accountID := range accountIDs {
+		kept := make([]types.Transaction, 0, len(byAccount[accountID]))
+		for i, t := range byAccount[accountID] {
+			if !transfers[transferLeg{accountID, i}] {
+				kept = append(kept, t)
+			}
+		}
+
+		config, err := s.accountConfig(ctx, accountID)
+		if err != nil {
+			return nil, err
+		}
+		currency := s.configCurrency(config)
+		kept, err = s.inCurrency(ctx, kept, currency)
+		if err != nil {
+			return nil, err
+		}
+		predictions, err := s.predictFromTransactions(ctx, s.netRefunds(kept), PredictionOptions{}, currency)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range predictions {
+			c, ok := byCategory[p.Category]
+			if !ok {
+				c = &types.PortfolioCategoryForecast{Category: p.Category, PredictedDate: p.PredictedDate}
+				byCategory[p.Category] = c
+			}
+			c.Amount += p.PredictedAmount
+			c.Expected += p.PredictedAmount * p.Likelihood
+			if p.PredictedDate.Before(c.PredictedDate) {
+				c.PredictedDate = p.PredictedDate
+			}
+			c.AccountIDs = append(c.AccountIDs, accountID)
+			forecast.Total += p.PredictedAmount
+			forecast.Expected += p.PredictedAmount * p.Likelihood
+		}
+	}
+
+	for _, c := range byCategory {
+		forecast.Categories = append(forecast.Categories, *c)
+	}
+	sort.Slice(forecast.Categories, func(i, j int) bool {
+		if forecast.Categories[i].Amount == forecast.Categories[j].Amount {
+			return forecast.Categories[i].Category < forecast.Categories[j].Category
+		}
+		return forecast.Categories[i].Amount > forecast.Categories[j].Amount
+	})
+
+	return forecast, nil
+}
+
+// transferLeg identifies a transaction by account and position in its list
+type transferLeg struct {
+	accountID string
+	index     int
+}
+
+// internalTransfers pairs each outflow from one account with an inflow of the
+// same amount into another account in the set within transferWindow, and
+// returns both legs of every pair. Each inflow is matched at most once.
+func internalTransfers(accountIDs []string, byAccount map[string][]types.Transaction) map[transferLeg]bool {
+	legs := make(map[transferLeg]bool)
+	for _, from := range accountIDs {
+		for i, out := range byAccount[from] {
+			if out.Amount >= 0 {
+				continue
+			}
+		match:
+			for _, to := range accountIDs {
+				if to == from {
+					continue
+				}
+				for j, in := range byAccount[to] {
+					leg := transferLeg{to, j}
+					if legs[leg] || in.Amount <= 0 || abs(in.Amount+out.Amount) >= 0.005 {
+						continue
+					}
+					if absDuration(in.Date.Sub(out.Date)) <= transferWindow {
+						legs[transferLeg{from, i}] = true
+						legs[leg] = true
+						break match
+					}
+				}
+			}
+		}
+	}
+	return legs
+}
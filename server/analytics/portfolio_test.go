@@ -0,0 +1,75 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+)
+
+// accountsRepo serves each account its own transactions
+type accountsRepo map[string][]types.Transaction
+
+func (r accountsRepo) GetTransactions(ctx context.Context, accountID string, window types.DateRange) ([]types.Transaction, error) {
+	return r[accountID], nil
+}
+
+func (r accountsRepo) GetCategoryTotals(ctx context.Context, accountID string, window types.DateRange) (map[string]float64, error) {
+	return nil, nil
+}
+
+func (r accountsRepo) GetBalance(ctx context.Context, accountID string) (float64, error) {
+	return 0, nil
+}
+
+func TestPredictPortfolioSpending(t *testing.T) {
+	repo := accountsRepo{
+		"checking": {
+			txn("2025-01-10", -100, "Food", "Whole Foods"),
+			txn("2025-02-10", -100, "Food", "Whole Foods"),
+			txn("2025-03-10", -100, "Food", "Whole Foods"),
+			txn("2025-01-01", -2000, "Rent", "Park Avenue Apartments"),
+			txn("2025-02-01", -2000, "Rent", "Park Avenue Apartments"),
+			txn("2025-03-01", -2000, "Rent", "Park Avenue Apartments"),
+			// Monthly top-up of the joint account
+			txn("2025-01-15", -500, "Transfer", "Joint Account"),
+			txn("2025-02-15", -500, "Transfer", "Joint Account"),
+			txn("2025-03-15", -500, "Transfer", "Joint Account"),
+		},
+		"joint": {
+			txn("2025-01-12", -60, "Food", "Trader Joe's"),
+			txn("2025-02-12", -60, "Food", "Trader Joe's"),
+			txn("2025-03-12", -60, "Food", "Trader Joe's"),
+			txn("2025-01-16", 500, "Transfer", "Checking"),
+			txn("2025-02-16", 500, "Transfer", "Checking"),
+			txn("2025-03-16", 500, "Transfer", "Checking"),
+		},
+	}
+	svc := NewService(repo, fixedClock("2025-03-20"))
+
+	got, err := svc.PredictPortfolioSpending(context.Background(), []string{"checking", "joint"})
+	if err != nil {
+		t.Fatalf("PredictPortfolioSpending() failed: %v", err)
+	}
+
+	if got.TransfersExcluded != 3 {
+		t.Errorf("TransfersExcluded = %d, want 3", got.TransfersExcluded)
+	}
+	if len(got.Categories) != 2 {
+		t.Fatalf("Categories = %+v, want Rent and Food only", got.Categories)
+	}
+
+	rent, food := got.Categories[0], got.Categories[1]
+	if rent.Category != "Rent" || !approxEqual(rent.Amount, 2000) || len(rent.AccountIDs) != 1 {
+		t.Errorf("Categories[0] = %+v, want Rent at 2000 from one account", rent)
+	}
+	if food.Category != "Food" || !approxEqual(food.Amount, 160) || len(food.AccountIDs) != 2 {
+		t.Errorf("Categories[1] = %+v, want Food at 160 from both accounts", food)
+	}
+	// Checking's groceries come round every 29.5 days, before joint's
+	if want := txn("2025-03-10", 0, "", "").Date.Add(59 * day / 2); !food.PredictedDate.Equal(want) {
+		t.Errorf("Food PredictedDate = %v, want the earlier account's %v", food.PredictedDate, want)
+	}
+	if !approxEqual(got.Total, 2160) {
+		t.Errorf("Total = %v, want 2160", got.Total)
+	}
+}
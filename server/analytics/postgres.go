@@ -18,25 +18,25 @@ func NewPostgresRepository(db *sql.DB) Repository {
 	return &postgresRepo{db: db}
 }
 
-func (r *postgresRepo) GetTransactions(ctx context.Context, accountID string, timeRange string) ([]types.Transaction, error) {
+func (r *postgresRepo) GetTransactions(ctx context.Context, accountID string, window types.DateRange) ([]types.Transaction, error) {
 	if accountID == "" {
 		return nil, fmt.Errorf("account ID is required")
 	}
 
 	query := `
-		SELECT transaction_id, account_id, date, amount, category, merchant, location
+		SELECT transaction_id, account_id, date, amount, category, merchant, location, rewards, currency
 		FROM transactions 
 		WHERE account_id = $1 
-		  AND date >= NOW() - $2::INTERVAL
+		  AND date BETWEEN $2 AND $3
 		ORDER BY date DESC`
 	
-	rows, err := r.db.QueryContext(ctx, query, accountID, timeRange)
+	rows, err := r.db.QueryContext(ctx, query, accountID, window.Start, window.End)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query transactions: %w", err)
 	}
 	defer rows.Close()
 
-	var transactions []types.Transaction
+	transactions := make([]types.Transaction, 0)
 	for rows.Next() {
 		var t types.Transaction
 		if err := rows.Scan(
@@ -47,6 +47,8 @@ func (r *postgresRepo) GetTransactions(ctx context.Context, accountID string, ti
 			&t.Category,
 			&t.Merchant,
 			&t.Location,
+			&t.Rewards,
+			&t.Currency,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan transaction: %w", err)
 		}
@@ -60,20 +62,21 @@ func (r *postgresRepo) GetTransactions(ctx context.Context, accountID string, ti
 	return transactions, nil
 }
 
-func (r *postgresRepo) GetCategoryTotals(ctx context.Context, accountID string, timeRange string) (map[string]float64, error) {
+func (r *postgresRepo) GetCategoryTotals(ctx context.Context, accountID string, window types.DateRange) (map[string]float64, error) {
 	if accountID == "" {
 		return nil, fmt.Errorf("account ID is required")
 	}
 
 	query := `
-		SELECT category, COALESCE(SUM(ABS(amount)), 0) as total
+		SELECT category, COALESCE(SUM(-amount), 0) as total
 		FROM transactions 
 		WHERE account_id = $1 
-		  AND date >= NOW() - $2::INTERVAL
+		  AND amount < 0
+		  AND date BETWEEN $2 AND $3
 		GROUP BY category
 		ORDER BY total DESC`
 	
-	rows, err := r.db.QueryContext(ctx, query, accountID, timeRange)
+	rows, err := r.db.QueryContext(ctx, query, accountID, window.Start, window.End)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query category totals: %w", err)
 	}
@@ -94,4 +97,22 @@ func (r *postgresRepo) GetCategoryTotals(ctx context.Context, accountID string,
 	}
 
 	return categoryTotals, nil
+}
+
+func (r *postgresRepo) GetBalance(ctx context.Context, accountID string) (float64, error) {
+	if accountID == "" {
+		return 0, fmt.Errorf("account ID is required")
+	}
+
+	query := `
+		SELECT balance_current
+		FROM users
+		WHERE account_id = $1`
+
+	var balance float64
+	if err := r.db.QueryRowContext(ctx, query, accountID).Scan(&balance); err != nil {
+		return 0, fmt.Errorf("failed to query balance: %w", err)
+	}
+
+	return balance, nil
 } 
\ No newline at end of file
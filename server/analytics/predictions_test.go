@@ -0,0 +1,624 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"server/types"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPredictFutureSpendingIsReproducible(t *testing.T) {
+	var transactions []types.Transaction
+	// Several categories with identical histories tie on likelihood
+	for _, category := range []string{"Books", "Clothing", "Electronics", "Home", "Other"} {
+		transactions = append(transactions,
+			txn("2025-01-10", -40, category, category+" Store"),
+			txn("2025-02-10", -40, category, category+" Store"),
+			txn("2025-03-10", -40, category, category+" Store"),
+		)
+	}
+	repo := &fakeRepo{transactions: transactions}
+
+	run := func() []types.PredictedSpend {
+		svc := NewService(repo, fixedClock("2025-03-20"), WithSeed(42))
+		got, err := svc.PredictFutureSpending(context.Background(), "1234567891", PredictionOptions{})
+		if err != nil {
+			t.Fatalf("PredictFutureSpending() failed: %v", err)
+		}
+		return got
+	}
+
+	first := run()
+	for i := 0; i < 5; i++ {
+		if got := run(); !reflect.DeepEqual(got, first) {
+			t.Fatalf("PredictFutureSpending() = %+v, want %+v", got, first)
+		}
+	}
+}
+
+func TestPredictFutureSpendingCadence(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-03-03", -6, "Coffee", "Starbucks"),
+		txn("2025-03-10", -5, "Coffee", "Starbucks"),
+		txn("2025-03-17", -6, "Coffee", "Starbucks"),
+		txn("2025-03-24", -5, "Coffee", "Starbucks"),
+		txn("2025-01-01", -2260, "Rent", "Park Avenue Apartments"),
+		txn("2025-01-31", -2260, "Rent", "Park Avenue Apartments"),
+		txn("2025-03-02", -2260, "Rent", "Park Avenue Apartments"),
+		txn("2025-01-04", -80, "Clothing", "Uniqlo"),
+		txn("2025-01-06", -45, "Clothing", "Uniqlo"),
+		txn("2025-03-20", -60, "Clothing", "Uniqlo"),
+	}}
+	svc := NewService(repo, fixedClock("2025-03-25"))
+
+	got, err := svc.PredictFutureSpending(context.Background(), "1234567891", PredictionOptions{})
+	if err != nil {
+		t.Fatalf("PredictFutureSpending() failed: %v", err)
+	}
+
+	want := map[string]types.Cadence{
+		"Coffee":   types.CadenceWeekly,
+		"Rent":     types.CadenceMonthly,
+		"Clothing": types.CadenceIrregular,
+	}
+	for _, p := range got {
+		if p.Cadence != want[p.Category] {
+			t.Errorf("%s cadence = %q, want %q", p.Category, p.Cadence, want[p.Category])
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("PredictFutureSpending() returned %d predictions, want %d", len(got), len(want))
+	}
+}
+
+func TestCalendarDays(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     int
+	}{
+		{from: "2024-02-01", to: "2024-03-01", want: 29}, // leap year
+		{from: "2023-02-01", to: "2023-03-01", want: 28},
+		{from: "2023-11-01", to: "2024-03-01", want: 121},
+		{from: "2024-01-01", to: "2025-01-01", want: 366},
+		{from: "2025-03-10", to: "2025-03-10", want: 0},
+	}
+	for _, tt := range tests {
+		if got := calendarDays(txn(tt.from, 0, "", "").Date, txn(tt.to, 0, "", "").Date); got != tt.want {
+			t.Errorf("calendarDays(%s, %s) = %d, want %d", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestPredictFutureSpendingLeapYearFrequency(t *testing.T) {
+	// Three charges over 121 days, including the 29 days of February 2024
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2023-11-01", -500, "Insurance", "State Farm"),
+		txn("2024-01-01", -500, "Insurance", "State Farm"),
+		txn("2024-03-01", -500, "Insurance", "State Farm"),
+	}}
+	got, err := NewService(repo, fixedClock("2024-03-20")).PredictFutureSpending(context.Background(), "1234567891", PredictionOptions{})
+	if err != nil {
+		t.Fatalf("PredictFutureSpending() failed: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("PredictFutureSpending() returned %d predictions, want 1", len(got))
+	}
+
+	normalizedFreq := 3.0 / 121 * 30
+	want := (normalizedFreq + 0.5) / 2
+	if math.Abs(got[0].Likelihood-want) > 1e-9 {
+		t.Errorf("Likelihood = %v, want %v from a 121-day span", got[0].Likelihood, want)
+	}
+}
+
+func TestPredictFutureSpendingIncludeTransactions(t *testing.T) {
+	rent := []types.Transaction{
+		txn("2025-03-01", -2260, "Rent", "Park Avenue Apartments"),
+		txn("2025-01-01", -2260, "Rent", "Park Avenue Apartments"),
+		txn("2025-02-01", -2260, "Rent", "Park Avenue Apartments"),
+	}
+	repo := &fakeRepo{transactions: append([]types.Transaction{
+		txn("2025-02-14", -80, "Dining", "Chipotle"),
+	}, rent...)}
+	svc := NewService(repo)
+
+	got, err := svc.PredictFutureSpending(context.Background(), "1234567891", PredictionOptions{})
+	if err != nil {
+		t.Fatalf("PredictFutureSpending() failed: %v", err)
+	}
+	if got[0].Basis != nil {
+		t.Errorf("Basis = %+v, want nil by default", got[0].Basis)
+	}
+
+	got, err = svc.PredictFutureSpending(context.Background(), "1234567891", PredictionOptions{IncludeTransactions: true})
+	if err != nil {
+		t.Fatalf("PredictFutureSpending() failed: %v", err)
+	}
+	if len(got) != 1 || got[0].Basis == nil {
+		t.Fatalf("PredictFutureSpending() = %+v, want one prediction with a basis", got)
+	}
+
+	basis := got[0].Basis
+	want := []types.Transaction{rent[1], rent[2], rent[0]}
+	if !reflect.DeepEqual(basis.Transactions, want) {
+		t.Errorf("Basis.Transactions = %+v, want %+v", basis.Transactions, want)
+	}
+	if basis.Count != 3 || !basis.From.Equal(want[0].Date) || !basis.To.Equal(want[2].Date) {
+		t.Errorf("Basis = %d from %v to %v, want 3 from %v to %v", basis.Count, basis.From, basis.To, want[0].Date, want[2].Date)
+	}
+}
+
+func TestPredictFutureSpendingCountdown(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		// Next Netflix charge expected 2025-04-04, 29.5 days after the last
+		txn("2025-01-05", -15.99, "Entertainment", "Netflix"),
+		txn("2025-02-05", -15.99, "Entertainment", "Netflix"),
+		txn("2025-03-05", -15.99, "Entertainment", "Netflix"),
+		// Next gym charge expected 2025-03-31
+		txn("2024-12-28", -50, "Fitness", "Equinox"),
+		txn("2025-01-28", -50, "Fitness", "Equinox"),
+		txn("2025-02-28", -50, "Fitness", "Equinox"),
+	}}
+	svc := NewService(repo, fixedClock("2025-04-01"))
+
+	got, err := svc.PredictFutureSpending(context.Background(), "1234567891", PredictionOptions{})
+	if err != nil {
+		t.Fatalf("PredictFutureSpending() failed: %v", err)
+	}
+
+	want := map[string]struct {
+		days    int
+		overdue bool
+	}{
+		"Entertainment": {days: 3, overdue: false},
+		"Fitness":       {days: 0, overdue: true},
+	}
+	for _, p := range got {
+		w := want[p.Category]
+		if p.DaysUntil != w.days || p.Overdue != w.overdue {
+			t.Errorf("%s DaysUntil, Overdue = %d, %v, want %d, %v", p.Category, p.DaysUntil, p.Overdue, w.days, w.overdue)
+		}
+	}
+}
+
+func TestPredictSpendingTotal(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		// Frequent and large: high likelihood
+		txn("2025-01-01", -2000, "Rent", "Park Avenue Apartments"),
+		txn("2025-02-01", -2000, "Rent", "Park Avenue Apartments"),
+		txn("2025-03-01", -2000, "Rent", "Park Avenue Apartments"),
+		// Rare and small: low likelihood
+		txn("2024-10-01", -100, "Gifts", "Etsy"),
+		txn("2025-01-01", -100, "Gifts", "Etsy"),
+		txn("2025-04-01", -100, "Gifts", "Etsy"),
+	}}
+	got, err := NewService(repo).PredictSpendingTotal(context.Background(), "1234567891")
+	if err != nil {
+		t.Fatalf("PredictSpendingTotal() failed: %v", err)
+	}
+
+	if !approxEqual(got.Unweighted, 2100) {
+		t.Errorf("Unweighted = %v, want 2100", got.Unweighted)
+	}
+	var wantWeighted float64
+	for _, p := range got.Predictions {
+		wantWeighted += p.PredictedAmount * p.Likelihood
+	}
+	if !approxEqual(got.Weighted, wantWeighted) {
+		t.Errorf("Weighted = %v, want %v", got.Weighted, wantWeighted)
+	}
+	if got.Weighted >= got.Unweighted {
+		t.Errorf("Weighted = %v, want below Unweighted %v", got.Weighted, got.Unweighted)
+	}
+
+	// Rent is certain, so only the uncertain Gifts prediction is downweighted
+	for _, p := range got.Predictions {
+		if p.Category == "Gifts" && p.Likelihood >= 0.5 {
+			t.Errorf("Gifts likelihood = %v, want a low-confidence prediction", p.Likelihood)
+		}
+	}
+}
+
+func TestPredictFutureSpendingSkipsCredits(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-01-15", 3000, "Income", "Acme Payroll"),
+		txn("2025-02-15", 3000, "Income", "Acme Payroll"),
+		txn("2025-03-15", 3000, "Income", "Acme Payroll"),
+		txn("2025-01-01", -2260, "Rent", "Park Avenue Apartments"),
+		txn("2025-02-01", -2260, "Rent", "Park Avenue Apartments"),
+		txn("2025-03-01", -2260, "Rent", "Park Avenue Apartments"),
+	}}
+	svc := NewService(repo, fixedClock("2025-03-20"))
+
+	got, err := svc.PredictFutureSpending(context.Background(), "1234567891", PredictionOptions{})
+	if err != nil {
+		t.Fatalf("PredictFutureSpending() failed: %v", err)
+	}
+	if len(got) != 1 || got[0].Category != "Rent" {
+		t.Errorf("PredictFutureSpending() = %+v, want only Rent", got)
+	}
+}
+
+func TestPredictFutureSpendingAmountRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		amounts []float64
+		wide    bool
+	}{
+		{name: "steady", amounts: []float64{100, 110, 90}},
+		{name: "erratic", amounts: []float64{10, 500, 50}, wide: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dates := []string{"2025-01-10", "2025-02-10", "2025-03-10"}
+			var transactions []types.Transaction
+			for i, amount := range tt.amounts {
+				transactions = append(transactions, txn(dates[i], -amount, "Shopping", "Target"))
+			}
+			svc := NewService(&fakeRepo{transactions: transactions}, fixedClock("2025-03-20"))
+
+			got, err := svc.PredictFutureSpending(context.Background(), "1234567891", PredictionOptions{})
+			if err != nil {
+				t.Fatalf("PredictFutureSpending() failed: %v", err)
+			}
+			if len(got) != 1 {
+				t.Fatalf("PredictFutureSpending() = %+v, want one prediction", got)
+			}
+
+			p := got[0]
+			if math.Abs(p.PredictedAmount-mean(tt.amounts)) > 0.01 {
+				t.Errorf("Amount = %v, want %v", p.PredictedAmount, mean(tt.amounts))
+			}
+			if p.AmountLow < 0 || p.AmountLow > p.PredictedAmount || p.AmountHigh < p.PredictedAmount {
+				t.Errorf("range %v-%v doesn't bracket %v", p.AmountLow, p.AmountHigh, p.PredictedAmount)
+			}
+			width := p.AmountHigh - p.AmountLow
+			if tt.wide && width < p.PredictedAmount {
+				t.Errorf("range %v-%v is too tight for amounts %v", p.AmountLow, p.AmountHigh, tt.amounts)
+			}
+			if !tt.wide && width > p.PredictedAmount*0.2 {
+				t.Errorf("range %v-%v is too wide for amounts %v", p.AmountLow, p.AmountHigh, tt.amounts)
+			}
+		})
+	}
+}
+
+func TestPredictionWarningMentionsAmount(t *testing.T) {
+	p := types.PredictedSpend{
+		Category:        "Rent",
+		Likelihood:      0.9,
+		PredictedDate:   txn("2025-04-01", 0, "", "").Date,
+		PredictedAmount: 2260,
+		AmountLow:       2200,
+		AmountHigh:      2320,
+	}
+	got := predictionWarning(p, "USD")
+	if got == nil {
+		t.Fatal("predictionWarning() = nil, want a warning")
+	}
+	if got.Severity != types.WarningHigh || got.Category != "Rent" || !got.PredictedDate.Equal(p.PredictedDate) ||
+		got.Likelihood != 0.9 || got.Amount != 2260 || got.AmountLow != 2200 || got.AmountHigh != 2320 {
+		t.Errorf("predictionWarning() = %+v, want the prediction's details at high severity", got)
+	}
+	want := "High likelihood (90%) of spending $2260.00 ($2200.00-$2320.00) in Rent category around Apr 01"
+	if got.String() != want {
+		t.Errorf("predictionWarning().String() = %q, want %q", got.String(), want)
+	}
+
+	// Other currencies are written with their own precision and code
+	p.PredictedAmount, p.AmountLow, p.AmountHigh = 234567.4, 230000, 239000.6
+	if got := predictionWarning(p, "JPY").String(); got != "High likelihood (90%) of spending 234567 JPY (230000 JPY-239001 JPY) in Rent category around Apr 01" {
+		t.Errorf("JPY predictionWarning().String() = %q", got)
+	}
+
+	p.Likelihood = 0.4
+	if got := predictionWarning(p, "USD"); got != nil {
+		t.Errorf("predictionWarning() = %+v, want none for a low likelihood", got)
+	}
+}
+
+func TestPredictFutureSpendingWarningCurrency(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-01-01", -226000, "Rent", "Landlord"),
+		txn("2025-02-01", -226000, "Rent", "Landlord"),
+		txn("2025-03-01", -226000, "Rent", "Landlord"),
+	}}
+	svc := NewService(repo, fixedClock("2025-03-20"), WithCurrency("JPY"))
+
+	got, err := svc.PredictFutureSpending(context.Background(), "1234567891", PredictionOptions{})
+	if err != nil {
+		t.Fatalf("PredictFutureSpending() failed: %v", err)
+	}
+	if len(got) != 1 || got[0].Warning == nil || got[0].Warning.Currency != "JPY" {
+		t.Fatalf("PredictFutureSpending() = %+v, want a Rent warning in JPY", got)
+	}
+	if text := got[0].Warning.String(); !strings.Contains(text, "226000 JPY") {
+		t.Errorf("Warning.String() = %q, want the amount in yen", text)
+	}
+}
+
+func TestPredictFutureSpendingConvertsCurrency(t *testing.T) {
+	var transactions []types.Transaction
+	for _, date := range []string{"2025-01-01", "2025-02-01", "2025-03-01"} {
+		rent := txn(date, -1500, "Rent", "Landlord")
+		rent.Currency = "USD"
+		transactions = append(transactions, rent)
+	}
+	svc := NewService(&fakeRepo{transactions: transactions}, fixedClock("2025-03-20"),
+		WithCurrency("JPY"), WithRateProvider(fixedRates{"USD/JPY": 150}))
+
+	got, err := svc.PredictFutureSpending(context.Background(), "1234567891", PredictionOptions{})
+	if err != nil {
+		t.Fatalf("PredictFutureSpending() failed: %v", err)
+	}
+	if len(got) != 1 || !approxEqual(got[0].PredictedAmount, 225000) {
+		t.Fatalf("PredictFutureSpending() = %+v, want Rent at 225000 yen", got)
+	}
+	if got[0].Warning == nil || !strings.Contains(got[0].Warning.String(), "225000 JPY") {
+		t.Errorf("Warning = %+v, want the converted amount in yen", got[0].Warning)
+	}
+}
+
+func TestPredictFutureSpendingDecaysOverdue(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		// Stopped after January, so the February charge is long overdue
+		txn("2024-11-01", -50, "Gym", "Planet Fitness"),
+		txn("2024-12-01", -50, "Gym", "Planet Fitness"),
+		txn("2025-01-01", -50, "Gym", "Planet Fitness"),
+		txn("2025-01-01", -50, "Streaming", "Netflix"),
+		txn("2025-02-01", -50, "Streaming", "Netflix"),
+		txn("2025-03-01", -50, "Streaming", "Netflix"),
+	}}
+	svc := NewService(repo, fixedClock("2025-03-20"))
+
+	got, err := svc.PredictFutureSpending(context.Background(), "1234567891", PredictionOptions{})
+	if err != nil {
+		t.Fatalf("PredictFutureSpending() failed: %v", err)
+	}
+	byCategory := make(map[string]types.PredictedSpend)
+	for _, p := range got {
+		byCategory[p.Category] = p
+	}
+
+	gym, streaming := byCategory["Gym"], byCategory["Streaming"]
+	if !gym.Overdue || streaming.Overdue {
+		t.Fatalf("Overdue = %v for Gym and %v for Streaming, want only Gym", gym.Overdue, streaming.Overdue)
+	}
+	if gym.Likelihood >= streaming.Likelihood/2 {
+		t.Errorf("Gym Likelihood = %v, want well below on-schedule Streaming at %v", gym.Likelihood, streaming.Likelihood)
+	}
+	if got[0].Category != "Streaming" {
+		t.Errorf("most likely = %s, want Streaming ahead of the stale Gym", got[0].Category)
+	}
+}
+
+func TestPredictFutureSpendingTrend(t *testing.T) {
+	dates := []string{"2024-10-10", "2024-11-10", "2024-12-10", "2025-01-10", "2025-02-10"}
+	tests := []struct {
+		name      string
+		amounts   []float64
+		slope     float64
+		intercept float64
+	}{
+		{name: "increasing", amounts: []float64{100, 150, 200, 250, 300}, slope: 50, intercept: 100},
+		{name: "decreasing", amounts: []float64{300, 250, 200, 150, 100}, slope: -50, intercept: 300},
+	}
+	likelihoods := make(map[string]float64)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var txns []types.Transaction
+			for i, amount := range tt.amounts {
+				txns = append(txns, txn(dates[i], -amount, "Utilities", "Con Edison"))
+			}
+			svc := NewService(&fakeRepo{transactions: txns}, fixedClock("2025-03-05"))
+
+			got, err := svc.PredictFutureSpending(context.Background(), "1234567891", PredictionOptions{})
+			if err != nil {
+				t.Fatalf("PredictFutureSpending() failed: %v", err)
+			}
+			if len(got) != 1 {
+				t.Fatalf("PredictFutureSpending() = %+v, want one prediction", got)
+			}
+			p := got[0]
+			if !approxEqual(p.TrendSlope, tt.slope) || !approxEqual(p.TrendIntercept, tt.intercept) {
+				t.Errorf("trend = %v + %v*x, want %v + %v*x", p.TrendIntercept, p.TrendSlope, tt.intercept, tt.slope)
+			}
+
+			untrended := predictCategory("Utilities", txns, AverageMean, DefaultPredictionConfig)
+			switch {
+			case tt.slope < 0 && p.Likelihood >= untrended.Likelihood:
+				t.Errorf("Likelihood = %v, want below the untrended %v", p.Likelihood, untrended.Likelihood)
+			case tt.slope < 0 && !p.PredictedDate.After(untrended.PredictedDate):
+				t.Errorf("PredictedDate = %v, want later than the untrended %v", p.PredictedDate, untrended.PredictedDate)
+			case tt.slope > 0 && p.Likelihood < untrended.Likelihood:
+				t.Errorf("Likelihood = %v, want at least the untrended %v", p.Likelihood, untrended.Likelihood)
+			}
+			likelihoods[tt.name] = p.Likelihood
+		})
+	}
+	if likelihoods["decreasing"] >= likelihoods["increasing"] {
+		t.Errorf("likelihoods = %v, want the decreasing series less likely", likelihoods)
+	}
+}
+
+func TestPredictFutureSpendingColdStart(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-01-01", -2260, "Rent", "Park Avenue Apartments"),
+		txn("2025-01-31", -2260, "Rent", "Park Avenue Apartments"),
+		txn("2025-03-02", -2260, "Rent", "Park Avenue Apartments"),
+		// A new category, seen twice 20 days apart
+		txn("2025-02-20", -60, "Pets", "Chewy"),
+		txn("2025-03-12", -40, "Pets", "Chewy"),
+		// A one-off is still left out
+		txn("2025-02-14", -80, "Dining", "Le Bernardin"),
+	}}
+	svc := NewService(repo, fixedClock("2025-03-15"))
+
+	got, err := svc.PredictFutureSpending(context.Background(), "1234567891", PredictionOptions{})
+	if err != nil {
+		t.Fatalf("PredictFutureSpending() failed: %v", err)
+	}
+	byCategory := make(map[string]types.PredictedSpend)
+	for _, p := range got {
+		byCategory[p.Category] = p
+	}
+	if len(got) != 2 {
+		t.Fatalf("PredictFutureSpending() = %+v, want Rent and Pets", got)
+	}
+
+	pets, rent := byCategory["Pets"], byCategory["Rent"]
+	if !pets.ColdStart || rent.ColdStart {
+		t.Errorf("ColdStart = %v for Pets and %v for Rent, want only Pets", pets.ColdStart, rent.ColdStart)
+	}
+	if pets.Likelihood >= rent.Likelihood || pets.Likelihood <= 0 {
+		t.Errorf("Pets Likelihood = %v, want a low confidence below Rent's %v", pets.Likelihood, rent.Likelihood)
+	}
+	// 20 observed days averaged with Rent's 30-day cadence
+	want := txn("2025-03-12", 0, "", "").Date.Add(25 * day)
+	if !pets.PredictedDate.Equal(want) {
+		t.Errorf("Pets PredictedDate = %v, want %v", pets.PredictedDate, want)
+	}
+	if !approxEqual(pets.PredictedAmount, 50) {
+		t.Errorf("Pets Amount = %v, want 50", pets.PredictedAmount)
+	}
+}
+
+func TestPredictFutureSpendingCancelled(t *testing.T) {
+	var transactions []types.Transaction
+	for i := 0; i < 5000; i++ {
+		transactions = append(transactions, txn("2025-03-01", -10, fmt.Sprintf("Category %d", i%50), "Shop"))
+	}
+	svc := NewService(&fakeRepo{transactions: transactions}, fixedClock("2025-04-15"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	got, err := svc.PredictFutureSpending(ctx, "1234567891", PredictionOptions{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("PredictFutureSpending() error = %v, want context.Canceled", err)
+	}
+	if got != nil {
+		t.Errorf("PredictFutureSpending() = %d predictions, want none", len(got))
+	}
+}
+
+func TestPredictFutureSpendingAverage(t *testing.T) {
+	var transactions []types.Transaction
+	for week := 0; week < 9; week++ {
+		transactions = append(transactions, txn(fmt.Sprintf("2025-03-%02d", 1+week*3), -50, "Shopping", "Target"))
+	}
+	// One purchase ten times the usual size
+	transactions = append(transactions, txn("2025-03-29", -500, "Shopping", "Target"))
+	svc := NewService(&fakeRepo{transactions: transactions}, fixedClock("2025-03-30"))
+
+	for _, tt := range []struct {
+		average AmountAverage
+		amount  float64
+	}{
+		{average: "", amount: 95},
+		{average: AverageMean, amount: 95},
+		{average: AverageMedian, amount: 50},
+		{average: AverageTrimmed, amount: 50},
+	} {
+		t.Run(string(tt.average), func(t *testing.T) {
+			got, err := svc.PredictFutureSpending(context.Background(), "1234567891", PredictionOptions{Average: tt.average})
+			if err != nil {
+				t.Fatalf("PredictFutureSpending() failed: %v", err)
+			}
+			if len(got) != 1 || !approxEqual(got[0].PredictedAmount, tt.amount) {
+				t.Errorf("PredictFutureSpending() = %+v, want Shopping at %v", got, tt.amount)
+			}
+		})
+	}
+
+	mean, err := svc.PredictFutureSpending(context.Background(), "1234567891", PredictionOptions{})
+	if err != nil {
+		t.Fatalf("PredictFutureSpending() failed: %v", err)
+	}
+	median, err := svc.PredictFutureSpending(context.Background(), "1234567891", PredictionOptions{Average: AverageMedian})
+	if err != nil {
+		t.Fatalf("PredictFutureSpending() failed: %v", err)
+	}
+	if median[0].Likelihood >= mean[0].Likelihood {
+		t.Errorf("median Likelihood = %v, want below the outlier-inflated mean's %v", median[0].Likelihood, mean[0].Likelihood)
+	}
+
+	if _, err := svc.PredictFutureSpending(context.Background(), "1234567891", PredictionOptions{Average: "mode"}); !errors.Is(err, ErrInvalidAverage) {
+		t.Errorf("PredictFutureSpending() error = %v, want ErrInvalidAverage", err)
+	}
+}
+
+func TestPredictFutureSpendingColdStartAverage(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-02-20", -60, "Pets", "Chewy"),
+		txn("2025-03-12", -40, "Pets", "Chewy"),
+	}}
+	svc := NewService(repo, fixedClock("2025-03-15"))
+
+	// Every average of two amounts is their midpoint
+	for _, average := range []AmountAverage{AverageMean, AverageMedian, AverageTrimmed} {
+		got, err := svc.PredictFutureSpending(context.Background(), "1234567891", PredictionOptions{Average: average})
+		if err != nil {
+			t.Fatalf("PredictFutureSpending(%s) failed: %v", average, err)
+		}
+		if len(got) != 1 || !got[0].ColdStart || !approxEqual(got[0].PredictedAmount, 50) {
+			t.Errorf("PredictFutureSpending(%s) = %+v, want a cold-start Pets at 50", average, got)
+		}
+	}
+
+	if _, err := svc.PredictFutureSpending(context.Background(), "1234567891", PredictionOptions{Average: "mode"}); !errors.Is(err, ErrInvalidAverage) {
+		t.Errorf("PredictFutureSpending() error = %v, want ErrInvalidAverage", err)
+	}
+}
+
+func TestWithPredictionConfigHighSpender(t *testing.T) {
+	var transactions []types.Transaction
+	for category, amount := range map[string]float64{"Travel": 2500, "Tuition": 6000, "Mortgage": 12000} {
+		for _, date := range []string{"2025-01-05", "2025-02-05", "2025-03-05"} {
+			transactions = append(transactions, txn(date, -amount, category, category+" Co"))
+		}
+	}
+	repo := &fakeRepo{transactions: transactions}
+
+	likelihoods := func(opts ...Option) map[string]float64 {
+		svc := NewService(repo, append(opts, fixedClock("2025-03-10"))...)
+		got, err := svc.PredictFutureSpending(context.Background(), "1234567891", PredictionOptions{})
+		if err != nil {
+			t.Fatalf("PredictFutureSpending() failed: %v", err)
+		}
+		byCategory := make(map[string]float64)
+		for _, p := range got {
+			byCategory[p.Category] = p.Likelihood
+		}
+		return byCategory
+	}
+
+	saturated := likelihoods()
+	if !approxEqual(saturated["Travel"], saturated["Mortgage"]) {
+		t.Errorf("default likelihoods = %v, want every category pinned at the same amount cap", saturated)
+	}
+
+	spread := likelihoods(WithPredictionConfig(PredictionConfig{AmountCap: 20000}))
+	if !(spread["Travel"] < spread["Tuition"] && spread["Tuition"] < spread["Mortgage"]) {
+		t.Errorf("high-spender likelihoods = %v, want them ordered by amount", spread)
+	}
+	if spread["Mortgage"] >= saturated["Mortgage"] {
+		t.Errorf("high-spender Mortgage likelihood = %v, want below the saturated %v", spread["Mortgage"], saturated["Mortgage"])
+	}
+}
+
+func TestWithPredictionConfigLookback(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewService(repo, fixedClock("2025-03-10"), WithPredictionConfig(PredictionConfig{Lookback: "1 year"}))
+
+	if _, err := svc.PredictFutureSpending(context.Background(), "1234567891", PredictionOptions{}); err != nil {
+		t.Fatalf("PredictFutureSpending() failed: %v", err)
+	}
+	if len(repo.ranges) != 1 || !repo.ranges[0].Start.Equal(time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("fetched %+v, want a year of history", repo.ranges)
+	}
+}
@@ -0,0 +1,43 @@
+package analytics
+
+import "server/types"
+
+const (
+	// lowDataTransactions and lowDataDays are the counts below which either
+	// makes an analysis low confidence
+	lowDataTransactions = 20
+	lowDataDays         = 30
+	// highDataTransactions and highDataDays must both be reached for an
+	// analysis to be high confidence
+	highDataTransactions = 60
+	highDataDays         = 90
+)
+
+// gradeDataQuality describes the transactions behind an analysis and how much
+// confidence they support: low for under 30 days or 20 transactions, high
+// for 90 days or more with at least 60, and medium in between
+func gradeDataQuality(transactions []types.Transaction) *types.DataQuality {
+	quality := &types.DataQuality{Transactions: len(transactions), Confidence: types.DataLow}
+	if len(transactions) > 0 {
+		from, to := transactions[0].Date, transactions[0].Date
+		for _, t := range transactions[1:] {
+			if t.Date.Before(from) {
+				from = t.Date
+			}
+			if t.Date.After(to) {
+				to = t.Date
+			}
+		}
+		quality.From, quality.To = &from, &to
+		quality.DaysCovered = calendarDays(from, to) + 1
+	}
+
+	switch {
+	case quality.Transactions < lowDataTransactions || quality.DaysCovered < lowDataDays:
+	case quality.Transactions >= highDataTransactions && quality.DaysCovered >= highDataDays:
+		quality.Confidence = types.DataHigh
+	default:
+		quality.Confidence = types.DataMedium
+	}
+	return quality
+}
@@ -0,0 +1,59 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+	"time"
+)
+
+func TestGetSpendingAnalyticsDataQuality(t *testing.T) {
+	dense := make([]types.Transaction, 0)
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	for d := 0; d < 100; d++ {
+		tx := txn("2025-01-01", -12, "Food", "Chipotle")
+		tx.Date = start.AddDate(0, 0, d)
+		dense = append(dense, tx)
+	}
+
+	tests := []struct {
+		name         string
+		transactions []types.Transaction
+		timeRange    string
+		want         types.DataQuality
+	}{
+		{
+			name: "sparse",
+			transactions: []types.Transaction{
+				txn("2025-04-02", -30, "Food", "Chipotle"),
+				txn("2025-04-06", -20, "Gas", "Shell"),
+				txn("2025-04-09", -15, "Food", "Chipotle"),
+			},
+			timeRange: "1 month",
+			want:      types.DataQuality{Transactions: 3, DaysCovered: 8, Confidence: types.DataLow},
+		},
+		{
+			name:         "dense",
+			transactions: dense,
+			timeRange:    "4 months",
+			want:         types.DataQuality{Transactions: 100, DaysCovered: 100, Confidence: types.DataHigh},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(&fakeRepo{transactions: tt.transactions}, fixedClock("2025-04-15"))
+
+			got, err := svc.GetSpendingAnalytics(context.Background(), "1234567891", tt.timeRange, AnalyticsOptions{Splits: SplitByPrimary})
+			if err != nil {
+				t.Fatalf("GetSpendingAnalytics() failed: %v", err)
+			}
+			q := got.Data.DataQuality
+			if q == nil {
+				t.Fatal("DataQuality = nil, want a grade")
+			}
+			if q.Transactions != tt.want.Transactions || q.DaysCovered != tt.want.DaysCovered || q.Confidence != tt.want.Confidence {
+				t.Errorf("DataQuality = %+v, want %+v", *q, tt.want)
+			}
+		})
+	}
+}
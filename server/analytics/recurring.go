@@ -0,0 +1,348 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"server/types"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// minRecurringOccurrences is how many charges we need to call a merchant recurring
+	minRecurringOccurrences = 3
+	// minYearlyOccurrences is how many charges a yearly subscription needs
+	minYearlyOccurrences = 2
+	// defaultRecurringTolerance is how far a charge may stray from the typical amount
+	defaultRecurringTolerance = 0.1
+	// defaultMerchantSimilarity only groups merchants whose names match exactly
+	defaultMerchantSimilarity = 1.0
+)
+
+// WithRecurringTolerance sets how far, as a fraction of the typical amount, a
+// charge may vary and still count towards a recurring charge. Bills such as
+// utilities that move month to month need a wider tolerance than
+// subscriptions.
+func WithRecurringTolerance(tolerance float64) Option {
+	return func(s *service) {
+		if tolerance >= 0 {
+			s.recurringTolerance = tolerance
+		}
+	}
+}
+
+// WithMerchantSimilarity groups merchant names that are at least this similar
+// (0 to 1) when detecting recurring charges, so "CON ED #4411" and "Con Edison"
+// style variations in bank descriptions still line up. 1 requires an exact
+// match.
+func WithMerchantSimilarity(threshold float64) Option {
+	return func(s *service) {
+		if threshold > 0 && threshold <= 1 {
+			s.merchantSimilarity = threshold
+		}
+	}
+}
+
+// recurringGroup is a detected recurring charge together with the
+// transactions it was built from
+type recurringGroup struct {
+	charge       types.RecurringCharge
+	transactions []types.Transaction
+}
+
+// detectRecurring finds expenses that repeat at a regular cadence with a
+// consistent amount at the same merchant. A merchant's charges are first split
+// by amount, so two subscriptions billed by the same company are detected
+// separately and a one-off purchase there doesn't break the cadence.
+func (s *service) detectRecurring(transactions []types.Transaction) []recurringGroup {
+	byMerchant := s.groupByMerchant(transactions)
+
+	groups := make([]recurringGroup, 0)
+	for _, txns := range byMerchant {
+		sort.Slice(txns, func(i, j int) bool {
+			return txns[i].Date.Before(txns[j].Date)
+		})
+		for _, cluster := range amountClusters(txns, s.recurringTolerance) {
+			if g, ok := recurringGroupOf(cluster); ok {
+				groups = append(groups, g)
+			}
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i].charge, groups[j].charge
+		if a.MonthlyAmount != b.MonthlyAmount {
+			return a.MonthlyAmount > b.MonthlyAmount
+		}
+		if a.Merchant != b.Merchant {
+			return a.Merchant < b.Merchant
+		}
+		return a.AverageAmount > b.AverageAmount
+	})
+
+	return groups
+}
+
+// amountClusters splits a merchant's charges, sorted by date, into runs of
+// similar amounts. A charge joins the first cluster whose first or latest
+// amount it is within tolerance of, so a price that creeps up a little each
+// cycle stays in one cluster while a separate, differently priced charge
+// starts its own.
+func amountClusters(txns []types.Transaction, tolerance float64) [][]types.Transaction {
+	type cluster struct {
+		first, latest float64
+		txns          []types.Transaction
+	}
+	within := func(amount, ref float64) bool {
+		return math.Abs(amount-ref) <= ref*tolerance
+	}
+
+	var clusters []*cluster
+	for _, t := range txns {
+		amount := expenseAmount(t)
+		var target *cluster
+		for _, c := range clusters {
+			if within(amount, c.first) || within(amount, c.latest) {
+				target = c
+				break
+			}
+		}
+		if target == nil {
+			target = &cluster{first: amount}
+			clusters = append(clusters, target)
+		}
+		target.latest = amount
+		target.txns = append(target.txns, t)
+	}
+
+	result := make([][]types.Transaction, len(clusters))
+	for i, c := range clusters {
+		result[i] = c.txns
+	}
+	return result
+}
+
+// recurringGroupOf builds a recurring charge from similarly priced charges at
+// one merchant, sorted by date, if they repeat at a regular cadence. Yearly
+// charges only need two occurrences, since few histories hold three.
+func recurringGroupOf(matched []types.Transaction) (recurringGroup, bool) {
+	if len(matched) < minYearlyOccurrences {
+		return recurringGroup{}, false
+	}
+
+	intervals := make([]time.Duration, len(matched)-1)
+	for i := 1; i < len(matched); i++ {
+		intervals[i-1] = matched[i].Date.Sub(matched[i-1].Date)
+	}
+	avgInterval := matched[len(matched)-1].Date.Sub(matched[0].Date) / time.Duration(len(intervals))
+	cadence, regularity := cadenceOf(intervals, 0.75)
+	if cadence == types.CadenceIrregular {
+		return recurringGroup{}, false
+	}
+	if cadence != types.CadenceYearly && len(matched) < minRecurringOccurrences {
+		return recurringGroup{}, false
+	}
+
+	var total float64
+	for _, t := range matched {
+		total += expenseAmount(t)
+	}
+	avgAmount := total / float64(len(matched))
+	last := matched[len(matched)-1]
+
+	return recurringGroup{
+		charge: types.RecurringCharge{
+			Merchant:      last.Merchant,
+			Category:      last.Category,
+			Cadence:       cadence,
+			AverageAmount: avgAmount,
+			MonthlyAmount: avgAmount * monthlyFactor(cadence),
+			Occurrences:   len(matched),
+			LastDate:      last.Date,
+			NextExpected:  last.Date.Add(avgInterval),
+			Confidence:    regularity * math.Min(float64(len(matched))/6, 1.0),
+		},
+		transactions: matched,
+	}, true
+}
+
+// DetectRecurring finds the account's active recurring charges, such as
+// subscriptions and bills, over the last two years so yearly ones have a chance
+// to repeat. Largest monthly cost first.
+func (s *service) DetectRecurring(ctx context.Context, accountID string) ([]types.RecurringCharge, error) {
+	history, err := s.loadTransactions(ctx, accountID, "2 years")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction history: %w", err)
+	}
+
+	groups := activeRecurring(s.detectRecurring(history), s.now())
+	charges := make([]types.RecurringCharge, len(groups))
+	for i, g := range groups {
+		charges[i] = g.charge
+	}
+	return charges, nil
+}
+
+// activeRecurring drops charges that have missed more than a full cycle as
+// of now, since they have most likely been cancelled
+func activeRecurring(groups []recurringGroup, now time.Time) []recurringGroup {
+	active := make([]recurringGroup, 0, len(groups))
+	for _, g := range groups {
+		cycle := g.charge.NextExpected.Sub(g.charge.LastDate)
+		if now.Before(g.charge.NextExpected.Add(cycle)) {
+			active = append(active, g)
+		}
+	}
+	return active
+}
+
+// merchantKey normalizes a merchant name for grouping
+func merchantKey(merchant string) string {
+	return strings.ToLower(strings.TrimSpace(merchant))
+}
+
+// groupByMerchant groups expenses by merchant. Below an exact similarity
+// threshold, each merchant joins the first group whose name is similar
+// enough, checked in name order so grouping is deterministic.
+func (s *service) groupByMerchant(transactions []types.Transaction) map[string][]types.Transaction {
+	byMerchant := make(map[string][]types.Transaction)
+	for _, t := range transactions {
+		if expenseAmount(t) == 0 {
+			continue
+		}
+		key := merchantKey(t.Merchant)
+		byMerchant[key] = append(byMerchant[key], t)
+	}
+	if s.merchantSimilarity >= 1 {
+		return byMerchant
+	}
+
+	keys := make([]string, 0, len(byMerchant))
+	for key := range byMerchant {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	grouped := make(map[string][]types.Transaction)
+	var representatives []string
+	for _, key := range keys {
+		name := fuzzyName(key)
+		target := key
+		for _, rep := range representatives {
+			if nameSimilarity(name, fuzzyName(rep)) >= s.merchantSimilarity {
+				target = rep
+				break
+			}
+		}
+		if target == key {
+			representatives = append(representatives, key)
+		}
+		grouped[target] = append(grouped[target], byMerchant[key]...)
+	}
+	return grouped
+}
+
+// fuzzyName reduces a merchant key to its letters and single spaces, dropping
+// the store numbers and punctuation banks add to descriptions
+func fuzzyName(key string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range key {
+		switch {
+		case unicode.IsLetter(r):
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteRune(r)
+		default:
+			space = true
+		}
+	}
+	return b.String()
+}
+
+// nameSimilarity scores two names from 0 to 1. A name that is a whole-word
+// prefix of the other, like "netflix" and "netflix com", counts as a match;
+// otherwise it is the edit-distance ratio.
+func nameSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	short, long := a, b
+	if len(short) > len(long) {
+		short, long = long, short
+	}
+	if strings.HasPrefix(long, short+" ") {
+		return 1
+	}
+	return 1 - float64(levenshtein(a, b))/float64(len(long))
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// transactionKey identifies a transaction across separate repository reads
+type transactionKey struct {
+	id       string
+	date     time.Time
+	amount   float64
+	merchant string
+}
+
+func keyOf(t types.Transaction) transactionKey {
+	return transactionKey{id: t.TransactionID, date: t.Date, amount: t.Amount, merchant: t.Merchant}
+}
+
+// recurringSet indexes the transactions belonging to the given groups
+func recurringSet(groups []recurringGroup) map[transactionKey]bool {
+	set := make(map[transactionKey]bool)
+	for _, g := range groups {
+		for _, t := range g.transactions {
+			set[keyOf(t)] = true
+		}
+	}
+	return set
+}
+
+// RecurringToBudget turns detected recurring charges into a starting monthly
+// budget per category. Each charge is normalized to its monthly cost from its
+// cadence, so a yearly insurance premium adds a twelfth of itself and a weekly
+// class about four times its price. Irregular charges have no monthly cost and
+// are left out.
+func RecurringToBudget(charges []types.RecurringCharge) map[string]float64 {
+	budget := make(map[string]float64)
+	for _, c := range charges {
+		perMonth := monthlyFactor(c.Cadence)
+		if perMonth == 0 {
+			continue
+		}
+		budget[c.Category] += c.AverageAmount * perMonth
+	}
+	return budget
+}
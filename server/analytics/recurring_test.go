@@ -0,0 +1,157 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+)
+
+func TestDetectRecurringTolerance(t *testing.T) {
+	// A utility bill that moves up to 15% either side of its usual amount,
+	// described slightly differently by the bank month to month
+	bills := []struct {
+		date     string
+		amount   float64
+		merchant string
+	}{
+		{"2024-10-20", -100, "Con Edison"},
+		{"2024-11-20", -112, "CON EDISON #4411"},
+		{"2024-12-20", -88, "Con Edisn"},
+		{"2025-01-20", -114, "Con Edison"},
+		{"2025-02-20", -90, "CON EDISON #4411"},
+		{"2025-03-20", -105, "Con Edison"},
+	}
+	transactions := make([]types.Transaction, 0, len(bills))
+	for _, b := range bills {
+		transactions = append(transactions, txn(b.date, b.amount, "Utilities", b.merchant))
+	}
+
+	tests := []struct {
+		name            string
+		opts            []Option
+		wantOccurrences int
+	}{
+		{name: "defaults split the bill", opts: nil, wantOccurrences: 0},
+		{name: "amount tolerance only", opts: []Option{WithRecurringTolerance(0.15)}, wantOccurrences: 0},
+		{name: "fuzzy names only", opts: []Option{WithMerchantSimilarity(0.85)}, wantOccurrences: 0},
+		{
+			name:            "amount tolerance and fuzzy names",
+			opts:            []Option{WithRecurringTolerance(0.15), WithMerchantSimilarity(0.85)},
+			wantOccurrences: 6,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(&fakeRepo{}, tt.opts...).(*service)
+			groups := svc.detectRecurring(transactions)
+
+			var got int
+			for _, g := range groups {
+				if g.charge.Occurrences > got {
+					got = g.charge.Occurrences
+				}
+			}
+			if got != tt.wantOccurrences {
+				t.Errorf("largest recurring group has %d charges, want %d", got, tt.wantOccurrences)
+			}
+		})
+	}
+}
+
+func TestDetectRecurring(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		// Yearly, with only two charges so far
+		txn("2024-02-01", -139, "Shopping", "Amazon Prime"),
+		txn("2025-02-01", -139, "Shopping", "Amazon Prime"),
+		// A price that creeps up every month
+		txn("2024-11-03", -10.00, "Entertainment", "Spotify"),
+		txn("2024-12-03", -10.50, "Entertainment", "Spotify"),
+		txn("2025-01-03", -11.00, "Entertainment", "Spotify"),
+		txn("2025-02-03", -11.50, "Entertainment", "Spotify"),
+		txn("2025-03-03", -12.00, "Entertainment", "Spotify"),
+		// Two plans billed by the same merchant, plus a one-off
+		txn("2025-01-09", -2.99, "Entertainment", "Apple"),
+		txn("2025-01-20", -9.99, "Entertainment", "Apple"),
+		txn("2025-02-09", -2.99, "Entertainment", "Apple"),
+		txn("2025-02-12", -999, "Entertainment", "Apple"),
+		txn("2025-02-20", -9.99, "Entertainment", "Apple"),
+		txn("2025-03-09", -2.99, "Entertainment", "Apple"),
+		txn("2025-03-20", -9.99, "Entertainment", "Apple"),
+	}}
+	svc := NewService(repo, fixedClock("2025-03-25"))
+
+	got, err := svc.DetectRecurring(context.Background(), "1234567891")
+	if err != nil {
+		t.Fatalf("DetectRecurring() failed: %v", err)
+	}
+
+	type key struct {
+		merchant string
+		amount   float64
+	}
+	want := map[key]types.Cadence{
+		{"Amazon Prime", 139}: types.CadenceYearly,
+		{"Spotify", 11}:       types.CadenceMonthly,
+		{"Apple", 9.99}:       types.CadenceMonthly,
+		{"Apple", 2.99}:       types.CadenceMonthly,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("DetectRecurring() = %+v, want %d charges", got, len(want))
+	}
+	for _, c := range got {
+		k := key{c.Merchant, float64(int(c.AverageAmount*100+0.5)) / 100}
+		cadence, ok := want[k]
+		if !ok || c.Cadence != cadence {
+			t.Errorf("unexpected charge %+v", c)
+		}
+		if c.Confidence <= 0 || c.Confidence > 1 || !c.NextExpected.After(c.LastDate) {
+			t.Errorf("%s: Confidence = %v, NextExpected = %v, want a score in (0, 1] and a date after %v", c.Merchant, c.Confidence, c.NextExpected, c.LastDate)
+		}
+	}
+}
+
+func TestNameSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{a: "con edison", b: "con edison", want: 1},
+		{a: "netflix", b: "netflix com", want: 1},
+		{a: "con edison", b: "con edisn", want: 0.9},
+		{a: "hulu", b: "hula", want: 0.75},
+		{a: "spotify", b: "hulu", want: 0},
+	}
+	for _, tt := range tests {
+		if got := nameSimilarity(tt.a, tt.b); !approxEqual(got, tt.want) {
+			t.Errorf("nameSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestRecurringToBudget(t *testing.T) {
+	charges := []types.RecurringCharge{
+		{Merchant: "Netflix", Category: "Entertainment", Cadence: types.CadenceMonthly, AverageAmount: 15.49},
+		{Merchant: "Spotify", Category: "Entertainment", Cadence: types.CadenceMonthly, AverageAmount: 10.99},
+		{Merchant: "Amazon Prime", Category: "Shopping", Cadence: types.CadenceYearly, AverageAmount: 139},
+		{Merchant: "Yoga Studio", Category: "Fitness", Cadence: types.CadenceWeekly, AverageAmount: 20},
+		{Merchant: "Car Insurance", Category: "Insurance", Cadence: types.CadenceQuarterly, AverageAmount: 360},
+		{Merchant: "Parking", Category: "Transport", Cadence: types.CadenceIrregular, AverageAmount: 12},
+	}
+
+	got := RecurringToBudget(charges)
+
+	want := map[string]float64{
+		"Entertainment": 26.48,
+		"Shopping":      139.0 / 12,
+		"Fitness":       20 * 30.44 / 7,
+		"Insurance":     120,
+	}
+	if len(got) != len(want) {
+		t.Errorf("RecurringToBudget() = %v, want %v", got, want)
+	}
+	for category, amount := range want {
+		if !approxEqual(got[category], amount) {
+			t.Errorf("budget[%s] = %v, want %v", category, got[category], amount)
+		}
+	}
+}
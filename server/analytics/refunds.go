@@ -0,0 +1,195 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"sort"
+	"strings"
+	"time"
+)
+
+// refundMatchWindow is how long after a charge a refund of the same amount at
+// the same merchant is taken to reverse it
+const refundMatchWindow = 60 * 24 * time.Hour
+
+// defaultIncomeCategories are the categories whose credits are earnings
+// rather than money coming back from a purchase
+var defaultIncomeCategories = []string{"Income"}
+
+// WithIncomeCategories replaces the categories treated as income. Credits in
+// any other category are counted as refunds. Matching is case-insensitive.
+func WithIncomeCategories(categories ...string) Option {
+	return func(s *service) {
+		s.incomeCategories = make([]string, 0, len(categories))
+		for _, c := range categories {
+			if c = strings.TrimSpace(c); c != "" {
+				s.incomeCategories = append(s.incomeCategories, c)
+			}
+		}
+	}
+}
+
+// GetRefundSummary reports the credits that came back in expense categories,
+// such as returns and merchant reversals, so they can be tracked separately
+// from spending and income
+func (s *service) GetRefundSummary(ctx context.Context, accountID string, timeRange string) (*types.RefundSummary, error) {
+	transactions, err := s.loadTransactions(ctx, accountID, timeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	summary := &types.RefundSummary{
+		ByCategory:   make(map[string]float64),
+		Transactions: make([]types.Transaction, 0),
+	}
+	for _, t := range transactions {
+		if !s.isRefund(t) {
+			continue
+		}
+		summary.Total += t.Amount
+		summary.Count++
+		summary.ByCategory[t.Category] += t.Amount
+		summary.Transactions = append(summary.Transactions, t)
+	}
+
+	return summary, nil
+}
+
+// incomeSummary totals the credits in income categories, leaving refunds out,
+// and nets them against the spend over the same range
+func (s *service) incomeSummary(transactions []types.Transaction, spent float64) *types.IncomeSummary {
+	summary := &types.IncomeSummary{ByCategory: make(map[string]float64)}
+	for _, t := range transactions {
+		if t.Amount <= 0 || !s.isIncome(t) {
+			continue
+		}
+		summary.Total += t.Amount
+		summary.ByCategory[t.Category] += t.Amount
+	}
+	summary.Net = summary.Total - spent
+	return summary
+}
+
+// isIncome reports whether a transaction falls in an income category
+func (s *service) isIncome(t types.Transaction) bool {
+	for _, c := range s.incomeCategories {
+		if strings.EqualFold(t.Category, c) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRefund reports whether a transaction is a credit outside the income
+// categories
+func (s *service) isRefund(t types.Transaction) bool {
+	return t.Amount > 0 && !s.isIncome(t)
+}
+
+// WithRefundNetting makes category totals and predictions count refunds
+// against the spending they reverse instead of leaving the original charge
+// in place. A refund matching an earlier charge at the same merchant for the
+// same amount cancels it; any other refund comes off its category's total,
+// which never goes below zero.
+func WithRefundNetting() Option {
+	return func(s *service) {
+		s.refundNetting = true
+	}
+}
+
+// matchRefunds pairs refunds with the charges they reverse: the latest
+// unmatched charge at the same merchant for the same amount in the
+// refundMatchWindow before the refund. It maps each matched refund's index
+// to its charge's index.
+func (s *service) matchRefunds(transactions []types.Transaction) map[int]int {
+	order := make([]int, len(transactions))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return transactions[order[a]].Date.Before(transactions[order[b]].Date)
+	})
+
+	matches := make(map[int]int)
+	charged := make(map[int]bool)
+	for pos, r := range order {
+		refund := transactions[r]
+		if !s.isRefund(refund) || merchantKey(refund.Merchant) == "" {
+			continue
+		}
+		for p := pos - 1; p >= 0; p-- {
+			c := order[p]
+			charge := transactions[c]
+			if refund.Date.Sub(charge.Date) > refundMatchWindow {
+				break
+			}
+			if charged[c] || charge.Amount >= 0 || merchantKey(charge.Merchant) != merchantKey(refund.Merchant) {
+				continue
+			}
+			if abs(charge.Amount+refund.Amount) < 0.005 {
+				matches[r] = c
+				charged[c] = true
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// netRefunds drops every refund matched to a charge along with the charge
+// itself. Transactions are returned unchanged unless refund netting is on.
+func (s *service) netRefunds(transactions []types.Transaction) []types.Transaction {
+	if !s.refundNetting {
+		return transactions
+	}
+	matches := s.matchRefunds(transactions)
+	if len(matches) == 0 {
+		return transactions
+	}
+	dropped := make(map[int]bool, 2*len(matches))
+	for r, c := range matches {
+		dropped[r] = true
+		dropped[c] = true
+	}
+	netted := make([]types.Transaction, 0, len(transactions)-len(dropped))
+	for i, t := range transactions {
+		if !dropped[i] {
+			netted = append(netted, t)
+		}
+	}
+	return netted
+}
+
+// netRefundTotals takes the refunds among transactions off the category
+// totals built from them: a matched refund off its charge's category and any
+// other off its own, flooring each total at zero. Categories netted down to
+// nothing are dropped. The totals are returned unchanged unless refund
+// netting is on.
+func (s *service) netRefundTotals(totals map[string]float64, transactions []types.Transaction) map[string]float64 {
+	if !s.refundNetting {
+		return totals
+	}
+	matches := s.matchRefunds(transactions)
+	netted := make(map[string]float64, len(totals))
+	for category, amount := range totals {
+		netted[category] = amount
+	}
+	for i, t := range transactions {
+		if !s.isRefund(t) {
+			continue
+		}
+		category := t.Category
+		if c, ok := matches[i]; ok {
+			category = transactions[c].Category
+		}
+		if _, ok := netted[category]; !ok {
+			continue
+		}
+		netted[category] -= t.Amount
+		if netted[category] < 0.005 {
+			delete(netted, category)
+		}
+	}
+	return netted
+}
@@ -0,0 +1,129 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+)
+
+func TestGetRefundSummary(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-03-01", 3500, "Income", "Employer"),
+		txn("2025-03-03", -89.99, "Shopping", "Amazon"),
+		txn("2025-03-08", 89.99, "Shopping", "Amazon"),
+		txn("2025-03-09", 24.50, "Shopping", "Target"),
+		txn("2025-03-12", 12.30, "Food", "Uber Eats"),
+		txn("2025-03-20", 150, "Travel", "Delta Airlines"),
+		txn("2025-03-21", -45, "Food", "Chipotle"),
+	}}
+
+	tests := []struct {
+		name      string
+		opts      []Option
+		wantTotal float64
+		wantCount int
+	}{
+		{
+			name:      "default income categories",
+			wantTotal: 276.79,
+			wantCount: 4,
+		},
+		{
+			name:      "travel reimbursements as income",
+			opts:      []Option{WithIncomeCategories("income", "Travel")},
+			wantTotal: 126.79,
+			wantCount: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(repo, tt.opts...)
+			got, err := svc.GetRefundSummary(context.Background(), "1234567891", "1 month")
+			if err != nil {
+				t.Fatalf("GetRefundSummary() failed: %v", err)
+			}
+			if !approxEqual(got.Total, tt.wantTotal) || got.Count != tt.wantCount {
+				t.Errorf("GetRefundSummary() total, count = %v, %d, want %v, %d", got.Total, got.Count, tt.wantTotal, tt.wantCount)
+			}
+			if !approxEqual(got.ByCategory["Shopping"], 114.49) {
+				t.Errorf("ByCategory[Shopping] = %v, want 114.49", got.ByCategory["Shopping"])
+			}
+			if !approxEqual(got.ByCategory["Food"], 12.30) {
+				t.Errorf("ByCategory[Food] = %v, want 12.30", got.ByCategory["Food"])
+			}
+			if _, ok := got.ByCategory["Income"]; ok {
+				t.Error("ByCategory includes Income, want salary excluded")
+			}
+		})
+	}
+}
+
+func TestWithRefundNettingMatchedRefund(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-03-02", -20, "Electronics", "Best Buy"),
+		txn("2025-03-09", -20, "Electronics", "Best Buy"),
+		txn("2025-03-16", -500, "Electronics", "Best Buy"),
+		txn("2025-03-23", -20, "Electronics", "Best Buy"),
+		// The 500 purchase goes back, charged to a different category
+		txn("2025-03-28", 500, "Returns", "Best Buy"),
+	}}
+
+	for _, tt := range []struct {
+		name   string
+		opts   []Option
+		total  string
+		amount float64
+	}{
+		{name: "without netting", total: "560.00", amount: 140},
+		{name: "with netting", opts: []Option{WithRefundNetting()}, total: "60.00", amount: 20},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(repo, append(tt.opts, fixedClock("2025-04-01"))...)
+
+			got, err := svc.GetSpendingAnalytics(context.Background(), "1234567891", "1 month", AnalyticsOptions{})
+			if err != nil {
+				t.Fatalf("GetSpendingAnalytics() failed: %v", err)
+			}
+			if len(got.Data.TopCategories) != 1 || got.Data.TopCategories[0].TotalSpent != tt.total {
+				t.Errorf("TopCategories = %+v, want Electronics at %s", got.Data.TopCategories, tt.total)
+			}
+
+			predictions, err := svc.PredictFutureSpending(context.Background(), "1234567891", PredictionOptions{})
+			if err != nil {
+				t.Fatalf("PredictFutureSpending() failed: %v", err)
+			}
+			if len(predictions) != 1 || !approxEqual(predictions[0].PredictedAmount, tt.amount) {
+				t.Errorf("PredictFutureSpending() = %+v, want Electronics at %v", predictions, tt.amount)
+			}
+		})
+	}
+}
+
+func TestWithRefundNettingUnmatchedRefund(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-03-03", -80, "Shopping", "Target"),
+		// A partial refund matches no charge
+		txn("2025-03-10", 30, "Shopping", "Target"),
+		txn("2025-03-05", -25, "Food", "Chipotle"),
+		// More comes back than was spent in the range
+		txn("2025-03-12", 40, "Food", "Chipotle"),
+		txn("2025-03-14", 3500, "Income", "Employer"),
+	}}
+	svc := NewService(repo, fixedClock("2025-04-01"), WithRefundNetting())
+
+	got, err := svc.GetSpendingAnalytics(context.Background(), "1234567891", "1 month", AnalyticsOptions{})
+	if err != nil {
+		t.Fatalf("GetSpendingAnalytics() failed: %v", err)
+	}
+
+	amounts := make(map[string]string)
+	for _, c := range got.Data.TopCategories {
+		amounts[c.Category] = c.TotalSpent
+	}
+	if len(amounts) != 1 || amounts["Shopping"] != "50.00" {
+		t.Errorf("category totals = %v, want only Shopping at 50.00", amounts)
+	}
+	if !approxEqual(got.Data.TotalSpent, 50) {
+		t.Errorf("TotalSpent = %v, want 50", got.Data.TotalSpent)
+	}
+}
@@ -6,6 +6,7 @@ import (
 )
 
 type Repository interface {
-	GetTransactions(ctx context.Context, accountID string, timeRange string) ([]types.Transaction, error)
-	GetCategoryTotals(ctx context.Context, accountID string, timeRange string) (map[string]float64, error)
+	GetTransactions(ctx context.Context, accountID string, window types.DateRange) ([]types.Transaction, error)
+	GetCategoryTotals(ctx context.Context, accountID string, window types.DateRange) (map[string]float64, error)
+	GetBalance(ctx context.Context, accountID string) (float64, error)
 } 
\ No newline at end of file
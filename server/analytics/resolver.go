@@ -0,0 +1,101 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"strings"
+	"sync"
+)
+
+// CategoryResolver works out a category for a transaction that arrived
+// without one, e.g. by asking an external enrichment service. An empty result
+// leaves the transaction uncategorized.
+type CategoryResolver interface {
+	Resolve(ctx context.Context, txn types.Transaction) (string, error)
+}
+
+// NoopResolver is the default CategoryResolver; it never categorizes anything
+type NoopResolver struct{}
+
+// Resolve returns no category
+func (NoopResolver) Resolve(ctx context.Context, txn types.Transaction) (string, error) {
+	return "", nil
+}
+
+// WithCategoryResolver sets the resolver used for uncategorized transactions.
+// Results are cached per merchant for the life of the service, so each
+// merchant is only looked up once.
+func WithCategoryResolver(r CategoryResolver) Option {
+	return func(s *service) {
+		if r != nil {
+			s.resolver = &cachedResolver{next: r, cache: make(map[string]string)}
+		}
+	}
+}
+
+// cachedResolver remembers what its resolver said about each merchant.
+// Failures aren't cached, so they are retried next time.
+type cachedResolver struct {
+	next CategoryResolver
+
+	mu    sync.Mutex
+	cache map[string]string
+}
+
+func (c *cachedResolver) Resolve(ctx context.Context, txn types.Transaction) (string, error) {
+	key := strings.ToLower(strings.TrimSpace(txn.Merchant))
+	if key == "" {
+		return c.next.Resolve(ctx, txn)
+	}
+
+	c.mu.Lock()
+	category, ok := c.cache[key]
+	c.mu.Unlock()
+	if ok {
+		return category, nil
+	}
+
+	category, err := c.next.Resolve(ctx, txn)
+	if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	c.cache[key] = category
+	c.mu.Unlock()
+	return category, nil
+}
+
+// resolveCategories fills in the category of every uncategorized transaction
+// the resolver knows about, moving its spend out of the uncategorized total.
+// It returns new totals and transactions, leaving the inputs untouched.
+func (s *service) resolveCategories(ctx context.Context, totals map[string]float64, transactions []types.Transaction) (map[string]float64, []types.Transaction, error) {
+	resolvedTotals := make(map[string]float64, len(totals))
+	for category, amount := range totals {
+		resolvedTotals[category] = amount
+	}
+	resolved := make([]types.Transaction, len(transactions))
+	copy(resolved, transactions)
+
+	for i, t := range resolved {
+		if t.Category != "" {
+			continue
+		}
+		category, err := s.resolver.Resolve(ctx, t)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to resolve category: %w", err)
+		}
+		if category == "" {
+			continue
+		}
+		resolved[i].Category = category
+		if amount := expenseAmount(t); amount > 0 {
+			resolvedTotals[""] -= amount
+			resolvedTotals[category] += amount
+		}
+	}
+	if amount, ok := resolvedTotals[""]; ok && amount <= 0 {
+		delete(resolvedTotals, "")
+	}
+	return resolvedTotals, resolved, nil
+}
@@ -0,0 +1,56 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+)
+
+// stubResolver categorizes by merchant and counts its lookups
+type stubResolver struct {
+	categories map[string]string
+	calls      int
+}
+
+func (r *stubResolver) Resolve(ctx context.Context, txn types.Transaction) (string, error) {
+	r.calls++
+	return r.categories[txn.Merchant], nil
+}
+
+func TestGetSpendingAnalyticsResolvesCategories(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-04-02", -30, "", "Blue Bottle"),
+		txn("2025-04-06", -20, "", "Blue Bottle"),
+		txn("2025-04-08", -15, "", "Corner Kiosk"),
+		txn("2025-04-05", -50, "Food", "Whole Foods"),
+	}}
+	resolver := &stubResolver{categories: map[string]string{"Blue Bottle": "Coffee"}}
+	svc := NewService(repo, fixedClock("2025-04-15"), WithCategoryResolver(resolver))
+
+	got, err := svc.GetSpendingAnalytics(context.Background(), "1234567891", "1 month", AnalyticsOptions{})
+	if err != nil {
+		t.Fatalf("GetSpendingAnalytics() failed: %v", err)
+	}
+
+	amounts := make(map[string]string)
+	for _, c := range got.Data.TopCategories {
+		amounts[c.Category] = c.TotalSpent
+	}
+	if amounts["Coffee"] != "50.00" || amounts["Food"] != "50.00" || amounts[""] != "15.00" {
+		t.Errorf("category totals = %v, want Blue Bottle resolved to Coffee and the kiosk left uncategorized", amounts)
+	}
+	if !approxEqual(got.Data.TotalSpent, 115) {
+		t.Errorf("TotalSpent = %v, want 115", got.Data.TotalSpent)
+	}
+	// Whole Foods already has a category and Blue Bottle's second visit is cached
+	if resolver.calls != 2 {
+		t.Errorf("resolver called %d times, want 2", resolver.calls)
+	}
+
+	if _, err := svc.GetSpendingAnalytics(context.Background(), "1234567891", "1 month", AnalyticsOptions{}); err != nil {
+		t.Fatalf("GetSpendingAnalytics() failed: %v", err)
+	}
+	if resolver.calls != 2 {
+		t.Errorf("resolver called %d times after a second run, want the cached 2", resolver.calls)
+	}
+}
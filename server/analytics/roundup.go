@@ -0,0 +1,40 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"server/types"
+)
+
+// SimulateRoundUpSavings works out what a round-up savings feature would have
+// put aside over the range: each purchase rounded up to the next whole dollar,
+// with the difference saved. Exact-dollar purchases save nothing.
+func (s *service) SimulateRoundUpSavings(ctx context.Context, accountID string, timeRange string) (*types.RoundUpSavings, error) {
+	transactions, err := s.loadTransactions(ctx, accountID, timeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	loc := s.now().Location()
+	result := &types.RoundUpSavings{ByMonth: make(map[string]float64)}
+	for _, t := range transactions {
+		amount := expenseAmount(t)
+		if amount == 0 {
+			continue
+		}
+		result.Purchases++
+
+		// Work in cents so float error can't turn 4.00 into a 99 cent round-up
+		cents := int64(math.Round(amount * 100))
+		roundUp := (100 - cents%100) % 100
+		if roundUp == 0 {
+			continue
+		}
+		saved := float64(roundUp) / 100
+		result.Total += saved
+		result.ByMonth[t.Date.In(loc).Format("2006-01")] += saved
+	}
+
+	return result, nil
+}
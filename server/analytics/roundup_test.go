@@ -0,0 +1,31 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+)
+
+func TestSimulateRoundUpSavings(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-02-03", -4.75, "Food", "Starbucks"),    // 0.25
+		txn("2025-02-10", -12.01, "Food", "Chipotle"),    // 0.99
+		txn("2025-02-14", -40.00, "Shopping", "Target"),  // exact dollar, 0
+		txn("2025-03-01", -84.20, "Food", "Whole Foods"), // 0.80
+		txn("2025-03-02", -0.10, "Fees", "Bank"),         // 0.90
+		txn("2025-03-15", 2000, "Income", "Payroll"),     // credits aren't purchases
+	}}
+	svc := NewService(repo, fixedClock("2025-03-20"))
+
+	got, err := svc.SimulateRoundUpSavings(context.Background(), "1234567891", "3 months")
+	if err != nil {
+		t.Fatalf("SimulateRoundUpSavings() failed: %v", err)
+	}
+
+	if !approxEqual(got.Total, 2.94) || got.Purchases != 5 {
+		t.Errorf("Total, Purchases = %v, %d, want 2.94, 5", got.Total, got.Purchases)
+	}
+	if len(got.ByMonth) != 2 || !approxEqual(got.ByMonth["2025-02"], 1.24) || !approxEqual(got.ByMonth["2025-03"], 1.70) {
+		t.Errorf("ByMonth = %v, want 2025-02: 1.24, 2025-03: 1.70", got.ByMonth)
+	}
+}
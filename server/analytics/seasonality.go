@@ -0,0 +1,89 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"time"
+)
+
+// seasonalHistoryMonths is how many complete months of spend a seasonal
+// index needs, so every calendar month has been seen at least once
+const seasonalHistoryMonths = 12
+
+// CompareMonthSeasonallyAdjusted compares spend in a month ("2006-01") with
+// the month before, both as spent and with seasonality divided out. Each
+// calendar month's seasonal index is its average spend over the account's
+// history relative to the average month, so a January after a December of
+// holiday shopping isn't reported as a sharp cut back.
+func (s *service) CompareMonthSeasonallyAdjusted(ctx context.Context, accountID string, period string) (*types.SeasonalComparison, error) {
+	start, err := time.ParseInLocation("2006-01", period, s.now().Location())
+	if err != nil {
+		return nil, fmt.Errorf("invalid period %q, expected YYYY-MM: %w", period, err)
+	}
+	end := start.AddDate(0, 1, 0)
+	priorStart := start.AddDate(0, -1, 0)
+	if end.After(monthStart(s.now())) {
+		return nil, fmt.Errorf("period %s hasn't finished yet", period)
+	}
+
+	// Up to three years of history before the period, however long ago it was
+	transactions, err := s.repo.GetTransactions(ctx, accountID, types.DateRange{Start: end.AddDate(-3, 0, 0), End: end})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	// Monthly totals up to the end of the period, with months of no spend
+	// counted as zero once history has begun
+	totals := make(map[time.Time]float64)
+	var first time.Time
+	for _, t := range transactions {
+		amount := expenseAmount(t)
+		date := t.Date.In(start.Location())
+		if amount == 0 || !date.Before(end) {
+			continue
+		}
+		month := monthStart(date)
+		totals[month] += amount
+		if first.IsZero() || month.Before(first) {
+			first = month
+		}
+	}
+	if first.IsZero() || first.AddDate(0, seasonalHistoryMonths, 0).After(end) {
+		return nil, fmt.Errorf("seasonal adjustment needs %d months of data: %w", seasonalHistoryMonths, ErrInsufficientHistory)
+	}
+
+	var overall []float64
+	byCalendarMonth := make(map[time.Month][]float64)
+	for m := first; m.Before(end); m = m.AddDate(0, 1, 0) {
+		overall = append(overall, totals[m])
+		byCalendarMonth[m.Month()] = append(byCalendarMonth[m.Month()], totals[m])
+	}
+	average := mean(overall)
+	index := func(month time.Month) float64 {
+		if average == 0 {
+			return 1
+		}
+		if seasonal := mean(byCalendarMonth[month]) / average; seasonal > 0 {
+			return seasonal
+		}
+		return 1
+	}
+
+	result := &types.SeasonalComparison{
+		Period:       period,
+		PriorPeriod:  priorStart.Format("2006-01"),
+		Current:      totals[start],
+		Prior:        totals[priorStart],
+		CurrentIndex: index(start.Month()),
+		PriorIndex:   index(priorStart.Month()),
+	}
+	result.Change = result.Current - result.Prior
+	result.PercentChange = percentChange(result.Current, result.Prior)
+	result.AdjustedCurrent = result.Current / result.CurrentIndex
+	result.AdjustedPrior = result.Prior / result.PriorIndex
+	result.AdjustedChange = result.AdjustedCurrent - result.AdjustedPrior
+	result.AdjustedPercentChange = percentChange(result.AdjustedCurrent, result.AdjustedPrior)
+
+	return result, nil
+}
@@ -0,0 +1,51 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"server/types"
+	"testing"
+	"time"
+)
+
+func TestCompareMonthSeasonallyAdjusted(t *testing.T) {
+	// Two years of steady spend with December doubling for the holidays
+	var transactions []types.Transaction
+	for m := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC); m.Before(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)); m = m.AddDate(0, 1, 0) {
+		amount := 1000.0
+		if m.Month() == time.December {
+			amount = 2000
+		}
+		transactions = append(transactions, txn(fmt.Sprintf("%d-%02d-10", m.Year(), m.Month()), -amount, "Shopping", "Target"))
+	}
+	repo := &fakeRepo{transactions: transactions}
+	svc := NewService(repo, fixedClock("2025-02-10"))
+
+	got, err := svc.CompareMonthSeasonallyAdjusted(context.Background(), "1234567891", "2025-01")
+	if err != nil {
+		t.Fatalf("CompareMonthSeasonallyAdjusted() failed: %v", err)
+	}
+	// Three years up to the end of the period, not up to now
+	want := types.DateRange{Start: time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)}
+	if len(repo.ranges) != 1 || !repo.ranges[0].Start.Equal(want.Start) || !repo.ranges[0].End.Equal(want.End) {
+		t.Errorf("ranges = %v, want %v", repo.ranges, want)
+	}
+	if got.PriorPeriod != "2024-12" {
+		t.Errorf("PriorPeriod = %q, want 2024-12", got.PriorPeriod)
+	}
+	if !approxEqual(got.PercentChange, -50) {
+		t.Errorf("PercentChange = %v, want the raw 50%% drop", got.PercentChange)
+	}
+	if got.PriorIndex <= 1 || got.CurrentIndex >= 1 {
+		t.Errorf("indices = %v, %v, want December above average and January below", got.PriorIndex, got.CurrentIndex)
+	}
+	if !approxEqual(got.AdjustedChange, 0) || !approxEqual(got.AdjustedPercentChange, 0) {
+		t.Errorf("AdjustedChange = %v (%v%%), want no change net of seasonality", got.AdjustedChange, got.AdjustedPercentChange)
+	}
+
+	short := NewService(&fakeRepo{transactions: transactions[len(transactions)-6:]}, fixedClock("2025-02-10"))
+	if _, err := short.CompareMonthSeasonallyAdjusted(context.Background(), "1234567891", "2025-01"); !errors.Is(err, ErrInsufficientHistory) {
+		t.Errorf("CompareMonthSeasonallyAdjusted() with 6 months error = %v, want ErrInsufficientHistory", err)
+	}
+}
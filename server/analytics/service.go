@@ -2,68 +2,262 @@ package analytics
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"server/types"
 	"sort"
-	"strconv"
+	"sync"
 	"time"
 )
 
+// Service is the analytics API. Methods that return slices return an empty,
+// non-nil slice when there are no results, so they encode as [] rather than
+// null in JSON.
 type Service interface {
-	GetSpendingAnalytics(ctx context.Context, accountID string, timeRange string) (*types.SpendingAnalytics, error)
-	AnalyzeTimePatterns(ctx context.Context, accountID string, startDate, endDate time.Time) ([]types.TimePattern, error)
-	PredictFutureSpending(ctx context.Context, accountID string) ([]types.PredictedSpend, error)
+	GetSpendingAnalytics(ctx context.Context, accountID string, timeRange string, opts AnalyticsOptions) (*types.AnalyticsResponse, error)
+	AnalyzeTimePatterns(ctx context.Context, accountID string, startDate, endDate time.Time, opts PatternOptions) ([]types.TimePattern, error)
+	PredictFutureSpending(ctx context.Context, accountID string, opts PredictionOptions) ([]types.PredictedSpend, error)
+	GetCurrentMonthForecast(ctx context.Context, accountID string) (*types.MonthForecast, error)
+	CheckCategoryAlerts(ctx context.Context, accountID string, budgets map[string]float64) ([]types.Alert, error)
+	GetBreakEvenIncome(ctx context.Context, accountID string, timeRange string) (float64, error)
+	CompareYearOverYear(ctx context.Context, accountID string, period string) (*types.YoYComparison, error)
+	GetFeeSummary(ctx context.Context, accountID string, timeRange string) (*types.FeeSummary, error)
+	GetNextMonthForecast(ctx context.Context, accountID string) (*types.MonthForecast, error)
+	RegisterPlannedExpense(ctx context.Context, accountID string, expense types.PlannedExpense) error
+	DetectCategorySubstitutions(ctx context.Context, accountID string, months int) ([]types.CategorySubstitution, error)
+	BacktestPredictions(ctx context.Context, accountID string) ([]types.CalibrationSample, error)
+	BuildStatement(ctx context.Context, accountID string, month time.Month, year int) (*types.Statement, error)
+	ScoreFraudRisk(ctx context.Context, accountID string, timeRange string) ([]types.FraudScore, error)
+	GetRefundSummary(ctx context.Context, accountID string, timeRange string) (*types.RefundSummary, error)
+	GetCategoryHealth(ctx context.Context, accountID string) (map[string]types.CategoryHealth, error)
+	GetMarginalSpend(ctx context.Context, accountID string, timeRange string) ([]types.MarginalSpend, error)
+	DetectEndOfPeriodSpikes(ctx context.Context, accountID string, months int, opts SpikeOptions) (*types.PeriodSpike, error)
+	GetNetSpend(ctx context.Context, accountID string, timeRange string) (*types.NetSpend, error)
+	DetectAnomalies(ctx context.Context, accountID string, timeRange string, opts AnomalyOptions) ([]types.Anomaly, error)
+	GetTenureCohorts(ctx context.Context, accountID string, openedAt time.Time) ([]types.TenureCohort, error)
+	GetDormantCategories(ctx context.Context, accountID string, inactiveDays int) ([]string, error)
+	PredictSpendingTotal(ctx context.Context, accountID string) (*types.PredictionTotal, error)
+	DetectPaydayWeekdaySpikes(ctx context.Context, accountID string, months int) ([]types.PaydaySpike, error)
+	SolveForTarget(ctx context.Context, accountID string, targetMonthlyTotal float64) (map[string]float64, error)
+	GetCategoryAttribution(ctx context.Context, accountID string, timeRange string) ([]types.CategoryAttribution, error)
+	DetectSubscriptionOverlap(ctx context.Context, accountID string) ([]types.SubscriptionOverlap, error)
+	GetSpendingGini(ctx context.Context, accountID string, timeRange string) (float64, error)
+	AnnualizeSpend(ctx context.Context, accountID string, timeRange string) (*types.AnnualizedSpend, error)
+	GetCategoryMomentum(ctx context.Context, accountID string) ([]types.CategoryMomentum, error)
+	PredictPortfolioSpending(ctx context.Context, accountIDs []string) (*types.PortfolioForecast, error)
+	GetFixedVariableSplit(ctx context.Context, accountID string, timeRange string) (*types.FixedVariableSplit, error)
+	GetForeignFeeSummary(ctx context.Context, accountID string, timeRange string) (*types.ForeignFeeSummary, error)
+	SimulateRoundUpSavings(ctx context.Context, accountID string, timeRange string) (*types.RoundUpSavings, error)
+	GetMerchantTrends(ctx context.Context, accountID string, months int) ([]types.MerchantTrend, error)
+	GetCurrentMonthCalendar(ctx context.Context, accountID string) ([][]types.DaySpend, error)
+	ProjectHabitCost(ctx context.Context, accountID string, merchant string, years int, annualReturn float64) (float64, error)
+	DetectRecurring(ctx context.Context, accountID string) ([]types.RecurringCharge, error)
+	IsPaycheckToPaycheck(ctx context.Context, accountID string) (*types.P2PStatus, error)
+	CompareSpending(ctx context.Context, accountID string, currentRange, priorRange types.DateRange) (*types.SpendingComparison, error)
+	SuggestWeeklyAllowance(ctx context.Context, accountID string, discretionaryCategories []string) (float64, error)
+	GetCombinedSpendingAnalytics(ctx context.Context, accountIDs []string, timeRange string, opts AnalyticsOptions, combine CombineOptions) (*types.AnalyticsResponse, error)
+	PredictCombinedSpending(ctx context.Context, accountIDs []string, opts PredictionOptions, combine CombineOptions) ([]types.PredictedSpend, error)
+	GetIncomeSourceSpend(ctx context.Context, accountID string, months int) ([]types.IncomeSourceSpend, error)
+	CompareMonthSeasonallyAdjusted(ctx context.Context, accountID string, period string) (*types.SeasonalComparison, error)
+	GetMerchantAnalytics(ctx context.Context, accountID string, timeRange string, limit int) ([]types.MerchantSpend, error)
+	PredictSpendingByWeek(ctx context.Context, accountID string, opts PredictionOptions) ([]types.WeekForecast, error)
+	GetCashFlow(ctx context.Context, accountID string, timeRange string) (*types.CashFlow, error)
+	CheckBudgets(ctx context.Context, accountID string, budgets map[string]float64) ([]types.BudgetStatus, error)
+	GetSpendTimeSeries(ctx context.Context, accountID string, timeRange string, interval string) ([]types.TimeBucket, error)
+	GetCategoryTotals(ctx context.Context, accountID string, timeRange string) (map[string]float64, error)
+	GetDayPartSummary(ctx context.Context, accountID string, start, end time.Time) (*types.DayPartSummary, error)
+	GetBurnRate(ctx context.Context, accountID string, timeRange string) (*types.BurnRate, error)
+	TrackGoal(ctx context.Context, accountID string, goal types.SavingsGoal) (*types.GoalProgress, error)
 }
 
 type service struct {
-	repo Repository
+	repo             Repository
+	now              func() time.Time
+	alertSink        AlertSink
+	alertThresholds  []float64
+	seed             int64
+	feeKeywords      []string
+	currency         string
+	calibrator       *Calibrator
+	configs          ConfigStore
+	incomeCategories []string
+	healthThresholds HealthThresholds
+	serviceTypes     map[string]string
+	holidays         HolidayCalendar
+	resolver         CategoryResolver
+
+	recurringTolerance float64
+	merchantSimilarity float64
+	dailyNetting       bool
+	refundNetting      bool
+	rates              RateProvider
+	predictionConfig   PredictionConfig
+	categoryAliases    map[string]string
+	categories         *normalizingRepository
+
+	// mu is shared with the views made by combined, along with the state it
+	// guards
+	mu      *sync.Mutex
+	planned map[string][]types.PlannedExpense
 }
 
-func NewService(repo Repository) Service {
-	return &service{repo: repo}
+func NewService(repo Repository, opts ...Option) Service {
+	s := &service{
+		repo:             repo,
+		now:              time.Now,
+		alertThresholds:  defaultAlertThresholds,
+		seed:             defaultSeed,
+		feeKeywords:      defaultFeeKeywords,
+		currency:         defaultCurrency,
+		incomeCategories: defaultIncomeCategories,
+		healthThresholds: DefaultHealthThresholds,
+		serviceTypes:     defaultServiceTypes,
+		resolver:         NoopResolver{},
+
+		recurringTolerance: defaultRecurringTolerance,
+		predictionConfig:   DefaultPredictionConfig,
+		merchantSimilarity: defaultMerchantSimilarity,
+		mu:               &sync.Mutex{},
+		planned:          make(map[string][]types.PlannedExpense),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.categories = &normalizingRepository{next: s.repo, aliases: s.categoryAliases, spellings: make(map[string]string)}
+	s.repo = s.categories
+	return s
+}
+
+// PatternOptions tunes time-pattern analysis. The zero value keeps every
+// bucket with at least one expense.
+type PatternOptions struct {
+	// MinFrequency drops day/hour buckets with fewer transactions, so only
+	// habitual patterns are reported
+	MinFrequency int
+	// IncludeCredits counts deposits and refunds by their size as well. By
+	// default only spending is analysed, so a payday deposit doesn't show up
+	// as a huge spend at that hour.
+	IncludeCredits bool
+	// Granularity picks the bucket transactions are grouped into. Empty means
+	// types.PatternHourly.
+	Granularity types.PatternGranularity
+	// Location is the timezone days and hours are read in, overriding the
+	// account's saved timezone. Nil means the saved one.
+	Location *time.Location
+	// Filter limits which transactions are analysed
+	Filter TransactionFilter
+}
+
+// ErrInvalidGranularity is returned for a PatternOptions.Granularity that
+// isn't one of the types.PatternGranularity values
+var ErrInvalidGranularity = errors.New("invalid pattern granularity")
+
+// patternKey identifies one time-pattern bucket
+type patternKey struct {
+	day        string
+	slot       string
+	dayOfMonth int
+}
+
+// patternBucket places a local transaction time in its bucket for g
+func patternBucket(local time.Time, g types.PatternGranularity) (patternKey, error) {
+	switch g {
+	case "", types.PatternHourly:
+		return patternKey{day: local.Format("Monday"), slot: local.Format("15:00")}, nil
+	case types.PatternDayPart:
+		return patternKey{day: local.Format("Monday"), slot: dayPart(local.Hour())}, nil
+	case types.PatternQuarterHour:
+		quarter := local.Truncate(time.Minute).Add(-time.Duration(local.Minute()%15) * time.Minute)
+		return patternKey{day: local.Format("Monday"), slot: quarter.Format("15:04")}, nil
+	case types.PatternDayOfMonth:
+		return patternKey{dayOfMonth: local.Day()}, nil
+	default:
+		return patternKey{}, fmt.Errorf("%w: %q", ErrInvalidGranularity, g)
+	}
 }
 
-func (s *service) AnalyzeTimePatterns(ctx context.Context, accountID string, startDate, endDate time.Time) ([]types.TimePattern, error) {
-	transactions, err := s.repo.GetTransactions(ctx, accountID, fmt.Sprintf("'%s'::timestamp - '%s'::timestamp", endDate.Format(time.RFC3339), startDate.Format(time.RFC3339)))
+// dayPart names the part of the day an hour falls in
+func dayPart(hour int) string {
+	switch {
+	case hour >= 5 && hour < 12:
+		return "morning"
+	case hour >= 12 && hour < 17:
+		return "afternoon"
+	case hour >= 17 && hour < 22:
+		return "evening"
+	default:
+		return "night"
+	}
+}
+
+func (s *service) AnalyzeTimePatterns(ctx context.Context, accountID string, startDate, endDate time.Time, opts PatternOptions) ([]types.TimePattern, error) {
+	patterns, _, err := s.analyzeTimePatterns(ctx, accountID, types.DateRange{Start: startDate, End: endDate}, opts)
+	return patterns, err
+}
+
+// analyzeTimePatterns is AnalyzeTimePatterns over a window that may have been
+// resolved from a relative range, which the repository can make use of. It
+// also returns the window's transactions that opts' filter kept.
+func (s *service) analyzeTimePatterns(ctx context.Context, accountID string, window types.DateRange, opts PatternOptions) ([]types.TimePattern, []types.Transaction, error) {
+	transactions, err := s.repo.GetTransactions(ctx, accountID, window)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get transactions: %w", err)
+		return nil, nil, fmt.Errorf("failed to get transactions: %w", err)
 	}
 
-	// Group transactions by day and hour
-	patterns := make(map[string]map[string]struct {
+	config, err := s.accountConfig(ctx, accountID)
+	if err != nil {
+		return nil, nil, err
+	}
+	loc, err := s.configLocation(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.Location != nil {
+		loc = opts.Location
+	}
+	kept := opts.Filter.Apply(transactions)
+	transactions = s.netDaily(kept, loc)
+
+	// Group transactions by day and hour, or whatever bucket was asked for
+	patterns := make(map[patternKey]struct {
 		totalAmount float64
 		count      int
 	})
 
-	for _, t := range transactions {
-		dayOfWeek := t.Date.Format("Monday")
-		hourOfDay := t.Date.Format("15:00")
+	for i, t := range transactions {
+		if err := checkContext(ctx, i); err != nil {
+			return nil, nil, err
+		}
+		if t.Amount >= 0 && !opts.IncludeCredits {
+			continue
+		}
 
-		if _, exists := patterns[dayOfWeek]; !exists {
-			patterns[dayOfWeek] = make(map[string]struct {
-				totalAmount float64
-				count      int
-			})
+		// Bucket by the account's local time so a 9am coffee isn't reported at 2pm
+		key, err := patternBucket(t.Date.In(loc), opts.Granularity)
+		if err != nil {
+			return nil, nil, err
 		}
 
-		stats := patterns[dayOfWeek][hourOfDay]
-		stats.totalAmount += math.Abs(t.Amount) // Use absolute value for spending analysis
+		stats := patterns[key]
+		stats.totalAmount += math.Abs(t.Amount) // Credits, when included, count by their size
 		stats.count++
-		patterns[dayOfWeek][hourOfDay] = stats
+		patterns[key] = stats
 	}
 
 	// Convert to TimePattern slice
-	var result []types.TimePattern
-	for day, hours := range patterns {
-		for hour, stats := range hours {
-			result = append(result, types.TimePattern{
-				TimeOfDay:    hour,
-				DayOfWeek:    day,
-				Frequency:    stats.count,
-				AverageSpend: stats.totalAmount / float64(stats.count),
-			})
+	result := make([]types.TimePattern, 0)
+	for key, stats := range patterns {
+		if stats.count < opts.MinFrequency {
+			continue
 		}
+		result = append(result, types.TimePattern{
+			TimeOfDay:    key.slot,
+			DayOfWeek:    key.day,
+			DayOfMonth:   key.dayOfMonth,
+			Frequency:    stats.count,
+			AverageSpend: ratio(stats.totalAmount, float64(stats.count)),
+		})
 	}
 
 	// Sort by frequency and average spend
@@ -74,142 +268,662 @@ func (s *service) AnalyzeTimePatterns(ctx context.Context, accountID string, sta
 		return result[i].Frequency > result[j].Frequency
 	})
 
-	return result, nil
+	return result, kept, nil
+}
+
+// SchemaVersion is the version of the SpendingAnalytics schema. Bump it when
+// the shape changes incompatibly, and teach MigrateAnalytics to upgrade
+// snapshots from the previous version.
+const SchemaVersion = 4
+
+// AnalyticsVersion is SchemaVersion as stamped on analytics responses
+const AnalyticsVersion = "4"
+
+// Ranking is how GetSpendingAnalytics orders its top categories
+type Ranking string
+
+const (
+	// RankByTotal ranks categories by total spend in the range
+	RankByTotal Ranking = "total"
+	// RankByRecency ranks categories by spend weighted towards recent
+	// transactions, so a large but stale one-off doesn't dominate
+	RankByRecency Ranking = "recency"
+)
+
+// ErrInvalidRanking is returned for an AnalyticsOptions.Ranking that isn't
+// one of the Ranking values
+var ErrInvalidRanking = errors.New("invalid ranking")
+
+// defaultRecencyHalfLife halves a transaction's weight for every 30 days of age
+const defaultRecencyHalfLife = 30 * day
+
+// SplitMode is how GetSpendingAnalytics counts transactions split across
+// categories
+type SplitMode string
+
+const (
+	// SplitByPortion counts each split portion towards its own category
+	SplitByPortion SplitMode = "portion"
+	// SplitByPrimary counts the whole transaction towards its own category
+	SplitByPrimary SplitMode = "primary"
+)
+
+// ErrInvalidSplitMode is returned for an AnalyticsOptions.Splits that isn't
+// one of the SplitMode values
+var ErrInvalidSplitMode = errors.New("invalid split mode")
+
+// DefaultTopN asks GetSpendingAnalytics for its default number of top
+// categories, currently 5
+const DefaultTopN = -1
+
+// defaultTopN is how many top categories DefaultTopN returns
+const defaultTopN = 5
+
+// AnalyticsOptions tunes GetSpendingAnalytics. The zero value returns every
+// category ranked by total and counts split transactions by portion.
+type AnalyticsOptions struct {
+	// TopN is how many top categories to return. Zero means all of them and
+	// DefaultTopN means the default 5.
+	TopN    int
+	Ranking Ranking
+	// RecencyHalfLife is the age at which a transaction counts half as much
+	// under RankByRecency. Zero means 30 days.
+	RecencyHalfLife time.Duration
+	// CategoryOrder pins categories to the front of the top list in the given
+	// order, regardless of spend, e.g. to always show Rent first. The rest
+	// follow in ranking order.
+	CategoryOrder []string
+	Splits        SplitMode
+	// IncludeIncome adds a summary of the income received over the range
+	IncludeIncome bool
+	// IncludeHistory gives each top category a sparkline of its recent monthly
+	// spend and its change against the previous period of the same length
+	IncludeHistory bool
+	// Window is an explicit date range to analyse in place of the relative
+	// time range, with the monthly average taken over its actual length.
+	// Unused when its start is zero.
+	Window types.DateRange
+	// Filter limits which transactions count towards the totals, and is
+	// passed on to the time patterns and predictions
+	Filter TransactionFilter
 }
 
-func (s *service) GetSpendingAnalytics(ctx context.Context, accountID string, timeRange string) (*types.SpendingAnalytics, error) {
-	categoryTotals, err := s.repo.GetCategoryTotals(ctx, accountID, timeRange)
+// loadCategoryTotals reads the spend per category over window with amounts
+// converted to the reporting currency if a rate provider is set, opts.Filter
+// applied, uncategorized spend resolved, splits applied and, if enabled,
+// refunds netted. The transactions behind the totals come back too when any
+// of that, or opts' ranking or income summary, needed them.
+func (s *service) loadCategoryTotals(ctx context.Context, accountID string, window types.DateRange, opts AnalyticsOptions) (map[string]float64, []types.Transaction, error) {
+	var categoryTotals map[string]float64
+	var transactions []types.Transaction
+	var err error
+	fromTransactions := s.rates != nil || !opts.Filter.IsZero()
+	if fromTransactions {
+		// The repository's totals would mix currencies or include filtered
+		// out spend, so convert and filter each transaction and sum them here
+		transactions, err = s.repo.GetTransactions(ctx, accountID, window)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get transactions: %w", err)
+		}
+		if s.rates != nil {
+			config, err := s.accountConfig(ctx, accountID)
+			if err != nil {
+				return nil, nil, err
+			}
+			transactions, err = s.convertCurrency(ctx, transactions, s.configCurrency(config))
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		transactions = opts.Filter.Apply(transactions)
+		categoryTotals = expenseTotals(transactions)
+	} else {
+		categoryTotals, err = LoadCategoryTotals(ctx, s.repo, accountID, window)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Split portions, recency ranking, the income summary, resolving
+	// uncategorized spend and netting refunds need the transactions behind the totals
+	_, uncategorized := categoryTotals[""]
+	if !fromTransactions && (opts.Splits != SplitByPrimary || opts.Ranking == RankByRecency || opts.IncludeIncome || uncategorized || s.refundNetting) {
+		transactions, err = s.repo.GetTransactions(ctx, accountID, window)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get transactions: %w", err)
+		}
+	}
+	if uncategorized {
+		categoryTotals, transactions, err = s.resolveCategories(ctx, categoryTotals, transactions)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	switch opts.Splits {
+	case "", SplitByPortion:
+		categoryTotals = ApplySplits(categoryTotals, transactions)
+	case SplitByPrimary:
+	default:
+		return nil, nil, fmt.Errorf("%w: %q", ErrInvalidSplitMode, opts.Splits)
+	}
+	return s.netRefundTotals(categoryTotals, transactions), transactions, nil
+}
+
+// GetCategoryTotals returns the spend in every category over a time range,
+// unrounded and untruncated, for callers doing their own math. The totals
+// are the ones GetSpendingAnalytics ranks and formats.
+func (s *service) GetCategoryTotals(ctx context.Context, accountID string, timeRange string) (map[string]float64, error) {
+	window, err := s.dateRange(timeRange)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get category totals: %w", err)
+		return nil, err
 	}
+	totals, _, err := s.loadCategoryTotals(ctx, accountID, window, AnalyticsOptions{})
+	return totals, err
+}
 
-	var totalSpent float64
-	var topCategories []types.CategorySpend
-	for category, amount := range categoryTotals {
-		totalSpent += amount
-		topCategories = append(topCategories, types.CategorySpend{
-			Category:   category,
-			TotalSpent: fmt.Sprintf("%.2f", amount),
-			Percentage: fmt.Sprintf("%.2f", (amount/totalSpent)*100),
+// GetSpendingAnalytics runs the default analytics pipeline: category totals
+// ranked to the top opts.TopN with their health, plus time patterns over the same
+// range and predictions. The result is wrapped with the currency, time range
+// and schema version it was produced with.
+func (s *service) GetSpendingAnalytics(ctx context.Context, accountID string, timeRange string, opts AnalyticsOptions) (*types.AnalyticsResponse, error) {
+	window, months, err := s.analyticsWindow(timeRange, opts.Window)
+	if err != nil {
+		return nil, err
+	}
+	if timeRange == "" {
+		timeRange = window.Start.Format("2006-01-02") + " to " + window.End.Format("2006-01-02")
+	}
+
+	// Time patterns and predictions don't depend on the category breakdown,
+	// so all three are fetched at once. The first failure cancels the rest
+	// and is the one returned.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		failOnce sync.Once
+		failure  error
+	)
+	fail := func(err error) {
+		failOnce.Do(func() {
+			failure = err
+			cancel()
 		})
 	}
 
-	// Sort by amount spent
-	sort.Slice(topCategories, func(i, j int) bool {
-		amtI, _ := strconv.ParseFloat(topCategories[i].TotalSpent, 64)
-		amtJ, _ := strconv.ParseFloat(topCategories[j].TotalSpent, 64)
-		return amtI > amtJ
-	})
+	var (
+		patterns    []types.TimePattern
+		predictions []types.PredictedSpend
+		windowed    []types.Transaction
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		// Analyze time patterns over the same window as the totals
+		var err error
+		patterns, windowed, err = s.analyzeTimePatterns(ctx, accountID, window, PatternOptions{Filter: opts.Filter})
+		if err != nil {
+			fail(fmt.Errorf("failed to analyze time patterns: %w", err))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		var err error
+		predictions, err = s.PredictFutureSpending(ctx, accountID, PredictionOptions{Filter: opts.Filter})
+		if err != nil {
+			fail(fmt.Errorf("failed to predict spending: %w", err))
+		}
+	}()
+
+	analytics, currency, err := s.categoryBreakdown(ctx, accountID, window, months, opts)
+	if err != nil {
+		fail(err)
+	}
+	wg.Wait()
+	if failure != nil {
+		return nil, failure
+	}
+	analytics.SpendingPatterns = patterns
+	analytics.PredictedSpending = predictions
+	analytics.DataQuality = gradeDataQuality(windowed)
 
-	// Get top 5 categories
-	if len(topCategories) > 5 {
-		topCategories = topCategories[:5]
+	if err := EnrichWithHealth(ctx, s, accountID, analytics); err != nil {
+		return nil, err
 	}
 
-	// Get time patterns for the last month
-	endDate := time.Now()
-	startDate := endDate.AddDate(0, -1, 0)
-	patterns, err := s.AnalyzeTimePatterns(ctx, accountID, startDate, endDate)
+	return &types.AnalyticsResponse{
+		Data:        analytics,
+		Currency:    currency,
+		TimeRange:   timeRange,
+		GeneratedAt: s.now(),
+		Version:     AnalyticsVersion,
+	}, nil
+}
+
+// categoryBreakdown is the category side of GetSpendingAnalytics: totals over
+// window, which spans the given number of months, ranked to the top
+// opts.TopN, along with the currency they are formatted in
+func (s *service) categoryBreakdown(ctx context.Context, accountID string, window types.DateRange, months float64, opts AnalyticsOptions) (*types.SpendingAnalytics, string, error) {
+	categoryTotals, transactions, err := s.loadCategoryTotals(ctx, accountID, window, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to analyze time patterns: %w", err)
+		return nil, "", err
 	}
 
-	// Get spending predictions
-	predictions, err := s.PredictFutureSpending(ctx, accountID)
+	config, err := s.accountConfig(ctx, accountID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to predict spending: %w", err)
+		return nil, "", err
 	}
 
-	return &types.SpendingAnalytics{
-		TopCategories:     topCategories,
-		SpendingPatterns: patterns,
-		PredictedSpending: predictions,
-		TotalSpent:       totalSpent,
-		MonthlyAverage:   totalSpent / float64(timeRangeToMonths(timeRange)),
-	}, nil
+	currency := s.configCurrency(config)
+	categories, totalSpent := AggregateCategories(categoryTotals, currency)
+
+	// Pinned categories can come from anywhere in the ranking, so only a
+	// plain top list can be selected without ranking everything
+	topN := opts.TopN
+	if topN < 0 {
+		topN = defaultTopN
+	}
+	limit := topN
+	if len(opts.CategoryOrder) > 0 {
+		limit = 0
+	}
+
+	var topCategories []types.CategorySpend
+	switch opts.Ranking {
+	case "", RankByTotal:
+		topCategories = RankCategories(categories, limit)
+	case RankByRecency:
+		halfLife := opts.RecencyHalfLife
+		if halfLife <= 0 {
+			halfLife = defaultRecencyHalfLife
+		}
+		topCategories = RankCategoriesByScore(categories, RecencyScores(transactions, s.now(), halfLife), limit)
+	default:
+		return nil, "", fmt.Errorf("%w: %q", ErrInvalidRanking, opts.Ranking)
+	}
+	topCategories = PinCategories(topCategories, opts.CategoryOrder, topN)
+	if opts.IncludeHistory {
+		if err := s.attachHistory(ctx, accountID, window, categoryTotals, opts.Splits, topCategories); err != nil {
+			return nil, "", err
+		}
+	}
+
+	analytics := &types.SpendingAnalytics{
+		SchemaVersion:  SchemaVersion,
+		TopCategories:  topCategories,
+		TotalSpent:     totalSpent,
+		MonthlyAverage: ratio(totalSpent, months),
+	}
+	if opts.IncludeIncome {
+		analytics.Income = s.incomeSummary(transactions, totalSpent)
+	}
+	return analytics, currency, nil
+}
+
+// PredictionOptions tunes spending predictions. The zero value returns the
+// predictions alone.
+type PredictionOptions struct {
+	// IncludeTransactions attaches the history each prediction was made from,
+	// so users can check what a forecast is based on
+	IncludeTransactions bool
+	// Average is how each category's expected amount is taken from its past
+	// amounts. The zero value is AverageMean.
+	Average AmountAverage
+	// Filter limits which transactions predictions are made from
+	Filter TransactionFilter
+}
+
+// AmountAverage is how a prediction's expected amount is taken from the
+// amounts a category has been charged
+type AmountAverage string
+
+const (
+	// AverageMean is the plain mean of every amount
+	AverageMean AmountAverage = "mean"
+	// AverageMedian is the middle amount, which one-off purchases barely move
+	AverageMedian AmountAverage = "median"
+	// AverageTrimmed is the mean once the highest and lowest trimmedShare of
+	// amounts are left out
+	AverageTrimmed AmountAverage = "trimmed"
+)
+
+// trimmedShare is the share of amounts AverageTrimmed drops from each end
+const trimmedShare = 0.1
+
+// ErrInvalidAverage is returned for a PredictionOptions.Average that isn't
+// one of the AmountAverage values
+var ErrInvalidAverage = errors.New("invalid prediction average")
+
+// central returns the typical amount of amounts by the given average
+func central(amounts []float64, average AmountAverage) (float64, error) {
+	switch average {
+	case "", AverageMean:
+		return mean(amounts), nil
+	case AverageMedian:
+		return median(amounts), nil
+	case AverageTrimmed:
+		trim := int(math.Ceil(float64(len(amounts)) * trimmedShare))
+		if 2*trim >= len(amounts) {
+			return median(amounts), nil
+		}
+		sorted := append([]float64(nil), amounts...)
+		sort.Float64s(sorted)
+		return mean(sorted[trim : len(sorted)-trim]), nil
+	default:
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAverage, average)
+	}
+}
+
+// PredictionConfig tunes the history predictions learn from and how their
+// likelihoods are scored
+type PredictionConfig struct {
+	// Lookback is the relative time range of history predictions learn from
+	Lookback string
+	// AmountCap is the average amount at which a category's amount stops
+	// adding to its likelihood. Raise it for accounts whose everyday spend
+	// would otherwise saturate it.
+	AmountCap float64
+	// FrequencyWindow is the gap between charges at or below which a
+	// category's frequency stops adding to its likelihood
+	FrequencyWindow time.Duration
+}
+
+// DefaultPredictionConfig learns from six months of history, with likelihoods
+// topping out at $1000 charges and monthly or more frequent ones
+var DefaultPredictionConfig = PredictionConfig{
+	Lookback:        "6 months",
+	AmountCap:       1000,
+	FrequencyWindow: 30 * day,
+}
+
+// WithPredictionConfig overrides how predictions are made. Fields left at
+// zero keep their defaults.
+func WithPredictionConfig(config PredictionConfig) Option {
+	return func(s *service) {
+		if config.Lookback != "" {
+			s.predictionConfig.Lookback = config.Lookback
+		}
+		if config.AmountCap > 0 {
+			s.predictionConfig.AmountCap = config.AmountCap
+		}
+		if config.FrequencyWindow > 0 {
+			s.predictionConfig.FrequencyWindow = config.FrequencyWindow
+		}
+	}
+}
+
+// likelihood scores a category from how often it is charged, in charges per
+// day, and its typical amount, each term capped at 1 by the config
+func (c PredictionConfig) likelihood(frequency, amount float64) float64 {
+	normalizedFreq := math.Min(frequency*c.FrequencyWindow.Hours()/24, 1.0)
+	normalizedAmount := math.Min(amount/c.AmountCap, 1.0)
+	return (normalizedFreq + normalizedAmount) / 2.0
 }
 
-func (s *service) PredictFutureSpending(ctx context.Context, accountID string) ([]types.PredictedSpend, error) {
-	// Get last 6 months of transactions for better prediction
-	transactions, err := s.repo.GetTransactions(ctx, accountID, "6 months")
+func (s *service) PredictFutureSpending(ctx context.Context, accountID string, opts PredictionOptions) ([]types.PredictedSpend, error) {
+	transactions, err := s.loadTransactions(ctx, accountID, s.predictionConfig.Lookback)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get transactions: %w", err)
 	}
+	config, err := s.accountConfig(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Predict in the currency the warnings are written in
+	currency := s.configCurrency(config)
+	transactions, err = s.inCurrency(ctx, transactions, currency)
+	if err != nil {
+		return nil, err
+	}
+	return s.predictFromTransactions(ctx, s.netRefunds(transactions), opts, currency)
+}
+
+// predictFromTransactions predicts the next spend in every category with
+// enough history, most likely first, with warnings written in currency. It
+// gives up with the context's error once ctx is done.
+func (s *service) predictFromTransactions(ctx context.Context, transactions []types.Transaction, opts PredictionOptions, currency string) ([]types.PredictedSpend, error) {
+	if _, err := central(nil, opts.Average); err != nil {
+		return nil, err
+	}
 
-	// Group transactions by category
+	// Group expenses by category; deposits and refunds aren't spending
 	categoryTransactions := make(map[string][]types.Transaction)
-	for _, t := range transactions {
+	for i, t := range opts.Filter.Apply(transactions) {
+		if err := checkContext(ctx, i); err != nil {
+			return nil, err
+		}
+		if expenseAmount(t) == 0 {
+			continue
+		}
 		categoryTransactions[t.Category] = append(categoryTransactions[t.Category], t)
 	}
 
-	var predictions []types.PredictedSpend
+	now := s.now()
+	predictions := make([]types.PredictedSpend, 0)
+	histories := make(map[string][]types.Transaction)
+	var intervals []float64
 	for category, txns := range categoryTransactions {
-		if len(txns) < 3 {
-			continue // Need at least 3 transactions for prediction
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
-
 		// Sort transactions by date
 		sort.Slice(txns, func(i, j int) bool {
 			return txns[i].Date.Before(txns[j].Date)
 		})
-
-		// Calculate average time between transactions
-		var totalDuration time.Duration
-		for i := 1; i < len(txns); i++ {
-			totalDuration += txns[i].Date.Sub(txns[i-1].Date)
-		}
-		avgTimeBetween := totalDuration / time.Duration(len(txns)-1)
-
-		// Calculate frequency and amount metrics
-		frequency := float64(len(txns)) / 180 // Normalize by 6 months (180 days)
-		var totalAmount float64
-		for _, t := range txns {
-			totalAmount += math.Abs(t.Amount)
+		if len(txns) < 3 {
+			continue // Need at least 3 transactions for prediction
 		}
-		avgAmount := totalAmount / float64(len(txns))
-
-		// Calculate likelihood score
-		normalizedFreq := math.Min(frequency*30, 1.0)  // Normalize to max 1.0 (30 days)
-		normalizedAmount := math.Min(avgAmount/1000, 1.0) // Normalize to max 1.0 ($1000)
-		likelihood := (normalizedFreq + normalizedAmount) / 2.0
 
-		// Generate prediction
-		lastTransaction := txns[len(txns)-1]
-		predictedDate := lastTransaction.Date.Add(avgTimeBetween)
+		prediction := predictCategory(category, txns, opts.Average, s.predictionConfig)
+		intervals = append(intervals, float64(prediction.PredictedDate.Sub(txns[len(txns)-1].Date)))
+		applyTrend(&prediction, txns, now)
+		predictions = append(predictions, prediction)
+		histories[category] = txns
+	}
 
-		warning := ""
-		if likelihood > 0.7 {
-			warning = fmt.Sprintf("High likelihood (%.0f%%) of spending in %s category around %s",
-				likelihood*100, category, predictedDate.Format("Jan 02"))
+	// New categories borrow the account's usual cadence
+	prior := time.Duration(median(intervals))
+	for category, txns := range categoryTransactions {
+		if len(txns) == coldStartTransactions {
+			predictions = append(predictions, coldStartPrediction(category, txns, prior, opts.Average, s.predictionConfig))
+			histories[category] = txns
 		}
+	}
 
-		predictions = append(predictions, types.PredictedSpend{
-			Category:      category,
-			Likelihood:    likelihood,
-			PredictedDate: predictedDate,
-			Warning:       warning,
-		})
+	for i := range predictions {
+		prediction := &predictions[i]
+		txns := histories[prediction.Category]
+		if s.calibrator != nil {
+			prediction.RawLikelihood = prediction.Likelihood
+			prediction.Likelihood = s.calibrator.Calibrate(prediction.Likelihood)
+		}
+		prediction.Likelihood *= stalenessDecay(now, txns[len(txns)-1].Date, prediction.PredictedDate)
+		prediction.Warning = predictionWarning(*prediction, currency)
+		prediction.DaysUntil, prediction.Overdue = countdown(now, prediction.PredictedDate)
+		if opts.IncludeTransactions {
+			prediction.Basis = &types.PredictionBasis{
+				Count:        len(txns),
+				From:         txns[0].Date,
+				To:           txns[len(txns)-1].Date,
+				Transactions: txns,
+			}
+		}
 	}
 
-	// Sort by likelihood
+	// Sort by likelihood, breaking ties by category so map iteration order
+	// never leaks into the output
 	sort.Slice(predictions, func(i, j int) bool {
+		if predictions[i].Likelihood == predictions[j].Likelihood {
+			return predictions[i].Category < predictions[j].Category
+		}
 		return predictions[i].Likelihood > predictions[j].Likelihood
 	})
 
 	return predictions, nil
 }
 
-func timeRangeToMonths(timeRange string) float64 {
-	switch timeRange {
-	case "1 month":
-		return 1
-	case "3 months":
-		return 3
-	case "6 months":
-		return 6
-	case "1 year":
-		return 12
-	default:
+// contextCheckInterval is how many loop iterations pass between checks that
+// the caller hasn't given up
+const contextCheckInterval = 256
+
+// checkContext returns ctx's error on every contextCheckInterval-th
+// iteration, starting with the first, once ctx is done
+func checkContext(ctx context.Context, i int) error {
+	if i%contextCheckInterval != 0 {
+		return nil
+	}
+	return ctx.Err()
+}
+
+// PredictSpendingTotal adds up the predicted amount of every category's next
+// spend, both as is and weighted by each prediction's likelihood so uncertain
+// categories count for less in the headline number
+func (s *service) PredictSpendingTotal(ctx context.Context, accountID string) (*types.PredictionTotal, error) {
+	predictions, err := s.PredictFutureSpending(ctx, accountID, PredictionOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	total := &types.PredictionTotal{Predictions: predictions}
+	for _, p := range predictions {
+		total.Unweighted += p.PredictedAmount
+		total.Weighted += p.PredictedAmount * p.Likelihood
+	}
+	return total, nil
+}
+
+// predictCategory predicts the next spend in a category from its transaction
+// history, which must be sorted by date and hold at least two transactions.
+// The expected amount is the history's typical amount by average, which must
+// be valid, and the likelihood is scored by config.
+func predictCategory(category string, txns []types.Transaction, average AmountAverage, config PredictionConfig) types.PredictedSpend {
+	// Calculate average time between transactions
+	var totalDuration time.Duration
+	intervals := make([]time.Duration, 0, len(txns)-1)
+	for i := 1; i < len(txns); i++ {
+		interval := txns[i].Date.Sub(txns[i-1].Date)
+		intervals = append(intervals, interval)
+		totalDuration += interval
+	}
+	avgTimeBetween := totalDuration / time.Duration(len(txns)-1)
+	cadence, _ := cadenceOf(intervals, 0.5)
+
+	// Calculate frequency and amount metrics over the days the history
+	// actually spans, so short months and leap days are counted exactly
+	observed := calendarDays(txns[0].Date, txns[len(txns)-1].Date)
+	if observed < 1 {
+		observed = 1
+	}
+	frequency := float64(len(txns)) / float64(observed)
+	amounts := make([]float64, len(txns))
+	for i, t := range txns {
+		amounts[i] = expenseAmount(t)
+	}
+	avgAmount, _ := central(amounts, average)
+
+	// One standard deviation either side of the average, so categories with
+	// erratic amounts get a wide range; spend can't go below zero
+	spread := stddev(amounts)
+	amountLow := math.Max(avgAmount-spread, 0)
+	amountHigh := avgAmount + spread
+
+	likelihood := config.likelihood(frequency, avgAmount)
+
+	// Generate prediction
+	lastTransaction := txns[len(txns)-1]
+	predictedDate := lastTransaction.Date.Add(avgTimeBetween)
+
+	return types.PredictedSpend{
+		Category:        category,
+		Likelihood:      likelihood,
+		PredictedDate:   predictedDate,
+		PredictedAmount: avgAmount,
+		AmountLow:       amountLow,
+		AmountHigh:      amountHigh,
+		Cadence:         cadence,
+	}
+}
+
+// coldStartTransactions is how many transactions a category needs for a
+// cold-start prediction; with fewer it could be a one-off
+const coldStartTransactions = 2
+
+// coldStartDiscount scales down cold-start likelihoods, which rest on a single
+// observed gap
+const coldStartDiscount = 0.5
+
+// coldStartPrediction predicts the next spend in a category too new for
+// predictCategory, from its two transactions sorted by date. The gap between
+// them is averaged with prior, the account's usual gap between charges, when
+// there is one, the amounts are averaged as average says, and the likelihood
+// is discounted.
+func coldStartPrediction(category string, txns []types.Transaction, prior time.Duration, average AmountAverage, config PredictionConfig) types.PredictedSpend {
+	last := txns[len(txns)-1]
+	interval := last.Date.Sub(txns[0].Date)
+	if prior > 0 {
+		interval = (interval + prior) / 2
+	}
+
+	amounts := make([]float64, len(txns))
+	for i, t := range txns {
+		amounts[i] = expenseAmount(t)
+	}
+	avgAmount, _ := central(amounts, average)
+	spread := stddev(amounts)
+
+	days := math.Max(interval.Hours()/24, 1)
+
+	return types.PredictedSpend{
+		Category:        category,
+		Likelihood:      config.likelihood(1/days, avgAmount) * coldStartDiscount,
+		PredictedDate:   last.Date.Add(interval),
+		PredictedAmount: avgAmount,
+		AmountLow:       math.Max(avgAmount-spread, 0),
+		AmountHigh:      avgAmount + spread,
+		Cadence:         classifyInterval(interval),
+		ColdStart:       true,
+	}
+}
+
+// countdown returns the whole days from now until a predicted date, clamped
+// to zero and flagged overdue once the date has passed
+func countdown(now, predicted time.Time) (int, bool) {
+	days := calendarDays(now, predicted)
+	if days < 0 {
+		return 0, true
+	}
+	return days, false
+}
+
+// stalenessDecay scales down the likelihood of a prediction whose date has
+// already passed, since the pattern may have stopped. The longer it is overdue
+// relative to the category's usual interval, the sharper the cut; predictions
+// still on schedule keep their likelihood.
+func stalenessDecay(now, last, predicted time.Time) float64 {
+	interval := predicted.Sub(last)
+	overdue := now.Sub(predicted)
+	if overdue <= 0 || interval <= 0 {
 		return 1
 	}
+	return math.Exp(-float64(overdue) / float64(interval))
+}
+
+// predictionWarning flags high-likelihood predictions for the UI, or returns
+// nil for the rest. Amounts are in currency.
+func predictionWarning(p types.PredictedSpend, currency string) *types.PredictionWarning {
+	if p.Likelihood <= 0.7 {
+		return nil
+	}
+	return &types.PredictionWarning{
+		Severity:      types.WarningHigh,
+		Category:      p.Category,
+		PredictedDate: p.PredictedDate,
+		Likelihood:    p.Likelihood,
+		Amount:        p.PredictedAmount,
+		AmountLow:     p.AmountLow,
+		AmountHigh:    p.AmountHigh,
+		Currency:      currency,
+	}
 } 
\ No newline at end of file
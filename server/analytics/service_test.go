@@ -0,0 +1,542 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"server/types"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeRepo serves a fixed set of transactions regardless of the requested range
+// and records the ranges it was asked for
+type fakeRepo struct {
+	transactions []types.Transaction
+	balance      float64
+
+	mu     sync.Mutex
+	ranges []types.DateRange
+}
+
+// record notes a requested range; reads may arrive concurrently
+func (f *fakeRepo) record(window types.DateRange) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ranges = append(f.ranges, window)
+}
+
+func (f *fakeRepo) GetTransactions(ctx context.Context, accountID string, window types.DateRange) ([]types.Transaction, error) {
+	f.record(window)
+	return f.transactions, nil
+}
+
+func (f *fakeRepo) GetBalance(ctx context.Context, accountID string) (float64, error) {
+	return f.balance, nil
+}
+
+func (f *fakeRepo) GetCategoryTotals(ctx context.Context, accountID string, window types.DateRange) (map[string]float64, error) {
+	f.record(window)
+	totals := make(map[string]float64)
+	for _, t := range f.transactions {
+		if t.Amount < 0 {
+			totals[t.Category] -= t.Amount
+		}
+	}
+	return totals, nil
+}
+
+// txn builds a transaction dated at noon UTC on the given day
+func txn(date string, amount float64, category, merchant string) types.Transaction {
+	d, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		panic(err)
+	}
+	return types.Transaction{
+		Date:     d.Add(12 * time.Hour),
+		Amount:   amount,
+		Category: category,
+		Merchant: merchant,
+	}
+}
+
+// fixedClock pins the service's notion of "now"
+func fixedClock(date string) Option {
+	d, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		panic(err)
+	}
+	return WithClock(func() time.Time { return d.Add(12 * time.Hour) })
+}
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 0.01
+}
+
+func TestGetSpendingAnalyticsEnvelope(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-03-12", -84.20, "Food", "Whole Foods"),
+	}}
+	svc := NewService(repo, fixedClock("2025-04-15"), WithCurrency("eur"))
+
+	got, err := svc.GetSpendingAnalytics(context.Background(), "1234567891", "3 months", AnalyticsOptions{})
+	if err != nil {
+		t.Fatalf("GetSpendingAnalytics() failed: %v", err)
+	}
+
+	if got.Data == nil || !approxEqual(got.Data.TotalSpent, 84.20) {
+		t.Errorf("Data = %+v, want the analytics payload", got.Data)
+	}
+	if got.Currency != "EUR" {
+		t.Errorf("Currency = %q, want EUR", got.Currency)
+	}
+	if got.TimeRange != "3 months" {
+		t.Errorf("TimeRange = %q, want 3 months", got.TimeRange)
+	}
+	if want := txn("2025-04-15", 0, "", "").Date; !got.GeneratedAt.Equal(want) {
+		t.Errorf("GeneratedAt = %v, want %v", got.GeneratedAt, want)
+	}
+	if got.Version != AnalyticsVersion {
+		t.Errorf("Version = %q, want %q", got.Version, AnalyticsVersion)
+	}
+}
+
+func TestGetSpendingAnalyticsPatternWindow(t *testing.T) {
+	now := txn("2025-04-15", 0, "", "").Date
+	tests := []struct {
+		timeRange string
+		wantStart time.Time
+	}{
+		{timeRange: "1 month", wantStart: now.AddDate(0, -1, 0)},
+		{timeRange: "6 months", wantStart: now.AddDate(0, -6, 0)},
+		{timeRange: "1 year", wantStart: now.AddDate(-1, 0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.timeRange, func(t *testing.T) {
+			repo := &fakeRepo{}
+			svc := NewService(repo, fixedClock("2025-04-15"))
+			if _, err := svc.GetSpendingAnalytics(context.Background(), "1234567891", tt.timeRange, AnalyticsOptions{}); err != nil {
+				t.Fatalf("GetSpendingAnalytics() failed: %v", err)
+			}
+
+			want := types.DateRange{Start: tt.wantStart, End: now}
+			var found bool
+			for _, r := range repo.ranges {
+				found = found || r.Start.Equal(want.Start) && r.End.Equal(want.End)
+			}
+			if !found {
+				t.Errorf("pattern window not requested; ranges = %v, want %v", repo.ranges, want)
+			}
+		})
+	}
+}
+
+func TestGetSpendingAnalyticsRanking(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-01-20", -900.00, "Travel", "Delta"),
+		txn("2025-04-10", -120.00, "Food", "Whole Foods"),
+		txn("2025-04-14", -80.00, "Food", "Trader Joe's"),
+	}}
+	svc := NewService(repo, fixedClock("2025-04-15"))
+
+	tests := []struct {
+		name    string
+		opts    AnalyticsOptions
+		wantTop string
+	}{
+		{name: "default ranks by total", opts: AnalyticsOptions{}, wantTop: "Travel"},
+		{name: "recency favours recent spend", opts: AnalyticsOptions{Ranking: RankByRecency}, wantTop: "Food"},
+		{name: "long half-life approaches total", opts: AnalyticsOptions{Ranking: RankByRecency, RecencyHalfLife: 3650 * day}, wantTop: "Travel"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.GetSpendingAnalytics(context.Background(), "1234567891", "3 months", tt.opts)
+			if err != nil {
+				t.Fatalf("GetSpendingAnalytics() failed: %v", err)
+			}
+			if top := got.Data.TopCategories[0].Category; top != tt.wantTop {
+				t.Errorf("top category = %q, want %q", top, tt.wantTop)
+			}
+		})
+	}
+
+	if _, err := svc.GetSpendingAnalytics(context.Background(), "1234567891", "3 months", AnalyticsOptions{Ranking: "popularity"}); !errors.Is(err, ErrInvalidRanking) {
+		t.Errorf("GetSpendingAnalytics() with an unknown ranking error = %v, want ErrInvalidRanking", err)
+	}
+
+	mux := http.NewServeMux()
+	NewHandler(svc).RegisterRoutes(mux)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/analytics/1234567891?rank=popularity", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("handler status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestEmptyResultsEncodeAsEmptyArrays(t *testing.T) {
+	svc := NewService(&fakeRepo{}, fixedClock("2025-04-15"))
+	ctx := context.Background()
+
+	analytics, err := svc.GetSpendingAnalytics(ctx, "1234567891", "1 month", AnalyticsOptions{})
+	if err != nil {
+		t.Fatalf("GetSpendingAnalytics() failed: %v", err)
+	}
+	patterns, err := svc.AnalyzeTimePatterns(ctx, "1234567891", time.Now().AddDate(0, -1, 0), time.Now(), PatternOptions{})
+	if err != nil {
+		t.Fatalf("AnalyzeTimePatterns() failed: %v", err)
+	}
+	predictions, err := svc.PredictFutureSpending(ctx, "1234567891", PredictionOptions{})
+	if err != nil {
+		t.Fatalf("PredictFutureSpending() failed: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{name: "categories", value: analytics.Data.TopCategories},
+		{name: "analytics patterns", value: analytics.Data.SpendingPatterns},
+		{name: "analytics predictions", value: analytics.Data.PredictedSpending},
+		{name: "patterns", value: patterns},
+		{name: "predictions", value: predictions},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.value)
+		if err != nil {
+			t.Fatalf("json.Marshal(%s) failed: %v", tt.name, err)
+		}
+		if string(data) != "[]" {
+			t.Errorf("%s encoded as %s, want []", tt.name, data)
+		}
+	}
+}
+
+func TestGetSpendingAnalyticsSplits(t *testing.T) {
+	shop := txn("2025-04-02", -100, "Food", "Target")
+	shop.Splits = []types.Split{
+		{Category: "Household", Amount: -33.33},
+		{Category: "Clothing", Amount: -33.33},
+	}
+	repo := &fakeRepo{transactions: []types.Transaction{
+		shop,
+		txn("2025-04-05", -50, "Food", "Whole Foods"),
+		txn("2025-04-09", -15, "Entertainment", "Netflix"),
+	}}
+	svc := NewService(repo, fixedClock("2025-04-15"))
+
+	got, err := svc.GetSpendingAnalytics(context.Background(), "1234567891", "1 month", AnalyticsOptions{})
+	if err != nil {
+		t.Fatalf("GetSpendingAnalytics() failed: %v", err)
+	}
+
+	amounts := make(map[string]string)
+	var percentTotal float64
+	for _, c := range got.Data.TopCategories {
+		amounts[c.Category] = c.TotalSpent
+		p, err := strconv.ParseFloat(c.Percentage, 64)
+		if err != nil {
+			t.Fatalf("%s Percentage %q is not a number", c.Category, c.Percentage)
+		}
+		percentTotal += p
+	}
+	if amounts["Food"] != "83.34" || amounts["Household"] != "33.33" || amounts["Clothing"] != "33.33" {
+		t.Errorf("category totals = %v, want the split portions moved out of Food", amounts)
+	}
+	if math.Abs(percentTotal-100) > 0.05 {
+		t.Errorf("percentages sum to %v, want 100", percentTotal)
+	}
+	if !approxEqual(got.Data.TotalSpent, 165) {
+		t.Errorf("TotalSpent = %v, want 165", got.Data.TotalSpent)
+	}
+
+	got, err = svc.GetSpendingAnalytics(context.Background(), "1234567891", "1 month", AnalyticsOptions{Splits: SplitByPrimary})
+	if err != nil {
+		t.Fatalf("GetSpendingAnalytics() failed: %v", err)
+	}
+	if len(got.Data.TopCategories) != 2 || got.Data.TopCategories[0].TotalSpent != "150.00" {
+		t.Errorf("by primary TopCategories = %+v, want Food at 150.00 and Entertainment", got.Data.TopCategories)
+	}
+
+	if _, err := svc.GetSpendingAnalytics(context.Background(), "1234567891", "1 month", AnalyticsOptions{Splits: "evenly"}); !errors.Is(err, ErrInvalidSplitMode) {
+		t.Errorf("GetSpendingAnalytics() with an unknown split mode error = %v, want ErrInvalidSplitMode", err)
+	}
+
+	mux := http.NewServeMux()
+	NewHandler(svc).RegisterRoutes(mux)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/analytics/1234567891?splits=evenly", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("handler status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestGetSpendingAnalyticsIncome(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-04-01", 2000, "Income", "Acme Payroll"),
+		txn("2025-04-03", 40, "Food", "Whole Foods"),
+		txn("2025-04-05", -100, "Food", "Whole Foods"),
+		txn("2025-04-09", -15, "Entertainment", "Netflix"),
+	}}
+	svc := NewService(repo, fixedClock("2025-04-15"))
+
+	got, err := svc.GetSpendingAnalytics(context.Background(), "1234567891", "1 month", AnalyticsOptions{})
+	if err != nil {
+		t.Fatalf("GetSpendingAnalytics() failed: %v", err)
+	}
+	if !approxEqual(got.Data.TotalSpent, 115) {
+		t.Errorf("TotalSpent = %v, want 115 with the credits left out", got.Data.TotalSpent)
+	}
+	for _, c := range got.Data.TopCategories {
+		if c.Category == "Income" {
+			t.Errorf("TopCategories includes Income: %+v", c)
+		}
+	}
+	if got.Data.Income != nil {
+		t.Errorf("Income = %+v, want nil unless requested", got.Data.Income)
+	}
+
+	got, err = svc.GetSpendingAnalytics(context.Background(), "1234567891", "1 month", AnalyticsOptions{IncludeIncome: true})
+	if err != nil {
+		t.Fatalf("GetSpendingAnalytics() failed: %v", err)
+	}
+	income := got.Data.Income
+	if income == nil {
+		t.Fatal("Income = nil, want a summary")
+	}
+	if !approxEqual(income.Total, 2000) || !approxEqual(income.ByCategory["Income"], 2000) {
+		t.Errorf("Income = %+v, want 2000 from payroll and the refund left out", income)
+	}
+	if !approxEqual(income.Net, 1885) {
+		t.Errorf("Net = %v, want 1885", income.Net)
+	}
+}
+
+func TestGetSpendingAnalyticsTopN(t *testing.T) {
+	var transactions []types.Transaction
+	for i, category := range []string{"Rent", "Food", "Travel", "Shopping", "Dining", "Fuel", "Books"} {
+		transactions = append(transactions, txn("2025-04-05", -float64(700-i*100), category, category+" Co"))
+	}
+	svc := NewService(&fakeRepo{transactions: transactions}, fixedClock("2025-04-15"))
+
+	tests := []struct {
+		name string
+		topN int
+		want []string
+	}{
+		{name: "top 3", topN: 3, want: []string{"Rent", "Food", "Travel"}},
+		{name: "all", topN: 0, want: []string{"Rent", "Food", "Travel", "Shopping", "Dining", "Fuel", "Books"}},
+		{name: "more than there are", topN: 20, want: []string{"Rent", "Food", "Travel", "Shopping", "Dining", "Fuel", "Books"}},
+		{name: "default", topN: DefaultTopN, want: []string{"Rent", "Food", "Travel", "Shopping", "Dining"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.GetSpendingAnalytics(context.Background(), "1234567891", "1 month", AnalyticsOptions{TopN: tt.topN})
+			if err != nil {
+				t.Fatalf("GetSpendingAnalytics() failed: %v", err)
+			}
+			var categories []string
+			for _, c := range got.Data.TopCategories {
+				categories = append(categories, c.Category)
+			}
+			if !reflect.DeepEqual(categories, tt.want) {
+				t.Errorf("TopCategories = %v, want %v", categories, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSpendingAnalyticsHistory(t *testing.T) {
+	repo := &windowRepo{fakeRepo{transactions: []types.Transaction{
+		txn("2025-01-10", -50, "Food", "Chipotle"),
+		txn("2025-03-01", -80, "Food", "Chipotle"),
+		txn("2025-03-20", -60, "Food", "Chipotle"),
+		txn("2025-04-05", -100, "Food", "Chipotle"),
+	}}}
+	svc := NewService(repo, fixedClock("2025-04-15"))
+
+	got, err := svc.GetSpendingAnalytics(context.Background(), "1234567891", "1 month", AnalyticsOptions{})
+	if err != nil {
+		t.Fatalf("GetSpendingAnalytics() failed: %v", err)
+	}
+	if c := got.Data.TopCategories[0]; c.Sparkline != nil || c.Delta != nil {
+		t.Errorf("TopCategories[0] = %+v, want no history unless asked for", c)
+	}
+
+	got, err = svc.GetSpendingAnalytics(context.Background(), "1234567891", "1 month", AnalyticsOptions{IncludeHistory: true})
+	if err != nil {
+		t.Fatalf("GetSpendingAnalytics() failed: %v", err)
+	}
+	if len(got.Data.TopCategories) != 1 {
+		t.Fatalf("TopCategories = %+v, want only Food", got.Data.TopCategories)
+	}
+	food := got.Data.TopCategories[0]
+	// November through April, the current month to date last
+	wantSparkline := []float64{0, 0, 50, 0, 140, 100}
+	if !reflect.DeepEqual(food.Sparkline, wantSparkline) {
+		t.Errorf("Sparkline = %v, want %v", food.Sparkline, wantSparkline)
+	}
+	if food.Delta == nil {
+		t.Fatal("Delta = nil, want the change against the prior month")
+	}
+	if !approxEqual(food.Delta.Current, 160) || !approxEqual(food.Delta.Prior, 80) ||
+		!approxEqual(food.Delta.Change, 80) || !approxEqual(food.Delta.PercentChange, 100) {
+		t.Errorf("Delta = %+v, want 160 against 80, up 100%%", food.Delta)
+	}
+}
+
+// slowRepo is a fakeRepo whose every read takes delay, counting the reads
+type slowRepo struct {
+	fakeRepo
+	delay time.Duration
+
+	reads atomic.Int32
+}
+
+func (s *slowRepo) GetTransactions(ctx context.Context, accountID string, window types.DateRange) ([]types.Transaction, error) {
+	s.reads.Add(1)
+	time.Sleep(s.delay)
+	return s.fakeRepo.GetTransactions(ctx, accountID, window)
+}
+
+func (s *slowRepo) GetCategoryTotals(ctx context.Context, accountID string, window types.DateRange) (map[string]float64, error) {
+	s.reads.Add(1)
+	time.Sleep(s.delay)
+	return s.fakeRepo.GetCategoryTotals(ctx, accountID, window)
+}
+
+func TestGetSpendingAnalyticsConcurrent(t *testing.T) {
+	repo := &slowRepo{fakeRepo: fakeRepo{transactions: []types.Transaction{
+		txn("2025-04-03", -12.50, "Food", "Chipotle"),
+		txn("2025-04-10", -13.10, "Food", "Chipotle"),
+	}}, delay: 50 * time.Millisecond}
+	svc := NewService(repo, fixedClock("2025-04-15"))
+
+	start := time.Now()
+	if _, err := svc.GetSpendingAnalytics(context.Background(), "1234567891", "1 month", AnalyticsOptions{Splits: SplitByPrimary}); err != nil {
+		t.Fatalf("GetSpendingAnalytics() failed: %v", err)
+	}
+	elapsed := time.Since(start)
+
+	// Totals, patterns and predictions are one read each and overlap, then
+	// health needs one more: about two reads' worth rather than four
+	sequential := time.Duration(repo.reads.Load()) * repo.delay
+	if elapsed >= sequential-repo.delay {
+		t.Errorf("GetSpendingAnalytics() took %v for %d reads of %v each, want the independent reads overlapped", elapsed, repo.reads.Load(), repo.delay)
+	}
+}
+
+// failingRepo fails every transaction read
+type failingRepo struct {
+	fakeRepo
+}
+
+func (f *failingRepo) GetTransactions(ctx context.Context, accountID string, window types.DateRange) ([]types.Transaction, error) {
+	return nil, errors.New("connection reset")
+}
+
+func TestGetSpendingAnalyticsConcurrentFailure(t *testing.T) {
+	svc := NewService(&failingRepo{}, fixedClock("2025-04-15"))
+	_, err := svc.GetSpendingAnalytics(context.Background(), "1234567891", "1 month", AnalyticsOptions{Splits: SplitByPrimary})
+	if err == nil {
+		t.Fatal("GetSpendingAnalytics() succeeded, want the read failure")
+	}
+	if !strings.Contains(err.Error(), "connection reset") || !strings.Contains(err.Error(), "failed to") {
+		t.Errorf("GetSpendingAnalytics() error = %v, want the failure wrapped with context", err)
+	}
+}
+
+// nonFinite lists the paths of any NaN or infinite numbers within v, including
+// numbers formatted into strings
+func nonFinite(v reflect.Value, path string) []string {
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if f := v.Float(); math.IsNaN(f) || math.IsInf(f, 0) {
+			return []string{path}
+		}
+	case reflect.String:
+		if f, err := strconv.ParseFloat(v.String(), 64); err == nil && (math.IsNaN(f) || math.IsInf(f, 0)) {
+			return []string{path}
+		}
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			return nonFinite(v.Elem(), path)
+		}
+	case reflect.Struct:
+		var found []string
+		for i := 0; i < v.NumField(); i++ {
+			found = append(found, nonFinite(v.Field(i), path+"."+v.Type().Field(i).Name)...)
+		}
+		return found
+	case reflect.Slice, reflect.Array:
+		var found []string
+		for i := 0; i < v.Len(); i++ {
+			found = append(found, nonFinite(v.Index(i), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return found
+	case reflect.Map:
+		var found []string
+		for _, key := range v.MapKeys() {
+			found = append(found, nonFinite(v.MapIndex(key), fmt.Sprintf("%s[%v]", path, key))...)
+		}
+		return found
+	}
+	return nil
+}
+
+func TestGetSpendingAnalyticsNoTransactionsIsFinite(t *testing.T) {
+	svc := NewService(&fakeRepo{}, fixedClock("2025-04-15"))
+	for _, timeRange := range []string{"1 month", "2 weeks", "1 year"} {
+		got, err := svc.GetSpendingAnalytics(context.Background(), "1234567891", timeRange, AnalyticsOptions{IncludeIncome: true, IncludeHistory: true})
+		if err != nil {
+			t.Fatalf("GetSpendingAnalytics(%q) failed: %v", timeRange, err)
+		}
+		if found := nonFinite(reflect.ValueOf(got), "response"); len(found) > 0 {
+			t.Errorf("GetSpendingAnalytics(%q) has NaN or Inf at %v", timeRange, found)
+		}
+		if got.Data.TotalSpent != 0 || got.Data.MonthlyAverage != 0 {
+			t.Errorf("TotalSpent, MonthlyAverage = %v, %v, want zeros", got.Data.TotalSpent, got.Data.MonthlyAverage)
+		}
+		if _, err := json.Marshal(got); err != nil {
+			t.Errorf("json.Marshal() failed: %v", err)
+		}
+	}
+}
+
+func TestGetCategoryTotals(t *testing.T) {
+	want := map[string]float64{
+		"Food":          123.456,
+		"Rent":          1500.005,
+		"Gas":           40.1234,
+		"Shopping":      89.999,
+		"Utilities":     60.5,
+		"Entertainment": 15.333,
+		"Travel":        420.0001,
+	}
+	var transactions []types.Transaction
+	for category, amount := range want {
+		transactions = append(transactions, txn("2025-04-05", -amount, category, category+" Store"))
+	}
+	svc := NewService(&fakeRepo{transactions: transactions}, fixedClock("2025-04-15"))
+
+	got, err := svc.GetCategoryTotals(context.Background(), "1234567891", "1 month")
+	if err != nil {
+		t.Fatalf("GetCategoryTotals() failed: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("GetCategoryTotals() = %d categories, want all %d: %v", len(got), len(want), got)
+	}
+	for category, amount := range want {
+		if math.Abs(got[category]-amount) > 1e-9 {
+			t.Errorf("GetCategoryTotals()[%q] = %v, want the unrounded %v", category, got[category], amount)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// solverMonths is how many complete months of spend the solver averages
+const solverMonths = 3
+
+// ErrTargetUnreachable is returned when a target can't be met without
+// cutting essential spending
+var ErrTargetUnreachable = errors.New("target unreachable without cutting essentials")
+
+// SolveForTarget suggests how much to cut from each category, per month, to
+// bring average monthly spend down to targetMonthlyTotal. The account's
+// essential categories stay fixed and the cut is shared across discretionary
+// categories in proportion to what they cost now. Categories missing from the
+// result need no cut, and an empty result means the target is already met.
+func (s *service) SolveForTarget(ctx context.Context, accountID string, targetMonthlyTotal float64) (map[string]float64, error) {
+	if targetMonthlyTotal < 0 {
+		return nil, fmt.Errorf("target must not be negative, got %.2f", targetMonthlyTotal)
+	}
+
+	config, err := s.accountConfig(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	essential := make(map[string]bool, len(config.EssentialCategories))
+	for _, c := range config.EssentialCategories {
+		essential[strings.ToLower(c)] = true
+	}
+
+	transactions, err := s.loadTransactions(ctx, accountID, fmt.Sprintf("%d months", solverMonths+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	var total, discretionary float64
+	averages := make(map[string]float64)
+	for category, series := range monthlySpendSeries(transactions, s.now(), solverMonths) {
+		avg := mean(series)
+		total += avg
+		if !essential[strings.ToLower(category)] {
+			averages[category] = avg
+			discretionary += avg
+		}
+	}
+
+	cuts := make(map[string]float64)
+	needed := total - targetMonthlyTotal
+	if needed <= 0 {
+		return cuts, nil
+	}
+	if needed > discretionary {
+		return nil, fmt.Errorf("reaching %.2f needs %.2f of cuts but only %.2f is discretionary: %w",
+			targetMonthlyTotal, needed, discretionary, ErrTargetUnreachable)
+	}
+
+	for category, avg := range averages {
+		if avg > 0 {
+			cuts[category] = avg * needed / discretionary
+		}
+	}
+	return cuts, nil
+}
@@ -0,0 +1,51 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"server/types"
+	"testing"
+)
+
+func TestSolveForTarget(t *testing.T) {
+	var transactions []types.Transaction
+	for _, month := range []string{"2025-01", "2025-02", "2025-03"} {
+		transactions = append(transactions,
+			txn(month+"-01", -2000, "Rent", "Park Avenue Apartments"),
+			txn(month+"-05", -500, "Groceries", "Whole Foods"),
+			txn(month+"-12", -300, "Dining", "Chipotle"),
+			txn(month+"-18", -200, "Shopping", "Amazon"),
+		)
+	}
+	store := NewMemoryConfigStore()
+	if err := store.SaveConfig(context.Background(), types.AccountConfig{
+		AccountID:           "1234567891",
+		EssentialCategories: []string{"rent", "Groceries"},
+	}); err != nil {
+		t.Fatalf("SaveConfig() failed: %v", err)
+	}
+	svc := NewService(&fakeRepo{transactions: transactions}, fixedClock("2025-04-15"), WithConfigStore(store))
+
+	// Spending 3000 a month, 500 of it discretionary; cutting 250 halves it
+	got, err := svc.SolveForTarget(context.Background(), "1234567891", 2750)
+	if err != nil {
+		t.Fatalf("SolveForTarget() failed: %v", err)
+	}
+	want := map[string]float64{"Dining": 150, "Shopping": 100}
+	if len(got) != len(want) {
+		t.Fatalf("SolveForTarget() = %v, want %v", got, want)
+	}
+	for category, cut := range want {
+		if !approxEqual(got[category], cut) {
+			t.Errorf("cut for %s = %v, want %v", category, got[category], cut)
+		}
+	}
+
+	if got, err := svc.SolveForTarget(context.Background(), "1234567891", 3200); err != nil || len(got) != 0 {
+		t.Errorf("SolveForTarget() above current spend = %v, %v, want no cuts", got, err)
+	}
+
+	if _, err := svc.SolveForTarget(context.Background(), "1234567891", 2400); !errors.Is(err, ErrTargetUnreachable) {
+		t.Errorf("SolveForTarget() below essentials error = %v, want ErrTargetUnreachable", err)
+	}
+}
@@ -0,0 +1,141 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"sort"
+	"time"
+)
+
+// SpikeOptions tunes end-of-period spike detection. The zero value looks at
+// the last 3 days of each calendar month and flags spend 1.5x the average.
+type SpikeOptions struct {
+	// Period is what spend is paced against: calendar months, or the pay
+	// periods between income deposits
+	Period types.PeriodKind
+	// WindowDays is how many days at the end of each period count as its end
+	WindowDays int
+	// Threshold is how many times the average daily spend the end of a period
+	// must reach to count as a spike
+	Threshold float64
+}
+
+func (o SpikeOptions) withDefaults() SpikeOptions {
+	if o.Period == "" {
+		o.Period = types.PeriodMonth
+	}
+	if o.WindowDays <= 0 {
+		o.WindowDays = 3
+	}
+	if o.Threshold <= 0 {
+		o.Threshold = 1.5
+	}
+	return o
+}
+
+// period is a half-open [start, end) span of days spend is paced over
+type period struct {
+	start, end time.Time
+}
+
+// DetectEndOfPeriodSpikes looks for spend bunching up at the end of each
+// month or pay period, a sign of "use it or lose it" spending or poor pacing.
+// The spike is reported as the end-of-period daily spend relative to the
+// average daily spend, and is flagged when most periods show it.
+func (s *service) DetectEndOfPeriodSpikes(ctx context.Context, accountID string, months int, opts SpikeOptions) (*types.PeriodSpike, error) {
+	if months < 2 {
+		return nil, fmt.Errorf("at least 2 months are needed to detect spending spikes, got %d", months)
+	}
+	opts = opts.withDefaults()
+
+	transactions, err := s.loadTransactions(ctx, accountID, fmt.Sprintf("%d months", months+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	end := monthStart(s.now())
+	start := end.AddDate(0, -months, 0)
+	var periods []period
+	switch opts.Period {
+	case types.PeriodMonth:
+		for m := start; m.Before(end); m = m.AddDate(0, 1, 0) {
+			periods = append(periods, period{start: m, end: m.AddDate(0, 1, 0)})
+		}
+	case types.PeriodPayPeriod:
+		periods = s.payPeriods(transactions, start, end)
+	default:
+		return nil, fmt.Errorf("unknown period %q", opts.Period)
+	}
+	if len(periods) == 0 {
+		return nil, fmt.Errorf("no complete %s periods to compare: %w", opts.Period, ErrInsufficientHistory)
+	}
+
+	result := &types.PeriodSpike{
+		Period:     opts.Period,
+		WindowDays: opts.WindowDays,
+		Periods:    len(periods),
+	}
+	var totalSpend, totalDays, windowSpend, windowDays float64
+	for _, p := range periods {
+		windowStart := p.end.AddDate(0, 0, -opts.WindowDays)
+		if windowStart.Before(p.start) {
+			windowStart = p.start
+		}
+
+		var spend, endSpend float64
+		for _, t := range transactions {
+			amount := expenseAmount(t)
+			if amount == 0 || t.Date.Before(p.start) || !t.Date.Before(p.end) {
+				continue
+			}
+			spend += amount
+			if !t.Date.Before(windowStart) {
+				endSpend += amount
+			}
+		}
+
+		days := p.end.Sub(p.start).Hours() / 24
+		endDays := p.end.Sub(windowStart).Hours() / 24
+		totalSpend += spend
+		totalDays += days
+		windowSpend += endSpend
+		windowDays += endDays
+		if spend > 0 && (endSpend/endDays)/(spend/days) >= opts.Threshold {
+			result.SpikingPeriods++
+		}
+	}
+
+	result.OverallDaily = totalSpend / totalDays
+	result.EndOfPeriodDaily = windowSpend / windowDays
+	if result.OverallDaily > 0 {
+		result.Magnitude = result.EndOfPeriodDaily / result.OverallDaily
+	}
+	result.Detected = result.Magnitude >= opts.Threshold && result.SpikingPeriods*2 > result.Periods
+
+	return result, nil
+}
+
+// payPeriods splits [start, end) at each income deposit, keeping only the
+// periods that run from one payday to the next
+func (s *service) payPeriods(transactions []types.Transaction, start, end time.Time) []period {
+	var paydays []time.Time
+	for _, t := range transactions {
+		if t.Amount > 0 && s.isIncome(t) && !t.Date.Before(start) && t.Date.Before(end) {
+			y, m, d := t.Date.Date()
+			paydays = append(paydays, time.Date(y, m, d, 0, 0, 0, 0, t.Date.Location()))
+		}
+	}
+	sort.Slice(paydays, func(i, j int) bool {
+		return paydays[i].Before(paydays[j])
+	})
+
+	var periods []period
+	for i := 1; i < len(paydays); i++ {
+		if paydays[i].Equal(paydays[i-1]) {
+			continue // split deposits on the same day
+		}
+		periods = append(periods, period{start: paydays[i-1], end: paydays[i]})
+	}
+	return periods
+}
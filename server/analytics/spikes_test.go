@@ -0,0 +1,74 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"testing"
+)
+
+func TestDetectEndOfPeriodSpikes(t *testing.T) {
+	var steady, spiky []types.Transaction
+	for _, month := range []string{"2025-01", "2025-02", "2025-03"} {
+		for day := 1; day <= 28; day++ {
+			date := fmt.Sprintf("%s-%02d", month, day)
+			steady = append(steady, txn(date, -20, "Food", "Whole Foods"))
+			spiky = append(spiky, txn(date, -20, "Food", "Whole Foods"))
+		}
+		// The last few days of each month, however long it is
+		last := map[string][]int{"2025-01": {29, 30, 31}, "2025-02": {26, 27, 28}, "2025-03": {29, 30, 31}}[month]
+		for _, day := range last {
+			spiky = append(spiky, txn(fmt.Sprintf("%s-%02d", month, day), -150, "Shopping", "Amazon"))
+		}
+	}
+
+	tests := []struct {
+		name         string
+		transactions []types.Transaction
+		wantDetected bool
+	}{
+		{name: "steady spend", transactions: steady, wantDetected: false},
+		{name: "month-end spikes", transactions: spiky, wantDetected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(&fakeRepo{transactions: tt.transactions}, fixedClock("2025-04-15"))
+			got, err := svc.DetectEndOfPeriodSpikes(context.Background(), "1234567891", 3, SpikeOptions{})
+			if err != nil {
+				t.Fatalf("DetectEndOfPeriodSpikes() failed: %v", err)
+			}
+			if got.Detected != tt.wantDetected {
+				t.Errorf("Detected = %v (%+v), want %v", got.Detected, got, tt.wantDetected)
+			}
+			if tt.wantDetected && (got.Magnitude < 2 || got.SpikingPeriods != 3) {
+				t.Errorf("Magnitude = %v over %d spiking periods, want a large spike in all 3", got.Magnitude, got.SpikingPeriods)
+			}
+		})
+	}
+}
+
+func TestDetectEndOfPeriodSpikesPayPeriod(t *testing.T) {
+	var transactions []types.Transaction
+	for _, payday := range []string{"2025-01-01", "2025-01-15", "2025-02-01", "2025-02-15", "2025-03-01"} {
+		transactions = append(transactions, txn(payday, 2000, "Income", "Employer"))
+	}
+	for _, date := range []string{"2025-01-03", "2025-01-07", "2025-01-18", "2025-01-22", "2025-02-04", "2025-02-08", "2025-02-18", "2025-02-22"} {
+		transactions = append(transactions, txn(date, -25, "Food", "Whole Foods"))
+	}
+	// Spend piles up in the two days before each payday
+	for _, date := range []string{"2025-01-13", "2025-01-30", "2025-02-13", "2025-02-27"} {
+		transactions = append(transactions, txn(date, -200, "Dining", "Steakhouse"))
+	}
+
+	svc := NewService(&fakeRepo{transactions: transactions}, fixedClock("2025-04-15"))
+	got, err := svc.DetectEndOfPeriodSpikes(context.Background(), "1234567891", 3, SpikeOptions{Period: types.PeriodPayPeriod, WindowDays: 2})
+	if err != nil {
+		t.Fatalf("DetectEndOfPeriodSpikes() failed: %v", err)
+	}
+	if got.Periods != 4 {
+		t.Errorf("Periods = %d, want 4 pay periods", got.Periods)
+	}
+	if !got.Detected {
+		t.Errorf("Detected = false (%+v), want a pre-payday spike", got)
+	}
+}
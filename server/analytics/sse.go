@@ -0,0 +1,164 @@
+package analytics
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"server/types"
+	"sync"
+)
+
+// sseTimeRange is the window summarized in each streamed update
+const sseTimeRange = "1 month"
+
+type sseUpdate struct {
+	AccountID string                   `json:"accountId"`
+	Analytics *types.AnalyticsResponse `json:"analytics"`
+}
+
+// sseHandler streams recomputed analytics to dashboards over Server-Sent
+// Events. A single broker goroutine reads account IDs from the notification
+// channel and wakes the clients watching those accounts.
+type sseHandler struct {
+	service Service
+
+	mu          sync.Mutex
+	subscribers map[chan struct{}]string
+	closed      bool
+}
+
+// NewAnalyticsSSEHandler streams analytics for the account given by the
+// accountId query parameter. Clients get the current summary on connect and a
+// recomputed one each time the account's ID arrives on events. Closing events
+// ends every stream.
+func NewAnalyticsSSEHandler(svc Service, events <-chan string) http.Handler {
+	if svc == nil {
+		panic("service is required")
+	}
+	h := &sseHandler{
+		service:     svc,
+		subscribers: make(map[chan struct{}]string),
+	}
+	go h.broker(events)
+	return h
+}
+
+func (h *sseHandler) broker(events <-chan string) {
+	for accountID := range events {
+		h.mu.Lock()
+		for ch, subscribed := range h.subscribers {
+			if subscribed != accountID {
+				continue
+			}
+			// A pending wake-up already covers this update
+			select {
+			case ch <- struct{}{}:
+			default:
+			}
+		}
+		h.mu.Unlock()
+	}
+
+	h.mu.Lock()
+	h.closed = true
+	for ch := range h.subscribers {
+		close(ch)
+		delete(h.subscribers, ch)
+	}
+	h.mu.Unlock()
+}
+
+func (h *sseHandler) subscribe(accountID string) (chan struct{}, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, false
+	}
+	ch := make(chan struct{}, 1)
+	h.subscribers[ch] = accountID
+	return ch, true
+}
+
+func (h *sseHandler) unsubscribe(ch chan struct{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	// The broker may already have closed and removed it
+	if _, ok := h.subscribers[ch]; ok {
+		delete(h.subscribers, ch)
+		close(ch)
+	}
+}
+
+func (h *sseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	accountID := r.URL.Query().Get("accountId")
+	if accountID == "" {
+		http.Error(w, "Account ID is required", http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	updates, ok := h.subscribe(accountID)
+	if !ok {
+		http.Error(w, "Analytics stream closed", http.StatusServiceUnavailable)
+		return
+	}
+	defer h.unsubscribe(updates)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ctx := r.Context()
+	for {
+		if err := h.writeUpdate(w, r, accountID); err != nil {
+			return
+		}
+		flusher.Flush()
+
+		select {
+		case <-ctx.Done():
+			return
+		case _, open := <-updates:
+			if !open {
+				return
+			}
+		}
+	}
+}
+
+// writeUpdate recomputes the account's analytics and writes them as one SSE
+// event. Analytics failures are reported to the client as error events; only
+// write failures, meaning the client has gone, are returned.
+func (h *sseHandler) writeUpdate(w http.ResponseWriter, r *http.Request, accountID string) error {
+	analytics, err := h.service.GetSpendingAnalytics(r.Context(), accountID, sseTimeRange, AnalyticsOptions{TopN: DefaultTopN})
+	if err != nil {
+		if r.Context().Err() != nil {
+			return r.Context().Err()
+		}
+		_, err = fmt.Fprintf(w, "event: error\ndata: %s\n\n", jsonString(err.Error()))
+		return err
+	}
+
+	data, err := json.Marshal(sseUpdate{AccountID: accountID, Analytics: analytics})
+	if err != nil {
+		_, err = fmt.Fprintf(w, "event: error\ndata: %s\n\n", jsonString(err.Error()))
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: analytics\ndata: %s\n\n", data)
+	return err
+}
+
+func jsonString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
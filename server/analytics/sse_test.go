@@ -0,0 +1,80 @@
+package analytics
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"server/types"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnalyticsSSEHandler(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-03-12", -84.20, "Food", "Whole Foods"),
+	}}
+	events := make(chan string)
+	defer close(events)
+	srv := httptest.NewServer(NewAnalyticsSSEHandler(NewService(repo), events))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"?accountId=1234567891", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() failed: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	readEvent := func() sseUpdate {
+		t.Helper()
+		var name, data string
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("reading event failed: %v", err)
+			}
+			line = strings.TrimRight(line, "\n")
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				data = strings.TrimPrefix(line, "data: ")
+			case line == "" && data != "":
+				if name != "analytics" {
+					t.Fatalf("got %q event %s, want analytics", name, data)
+				}
+				var update sseUpdate
+				if err := json.Unmarshal([]byte(data), &update); err != nil {
+					t.Fatalf("decoding event failed: %v", err)
+				}
+				return update
+			}
+		}
+	}
+
+	// Snapshot on connect
+	if got := readEvent(); got.AccountID != "1234567891" || got.Analytics == nil {
+		t.Errorf("first event = %+v, want analytics for 1234567891", got)
+	}
+
+	// Update after new transactions arrive
+	repo.transactions = append(repo.transactions, txn("2025-03-14", -40, "Food", "Chipotle"))
+	events <- "1234567891"
+	got := readEvent()
+	if !approxEqual(got.Analytics.Data.TotalSpent, 124.20) {
+		t.Errorf("updated TotalSpent = %v, want 124.20", got.Analytics.Data.TotalSpent)
+	}
+}
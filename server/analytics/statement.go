@@ -0,0 +1,147 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"sort"
+	"time"
+)
+
+// BuildStatement assembles a month's spending into a statement a renderer can
+// lay out directly: a header, a category table that sums to the total, a few
+// notable insights and the recurring charges billed that month
+func (s *service) BuildStatement(ctx context.Context, accountID string, month time.Month, year int) (*types.Statement, error) {
+	if month < time.January || month > time.December {
+		return nil, fmt.Errorf("invalid month %d", month)
+	}
+	config, err := s.accountConfig(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := s.configLocation(config)
+	if err != nil {
+		return nil, err
+	}
+	currency := s.configCurrency(config)
+	start := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	end := start.AddDate(0, 1, 0)
+	priorStart := start.AddDate(0, -1, 0)
+
+	// Recurring detection needs history well before the statement month
+	transactions, err := s.loadTransactions(ctx, accountID, "2 years")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	statement := &types.Statement{
+		Header: types.StatementHeader{
+			AccountID:   accountID,
+			Period:      start.Format("2006-01"),
+			PeriodStart: start,
+			PeriodEnd:   end.Add(-time.Nanosecond),
+			Currency:    currency,
+			GeneratedAt: s.now(),
+		},
+		Categories:       make([]types.StatementRow, 0),
+		Insights:         make([]string, 0),
+		RecurringCharges: make([]types.RecurringCharge, 0),
+	}
+
+	var history []types.Transaction
+	rows := make(map[string]*types.StatementRow)
+	var priorSpent float64
+	for _, t := range transactions {
+		if t.Date.Before(end) {
+			history = append(history, t)
+		}
+		amount := expenseAmount(t)
+		switch {
+		case !t.Date.Before(start) && t.Date.Before(end):
+			if amount == 0 {
+				statement.TotalIncome += t.Amount
+				continue
+			}
+			row, ok := rows[t.Category]
+			if !ok {
+				row = &types.StatementRow{Category: t.Category}
+				rows[t.Category] = row
+			}
+			row.Amount += amount
+			row.Count++
+			statement.TotalSpent += amount
+		case !t.Date.Before(priorStart) && t.Date.Before(start):
+			priorSpent += amount
+		}
+	}
+
+	for _, row := range rows {
+		if statement.TotalSpent > 0 {
+			row.Percentage = row.Amount / statement.TotalSpent * 100
+		}
+		statement.Categories = append(statement.Categories, *row)
+	}
+	sort.Slice(statement.Categories, func(i, j int) bool {
+		if statement.Categories[i].Amount == statement.Categories[j].Amount {
+			return statement.Categories[i].Category < statement.Categories[j].Category
+		}
+		return statement.Categories[i].Amount > statement.Categories[j].Amount
+	})
+
+	// Only charges actually billed during the month belong on its statement
+	for _, g := range s.detectRecurring(history) {
+		for _, t := range g.transactions {
+			if !t.Date.Before(start) {
+				statement.RecurringCharges = append(statement.RecurringCharges, g.charge)
+				break
+			}
+		}
+	}
+
+	statement.Insights = s.statementInsights(statement, priorSpent, history)
+	return statement, nil
+}
+
+// statementInsights picks out the handful of facts worth calling out at the
+// top of a statement
+func (s *service) statementInsights(statement *types.Statement, priorSpent float64, history []types.Transaction) []string {
+	insights := make([]string, 0)
+	if len(statement.Categories) == 0 {
+		return insights
+	}
+
+	top := statement.Categories[0]
+	insights = append(insights, fmt.Sprintf("%s was the largest category at %.0f%% of spending",
+		top.Category, top.Percentage))
+
+	if priorSpent > 0 {
+		change := percentChange(statement.TotalSpent, priorSpent)
+		direction := "up"
+		if change < 0 {
+			direction = "down"
+		}
+		insights = append(insights, fmt.Sprintf("Spending was %s %.0f%% on the previous month",
+			direction, abs(change)))
+	}
+
+	if len(statement.RecurringCharges) > 0 {
+		var total float64
+		for _, c := range statement.RecurringCharges {
+			total += c.AverageAmount
+		}
+		insights = append(insights, fmt.Sprintf("%d recurring charges totalled %s %s",
+			len(statement.RecurringCharges), FormatAmount(total, statement.Header.Currency), statement.Header.Currency))
+	}
+
+	var fees float64
+	for _, t := range history {
+		if !t.Date.Before(statement.Header.PeriodStart) && s.isFee(t) {
+			fees += expenseAmount(t)
+		}
+	}
+	if fees > 0 {
+		insights = append(insights, fmt.Sprintf("Paid %s %s in fees", FormatAmount(fees, statement.Header.Currency), statement.Header.Currency))
+	}
+
+	return insights
+}
@@ -0,0 +1,58 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+	"time"
+)
+
+func TestBuildStatement(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-01-05", -15.99, "Entertainment", "Netflix"),
+		txn("2025-02-05", -15.99, "Entertainment", "Netflix"),
+		txn("2025-02-10", -200, "Shopping", "Amazon"),
+		txn("2025-03-05", -15.99, "Entertainment", "Netflix"),
+		txn("2025-03-01", -2260, "Rent", "Park Avenue Apartments"),
+		txn("2025-03-12", -84.20, "Food", "Whole Foods"),
+		txn("2025-03-20", -45.30, "Food", "Chipotle"),
+		txn("2025-03-22", -3, "Fees", "ATM Fee"),
+		txn("2025-03-15", 3500, "Income", "Employer"),
+		txn("2025-04-02", -60, "Food", "Whole Foods"),
+	}}
+	svc := NewService(repo, fixedClock("2025-04-15"))
+
+	got, err := svc.BuildStatement(context.Background(), "1234567891", time.March, 2025)
+	if err != nil {
+		t.Fatalf("BuildStatement() failed: %v", err)
+	}
+
+	if got.Header.Period != "2025-03" {
+		t.Errorf("Header.Period = %q, want %q", got.Header.Period, "2025-03")
+	}
+
+	var sum float64
+	for _, row := range got.Categories {
+		sum += row.Amount
+	}
+	want := 2260 + 84.20 + 45.30 + 3 + 15.99
+	if !approxEqual(got.TotalSpent, want) {
+		t.Errorf("TotalSpent = %v, want %v", got.TotalSpent, want)
+	}
+	if !approxEqual(sum, got.TotalSpent) {
+		t.Errorf("category rows sum to %v, want TotalSpent %v", sum, got.TotalSpent)
+	}
+	if len(got.Categories) != 4 || got.Categories[0].Category != "Rent" {
+		t.Errorf("Categories = %+v, want 4 rows led by Rent", got.Categories)
+	}
+	if got.TotalIncome != 3500 {
+		t.Errorf("TotalIncome = %v, want 3500", got.TotalIncome)
+	}
+
+	if len(got.RecurringCharges) != 1 || got.RecurringCharges[0].Merchant != "Netflix" {
+		t.Errorf("RecurringCharges = %+v, want Netflix", got.RecurringCharges)
+	}
+	if len(got.Insights) == 0 {
+		t.Error("Insights is empty, want at least the largest category")
+	}
+}
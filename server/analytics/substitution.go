@@ -0,0 +1,78 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"sort"
+)
+
+const (
+	// substitutionCorrelation is how strongly two categories must move in
+	// opposite directions to be reported as a substitution
+	substitutionCorrelation = -0.7
+	// minRelativeSlope ignores categories whose monthly trend is less than 5%
+	// of their average monthly spend
+	minRelativeSlope = 0.05
+)
+
+// DetectCategorySubstitutions finds pairs of categories where spend in one
+// has been rising while the other fell over the last few months, e.g. eating
+// out less and buying more groceries
+func (s *service) DetectCategorySubstitutions(ctx context.Context, accountID string, months int) ([]types.CategorySubstitution, error) {
+	if months < 3 {
+		return nil, fmt.Errorf("at least 3 months are needed to detect substitutions, got %d", months)
+	}
+
+	transactions, err := s.loadTransactions(ctx, accountID, fmt.Sprintf("%d months", months+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	series := monthlySpendSeries(transactions, s.now(), months)
+
+	type trend struct {
+		category string
+		values   []float64
+		slope    float64
+	}
+	var rising, falling []trend
+	for category, values := range series {
+		slope, _ := linearFit(values)
+		avg := mean(values)
+		if avg == 0 || abs(slope)/avg < minRelativeSlope {
+			continue
+		}
+		if slope > 0 {
+			rising = append(rising, trend{category, values, slope})
+		} else {
+			falling = append(falling, trend{category, values, slope})
+		}
+	}
+
+	substitutions := make([]types.CategorySubstitution, 0)
+	for _, r := range rising {
+		for _, f := range falling {
+			correlation := pearson(r.values, f.values)
+			if correlation > substitutionCorrelation {
+				continue
+			}
+			substitutions = append(substitutions, types.CategorySubstitution{
+				Rising:       r.category,
+				Falling:      f.category,
+				Correlation:  correlation,
+				RisingSlope:  r.slope,
+				FallingSlope: f.slope,
+			})
+		}
+	}
+
+	sort.Slice(substitutions, func(i, j int) bool {
+		if substitutions[i].Correlation == substitutions[j].Correlation {
+			return substitutions[i].Rising+substitutions[i].Falling < substitutions[j].Rising+substitutions[j].Falling
+		}
+		return substitutions[i].Correlation < substitutions[j].Correlation
+	})
+
+	return substitutions, nil
+}
@@ -0,0 +1,45 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"server/types"
+	"testing"
+)
+
+func TestDetectCategorySubstitutions(t *testing.T) {
+	var transactions []types.Transaction
+	restaurants := []float64{400, 350, 300, 220, 150, 100}
+	groceries := []float64{200, 240, 300, 360, 410, 450}
+	for i := 0; i < 6; i++ {
+		month := fmt.Sprintf("2024-%02d", i+7)
+		transactions = append(transactions,
+			txn(month+"-10", -restaurants[i], "Restaurants", "Le Bernardin"),
+			txn(month+"-12", -groceries[i], "Groceries", "Whole Foods"),
+			txn(month+"-01", -2260, "Rent", "Park Avenue Apartments"),
+		)
+	}
+	svc := NewService(&fakeRepo{transactions: transactions}, fixedClock("2025-01-15"))
+
+	got, err := svc.DetectCategorySubstitutions(context.Background(), "1234567891", 6)
+	if err != nil {
+		t.Fatalf("DetectCategorySubstitutions() failed: %v", err)
+	}
+
+	if len(got) != 1 {
+		t.Fatalf("DetectCategorySubstitutions() = %+v, want one pair", got)
+	}
+	if got[0].Rising != "Groceries" || got[0].Falling != "Restaurants" {
+		t.Errorf("pair = %s up / %s down, want Groceries up / Restaurants down", got[0].Rising, got[0].Falling)
+	}
+	if got[0].Correlation > -0.9 {
+		t.Errorf("Correlation = %v, want strongly negative", got[0].Correlation)
+	}
+}
+
+func TestLinearFit(t *testing.T) {
+	slope, intercept := linearFit([]float64{1, 3, 5, 7})
+	if !approxEqual(slope, 2) || !approxEqual(intercept, 1) {
+		t.Errorf("linearFit() = %v, %v, want 2, 1", slope, intercept)
+	}
+}
@@ -9,7 +9,7 @@ import (
 // analyzeTimePatterns analyzes spending patterns by time of day and day of week
 func analyzeTimePatterns(transactions []types.Transaction) []types.TimePattern {
 	if len(transactions) == 0 {
-		return nil
+		return []types.TimePattern{}
 	}
 
 	// Use a more efficient data structure with a composite key
@@ -0,0 +1,104 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"server/types"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidTimeRange is returned for a time range that isn't of the form
+// "<n> days", "<n> weeks", "<n> months" or "<n> years"
+var ErrInvalidTimeRange = errors.New("invalid time range")
+
+// timeRangePattern is the whitelist a time range must match before it is
+// turned into dates
+var timeRangePattern = regexp.MustCompile(`^([1-9][0-9]{0,2}) (day|week|month|year)s?$`)
+
+// ParseTimeRange turns a relative time range such as "6 months" into the
+// dates it covers, ending at now. Anything else, including text that could
+// end up in a query, is rejected with ErrInvalidTimeRange.
+func ParseTimeRange(timeRange string, now time.Time) (types.DateRange, error) {
+	match := timeRangePattern.FindStringSubmatch(timeRange)
+	if match == nil {
+		return types.DateRange{}, fmt.Errorf("%w: %q", ErrInvalidTimeRange, timeRange)
+	}
+	n, _ := strconv.Atoi(match[1])
+
+	var start time.Time
+	switch match[2] {
+	case "day":
+		start = now.AddDate(0, 0, -n)
+	case "week":
+		start = now.AddDate(0, 0, -7*n)
+	case "month":
+		start = now.AddDate(0, -n, 0)
+	case "year":
+		start = now.AddDate(-n, 0, 0)
+	}
+	return types.DateRange{Start: start, End: now, Relative: fmt.Sprintf("%d %s", n, match[2])}, nil
+}
+
+// dateRange resolves a relative time range against the service clock
+func (s *service) dateRange(timeRange string) (types.DateRange, error) {
+	return ParseTimeRange(timeRange, s.now())
+}
+
+// loadTransactions reads the account's transactions over a relative time
+// range such as "1 year"
+func (s *service) loadTransactions(ctx context.Context, accountID string, timeRange string) ([]types.Transaction, error) {
+	window, err := s.dateRange(timeRange)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetTransactions(ctx, accountID, window)
+}
+
+// daysPerMonth is the average length of a month
+const daysPerMonth = 30.44
+
+// timeRangeToMonths is how many months a relative time range such as "6
+// months" or "2 weeks" spans. Ranges that don't parse count as 1 month.
+func timeRangeToMonths(timeRange string) float64 {
+	match := timeRangePattern.FindStringSubmatch(timeRange)
+	if match == nil {
+		return 1
+	}
+	n, _ := strconv.Atoi(match[1])
+	switch match[2] {
+	case "day":
+		return float64(n) / daysPerMonth
+	case "week":
+		return float64(7*n) / daysPerMonth
+	case "year":
+		return float64(12 * n)
+	default:
+		return float64(n)
+	}
+}
+
+// spanMonths is how many months, fractions included, a date range spans
+func spanMonths(window types.DateRange) float64 {
+	return window.End.Sub(window.Start).Hours() / 24 / daysPerMonth
+}
+
+// analyticsWindow resolves the dates analytics cover and how many months
+// they span: an explicit window when one is given, otherwise the relative
+// time range
+func (s *service) analyticsWindow(timeRange string, explicit types.DateRange) (types.DateRange, float64, error) {
+	if explicit.Start.IsZero() {
+		window, err := s.dateRange(timeRange)
+		if err != nil {
+			return types.DateRange{}, 0, err
+		}
+		return window, timeRangeToMonths(timeRange), nil
+	}
+	if !explicit.End.After(explicit.Start) {
+		return types.DateRange{}, 0, fmt.Errorf("%w: %s is not after %s", ErrInvalidTimeRange,
+			explicit.End.Format("2006-01-02"), explicit.Start.Format("2006-01-02"))
+	}
+	return explicit, spanMonths(explicit), nil
+}
@@ -0,0 +1,157 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"server/types"
+	"testing"
+	"time"
+)
+
+func TestParseTimeRange(t *testing.T) {
+	now := time.Date(2025, 4, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		timeRange string
+		wantStart time.Time
+	}{
+		{timeRange: "1 month", wantStart: now.AddDate(0, -1, 0)},
+		{timeRange: "6 months", wantStart: now.AddDate(0, -6, 0)},
+		{timeRange: "2 years", wantStart: now.AddDate(-2, 0, 0)},
+		{timeRange: "2 weeks", wantStart: now.AddDate(0, 0, -14)},
+		{timeRange: "30 days", wantStart: now.AddDate(0, 0, -30)},
+	}
+	for _, tt := range tests {
+		got, err := ParseTimeRange(tt.timeRange, now)
+		if err != nil {
+			t.Errorf("ParseTimeRange(%q) failed: %v", tt.timeRange, err)
+			continue
+		}
+		if !got.Start.Equal(tt.wantStart) || !got.End.Equal(now) {
+			t.Errorf("ParseTimeRange(%q) = %v to %v, want %v to %v", tt.timeRange, got.Start, got.End, tt.wantStart, now)
+		}
+	}
+
+	for _, bad := range []string{
+		"6 months'; DROP TABLE transactions; --",
+		"6 months' OR '1'='1",
+		"0 months",
+		"-1 month",
+		"month",
+		"1 fortnight",
+		"",
+	} {
+		if _, err := ParseTimeRange(bad, now); !errors.Is(err, ErrInvalidTimeRange) {
+			t.Errorf("ParseTimeRange(%q) error = %v, want ErrInvalidTimeRange", bad, err)
+		}
+	}
+}
+
+func TestMaliciousTimeRangeNeverReachesRepository(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewService(repo)
+
+	_, err := svc.GetSpendingAnalytics(context.Background(), "1234567891", "6 months'; DROP TABLE", AnalyticsOptions{})
+	if !errors.Is(err, ErrInvalidTimeRange) {
+		t.Errorf("GetSpendingAnalytics() error = %v, want ErrInvalidTimeRange", err)
+	}
+	if _, err := svc.GetNetSpend(context.Background(), "1234567891", "6 months'; DROP TABLE"); !errors.Is(err, ErrInvalidTimeRange) {
+		t.Errorf("GetNetSpend() error = %v, want ErrInvalidTimeRange", err)
+	}
+	if len(repo.ranges) != 0 {
+		t.Errorf("repository was queried %d times, want none", len(repo.ranges))
+	}
+
+	mux := http.NewServeMux()
+	NewHandler(svc).RegisterRoutes(mux)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/analytics/1234567891?timeRange="+url.QueryEscape("6 months'; DROP TABLE"), nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("handler status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestGetSpendingAnalyticsCustomWindow(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-03-05", -900, "Rent", "Landlord"),
+		txn("2025-04-05", -600, "Rent", "Landlord"),
+	}}
+	svc := NewService(repo, fixedClock("2025-04-20"))
+
+	// 45 days is about a month and a half
+	window := types.DateRange{Start: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC)}
+	got, err := svc.GetSpendingAnalytics(context.Background(), "1234567891", "", AnalyticsOptions{Window: window})
+	if err != nil {
+		t.Fatalf("GetSpendingAnalytics() failed: %v", err)
+	}
+	if want := 1500 / (45 / daysPerMonth); !approxEqual(got.Data.MonthlyAverage, want) {
+		t.Errorf("MonthlyAverage = %v, want %v over ~1.48 months", got.Data.MonthlyAverage, want)
+	}
+	if got.TimeRange != "2025-03-01 to 2025-04-15" {
+		t.Errorf("TimeRange = %q, want the window's dates", got.TimeRange)
+	}
+	var requested bool
+	for _, r := range repo.ranges {
+		requested = requested || (r.Start.Equal(window.Start) && r.End.Equal(window.End))
+	}
+	if !requested {
+		t.Errorf("window not requested; ranges = %v, want %v", repo.ranges, window)
+	}
+
+	backwards := types.DateRange{Start: window.End, End: window.Start}
+	if _, err := svc.GetSpendingAnalytics(context.Background(), "1234567891", "", AnalyticsOptions{Window: backwards}); !errors.Is(err, ErrInvalidTimeRange) {
+		t.Errorf("GetSpendingAnalytics() with end before start error = %v, want ErrInvalidTimeRange", err)
+	}
+}
+
+func TestHandleAnalyticsWindowIncludesEndDate(t *testing.T) {
+	repo := &windowRepo{fakeRepo{transactions: []types.Transaction{
+		txn("2025-03-01", -900, "Rent", "Landlord"),
+		// Noon on the end date
+		txn("2025-04-15", -40, "Food", "Chipotle"),
+		txn("2025-04-16", -25, "Food", "Chipotle"),
+	}}}
+	svc := NewService(repo, fixedClock("2025-04-20"))
+
+	mux := http.NewServeMux()
+	NewHandler(svc).RegisterRoutes(mux)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/analytics/1234567891?start=2025-03-01&end=2025-04-15", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("handler status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	var got types.AnalyticsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !approxEqual(got.Data.TotalSpent, 940) {
+		t.Errorf("TotalSpent = %v, want 940 with the end date's spend", got.Data.TotalSpent)
+	}
+	if got.TimeRange != "2025-03-01 to 2025-04-15" {
+		t.Errorf("TimeRange = %q, want the window's dates", got.TimeRange)
+	}
+}
+
+func TestTimeRangeToMonths(t *testing.T) {
+	tests := []struct {
+		timeRange string
+		want      float64
+	}{
+		{timeRange: "1 month", want: 1},
+		{timeRange: "3 months", want: 3},
+		{timeRange: "6 months", want: 6},
+		{timeRange: "1 year", want: 12},
+		{timeRange: "9 months", want: 9},
+		{timeRange: "2 years", want: 24},
+		{timeRange: "2 weeks", want: 14 / daysPerMonth},
+		{timeRange: "bogus", want: 1},
+	}
+	for _, tt := range tests {
+		if got := timeRangeToMonths(tt.timeRange); !approxEqual(got, tt.want) {
+			t.Errorf("timeRangeToMonths(%q) = %v, want %v", tt.timeRange, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"server/types"
+	"time"
+)
+
+// ErrInvalidInterval is returned for a time-series interval other than
+// "day", "week" or "month"
+var ErrInvalidInterval = errors.New("invalid time-series interval")
+
+// intervalStart returns the start of the day, week or month containing t.
+// Weeks start on Monday.
+func intervalStart(t time.Time, interval string, loc *time.Location) (time.Time, error) {
+	day := dayOf(t, loc)
+	switch interval {
+	case "day":
+		return day, nil
+	case "week":
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7)), nil
+	case "month":
+		return monthStart(day), nil
+	default:
+		return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidInterval, interval)
+	}
+}
+
+// nextInterval returns the start of the interval after the one starting at t
+func nextInterval(t time.Time, interval string) time.Time {
+	switch interval {
+	case "week":
+		return t.AddDate(0, 0, 7)
+	case "month":
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// intervalLabel names the interval starting at t
+func intervalLabel(t time.Time, interval string) string {
+	if interval == "month" {
+		return t.Format("2006-01")
+	}
+	return t.Format("2006-01-02")
+}
+
+// GetSpendTimeSeries buckets the spend over a time range by day, week or
+// month in the account's timezone, oldest first. Every interval from the one
+// the range starts in to the one it ends in is present, with zero spend if
+// nothing was bought, so charts have no gaps.
+func (s *service) GetSpendTimeSeries(ctx context.Context, accountID string, timeRange string, interval string) ([]types.TimeBucket, error) {
+	window, err := s.dateRange(timeRange)
+	if err != nil {
+		return nil, err
+	}
+	config, err := s.accountConfig(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := s.configLocation(config)
+	if err != nil {
+		return nil, err
+	}
+	first, err := intervalStart(window.Start, interval, loc)
+	if err != nil {
+		return nil, err
+	}
+	last, _ := intervalStart(window.End, interval, loc)
+
+	transactions, err := s.repo.GetTransactions(ctx, accountID, window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	buckets := make([]types.TimeBucket, 0)
+	index := make(map[time.Time]int)
+	for start := first; !start.After(last); start = nextInterval(start, interval) {
+		index[start] = len(buckets)
+		buckets = append(buckets, types.TimeBucket{
+			Period: intervalLabel(start, interval),
+			Start:  start,
+		})
+	}
+
+	for i, t := range transactions {
+		if err := checkContext(ctx, i); err != nil {
+			return nil, err
+		}
+		amount := expenseAmount(t)
+		if amount == 0 || t.Date.Before(window.Start) || t.Date.After(window.End) {
+			continue
+		}
+		start, _ := intervalStart(t.Date, interval, loc)
+		b, ok := index[start]
+		if !ok {
+			continue
+		}
+		buckets[b].Total += amount
+		buckets[b].Count++
+	}
+
+	return buckets, nil
+}
@@ -0,0 +1,108 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"server/types"
+	"testing"
+)
+
+func TestGetSpendTimeSeriesDaily(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-04-08", -10, "Food", "Chipotle"),
+		txn("2025-04-09", -4, "Food", "Starbucks"),
+		txn("2025-04-09", -6, "Food", "Starbucks"),
+		// Nothing on the 10th
+		txn("2025-04-11", -20, "Gas", "Shell"),
+		txn("2025-04-12", 500, "Income", "Payroll"),
+		txn("2025-04-14", -8, "Food", "Chipotle"),
+		// Outside the range
+		txn("2025-03-01", -99, "Food", "Chipotle"),
+	}}
+	svc := NewService(repo, fixedClock("2025-04-14"))
+
+	got, err := svc.GetSpendTimeSeries(context.Background(), "1234567891", "6 days", "day")
+	if err != nil {
+		t.Fatalf("GetSpendTimeSeries() failed: %v", err)
+	}
+
+	want := []types.TimeBucket{
+		{Period: "2025-04-08", Total: 10, Count: 1},
+		{Period: "2025-04-09", Total: 10, Count: 2},
+		{Period: "2025-04-10"},
+		{Period: "2025-04-11", Total: 20, Count: 1},
+		{Period: "2025-04-12"},
+		{Period: "2025-04-13"},
+		{Period: "2025-04-14", Total: 8, Count: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("GetSpendTimeSeries() = %d buckets, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Period != w.Period || !approxEqual(got[i].Total, w.Total) || got[i].Count != w.Count {
+			t.Errorf("bucket %d = %+v, want %+v", i, got[i], w)
+		}
+	}
+}
+
+func TestGetSpendTimeSeriesMonthly(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-01-20", -100, "Rent", "Landlord"),
+		txn("2025-01-25", -40, "Food", "Whole Foods"),
+		// No spending in February
+		txn("2025-03-05", -60, "Food", "Whole Foods"),
+	}}
+	svc := NewService(repo, fixedClock("2025-03-20"))
+
+	got, err := svc.GetSpendTimeSeries(context.Background(), "1234567891", "2 months", "month")
+	if err != nil {
+		t.Fatalf("GetSpendTimeSeries() failed: %v", err)
+	}
+
+	want := []types.TimeBucket{
+		{Period: "2025-01", Total: 140, Count: 2},
+		{Period: "2025-02"},
+		{Period: "2025-03", Total: 60, Count: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("GetSpendTimeSeries() = %d buckets, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Period != w.Period || !approxEqual(got[i].Total, w.Total) || got[i].Count != w.Count {
+			t.Errorf("bucket %d = %+v, want %+v", i, got[i], w)
+		}
+	}
+}
+
+func TestGetSpendTimeSeriesWeekly(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		txn("2025-04-01", -10, "Food", "Chipotle"),
+		txn("2025-04-06", -5, "Food", "Chipotle"),
+		txn("2025-04-07", -7, "Food", "Chipotle"),
+	}}
+	svc := NewService(repo, fixedClock("2025-04-09"))
+
+	got, err := svc.GetSpendTimeSeries(context.Background(), "1234567891", "2 weeks", "week")
+	if err != nil {
+		t.Fatalf("GetSpendTimeSeries() failed: %v", err)
+	}
+
+	// Weeks start on Monday
+	want := []types.TimeBucket{
+		{Period: "2025-03-24"},
+		{Period: "2025-03-31", Total: 15, Count: 2},
+		{Period: "2025-04-07", Total: 7, Count: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("GetSpendTimeSeries() = %d buckets, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Period != w.Period || !approxEqual(got[i].Total, w.Total) || got[i].Count != w.Count {
+			t.Errorf("bucket %d = %+v, want %+v", i, got[i], w)
+		}
+	}
+
+	if _, err := svc.GetSpendTimeSeries(context.Background(), "1234567891", "2 weeks", "hour"); !errors.Is(err, ErrInvalidInterval) {
+		t.Errorf("GetSpendTimeSeries() error = %v, want ErrInvalidInterval", err)
+	}
+}
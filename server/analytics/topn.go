@@ -0,0 +1,57 @@
+package analytics
+
+import (
+	"container/heap"
+	"server/types"
+	"sort"
+)
+
+// rankBy returns the n categories that sort first under less, in order. less
+// compares categories by index and must be a strict total order, so the result
+// matches a full sort truncated to n. When n is smaller than the set, only n
+// categories are kept in a heap while scanning, rather than sorting them all.
+// n <= 0 keeps every category.
+func rankBy(categories []types.CategorySpend, n int, less func(i, j int) bool) []types.CategorySpend {
+	var order []int
+	if n <= 0 || n >= len(categories) {
+		order = make([]int, len(categories))
+		for i := range order {
+			order[i] = i
+		}
+	} else {
+		// The heap is ordered worst first, so its root is the one to evict
+		h := &rankHeap{less: less, indices: make([]int, 0, n)}
+		for i := range categories {
+			if h.Len() < n {
+				heap.Push(h, i)
+			} else if less(i, h.indices[0]) {
+				h.indices[0] = i
+				heap.Fix(h, 0)
+			}
+		}
+		order = h.indices
+	}
+	sort.Slice(order, func(a, b int) bool { return less(order[a], order[b]) })
+
+	ranked := make([]types.CategorySpend, len(order))
+	for i, idx := range order {
+		ranked[i] = categories[idx]
+	}
+	return ranked
+}
+
+// rankHeap is a heap of category indices with the lowest-ranked at the root
+type rankHeap struct {
+	less    func(i, j int) bool
+	indices []int
+}
+
+func (h *rankHeap) Len() int           { return len(h.indices) }
+func (h *rankHeap) Less(a, b int) bool { return h.less(h.indices[b], h.indices[a]) }
+func (h *rankHeap) Swap(a, b int)      { h.indices[a], h.indices[b] = h.indices[b], h.indices[a] }
+func (h *rankHeap) Push(x any)         { h.indices = append(h.indices, x.(int)) }
+func (h *rankHeap) Pop() any {
+	last := h.indices[len(h.indices)-1]
+	h.indices = h.indices[:len(h.indices)-1]
+	return last
+}
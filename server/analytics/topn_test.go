@@ -0,0 +1,63 @@
+package analytics
+
+import (
+	"fmt"
+	"math/rand"
+	"server/types"
+	"sort"
+	"testing"
+)
+
+// manyCategories builds n categories with random totals, including ties
+func manyCategories(n int) []types.CategorySpend {
+	rng := rand.New(rand.NewSource(1))
+	categories := make([]types.CategorySpend, n)
+	for i := range categories {
+		amount := float64(rng.Intn(200)) * 2.5
+		categories[i] = types.CategorySpend{
+			Category:   fmt.Sprintf("Category %03d", i),
+			TotalSpent: fmt.Sprintf("%.2f", amount),
+			Amount:     amount,
+		}
+	}
+	return categories
+}
+
+// fullSortRank is the straightforward rank stage: sort everything, then truncate
+func fullSortRank(categories []types.CategorySpend, n int) []types.CategorySpend {
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i].Amount == categories[j].Amount {
+			return categories[i].Category < categories[j].Category
+		}
+		return categories[i].Amount > categories[j].Amount
+	})
+	if n > 0 && len(categories) > n {
+		categories = categories[:n]
+	}
+	return categories
+}
+
+func TestRankCategoriesMatchesFullSort(t *testing.T) {
+	categories := manyCategories(500)
+	for _, n := range []int{0, 1, 5, 50, 499, 500, 600} {
+		got := RankCategories(append([]types.CategorySpend(nil), categories...), n)
+		want := fullSortRank(append([]types.CategorySpend(nil), categories...), n)
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("RankCategories(n=%d) differs from a full sort", n)
+		}
+	}
+}
+
+func BenchmarkRankCategories(b *testing.B) {
+	categories := manyCategories(500)
+	b.Run("heap top 5", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			RankCategories(append([]types.CategorySpend(nil), categories...), 5)
+		}
+	})
+	b.Run("full sort", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			fullSortRank(append([]types.CategorySpend(nil), categories...), 5)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package analytics
+
+import (
+	"math"
+	"server/types"
+	"time"
+)
+
+// monthlySpendSeries buckets expenses per category into the given number of
+// calendar months ending with the month before end. Index 0 is the oldest month.
+func monthlySpendSeries(transactions []types.Transaction, end time.Time, months int) map[string][]float64 {
+	last := monthStart(end)
+	first := last.AddDate(0, -months, 0)
+
+	series := make(map[string][]float64)
+	for _, t := range transactions {
+		amount := expenseAmount(t)
+		if amount == 0 {
+			continue
+		}
+		date := t.Date.In(end.Location())
+		if date.Before(first) || !date.Before(last) {
+			continue
+		}
+
+		idx := (date.Year()-first.Year())*12 + int(date.Month()) - int(first.Month())
+		if _, exists := series[t.Category]; !exists {
+			series[t.Category] = make([]float64, months)
+		}
+		series[t.Category][idx] += amount
+	}
+	return series
+}
+
+// predictionTrendMonths is how many whole months of spend the prediction
+// trend is fitted over
+const predictionTrendMonths = 6
+
+// minTrendFit is the r² a trend line needs before predictions act on it, so a
+// category charged every other month isn't mistaken for one winding down
+const minTrendFit = 0.5
+
+// spendTrend fits a line through the monthly spend of one category's
+// transactions over the whole months before now. The series starts at the
+// first month with any spend, so a category that only began recently doesn't
+// look like it's rising, and empty months at the end only count once they lie
+// wholly after the predicted date, so a charge that is simply due later than
+// usual doesn't look like it's winding down. x is the month index, oldest
+// first; fit is the line's r².
+func spendTrend(txns []types.Transaction, now, predicted time.Time) (series []float64, slope, intercept, fit float64) {
+	series = monthlySpendSeries(txns, now, predictionTrendMonths)[txns[0].Category]
+	first := monthStart(now).AddDate(0, -predictionTrendMonths, 0)
+	for n := len(series); n > 0 && series[n-1] == 0 && first.AddDate(0, n-1, 0).Before(predicted); n-- {
+		series = series[:n-1]
+	}
+	for len(series) > 0 && series[0] == 0 {
+		series = series[1:]
+	}
+	if len(series) < 3 {
+		return series, 0, mean(series), 0
+	}
+
+	slope, intercept = linearFit(series)
+	index := make([]float64, len(series))
+	for i := range index {
+		index[i] = float64(i)
+	}
+	r := pearson(index, series)
+	return series, slope, intercept, r * r
+}
+
+// applyTrend adjusts a prediction by where its category's monthly spend is
+// heading. With a clear trend, the likelihood scales by next month's projected
+// spend relative to the average, and the predicted date moves out, by up to
+// twice the usual interval, as spending slows. Rising spend is more often
+// bigger bills than more frequent ones, so it never brings the date in.
+func applyTrend(p *types.PredictedSpend, txns []types.Transaction, now time.Time) {
+	series, slope, intercept, fit := spendTrend(txns, now, p.PredictedDate)
+	p.TrendSlope, p.TrendIntercept = slope, intercept
+
+	avg := mean(series)
+	if fit < minTrendFit || avg <= 0 {
+		return
+	}
+	projected := math.Max(intercept+slope*float64(len(series)), 0)
+	ratio := projected / avg
+
+	p.Likelihood = math.Min(p.Likelihood*math.Min(ratio, 1.25), 1)
+
+	last := txns[len(txns)-1].Date
+	stretch := 2.0
+	if ratio > 0 {
+		stretch = math.Max(math.Min(1/ratio, 2), 1)
+	}
+	p.PredictedDate = last.Add(time.Duration(float64(p.PredictedDate.Sub(last)) * stretch))
+}
@@ -0,0 +1,61 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"sort"
+)
+
+// upcomingWeeks is how many weeks ahead PredictSpendingByWeek looks
+const upcomingWeeks = 4
+
+// PredictSpendingByWeek lays the spending predictions out over the next four
+// weeks, starting today, listing the categories expected to charge in each
+// week with their expected amounts. Overdue predictions are still expected,
+// so they fall in the first week; predictions further out are left off.
+// Categories within a week are ordered by predicted date.
+func (s *service) PredictSpendingByWeek(ctx context.Context, accountID string, opts PredictionOptions) ([]types.WeekForecast, error) {
+	predictions, err := s.PredictFutureSpending(ctx, accountID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	today := dayOf(now, now.Location())
+	weeks := make([]types.WeekForecast, upcomingWeeks)
+	for i := range weeks {
+		weeks[i] = types.WeekForecast{
+			Start:      today.AddDate(0, 0, 7*i),
+			End:        today.AddDate(0, 0, 7*(i+1)),
+			Categories: make([]types.WeekCategory, 0),
+		}
+	}
+
+	for _, p := range predictions {
+		week := 0
+		if p.PredictedDate.After(today) {
+			week = int(p.PredictedDate.Sub(today).Hours() / 24 / 7)
+		}
+		if week >= upcomingWeeks {
+			continue
+		}
+		weeks[week].Categories = append(weeks[week].Categories, types.WeekCategory{
+			Category:      p.Category,
+			Amount:        p.PredictedAmount,
+			Likelihood:    p.Likelihood,
+			PredictedDate: p.PredictedDate,
+		})
+		weeks[week].Total += p.PredictedAmount
+	}
+
+	for _, w := range weeks {
+		sort.Slice(w.Categories, func(i, j int) bool {
+			if w.Categories[i].PredictedDate.Equal(w.Categories[j].PredictedDate) {
+				return w.Categories[i].Category < w.Categories[j].Category
+			}
+			return w.Categories[i].PredictedDate.Before(w.Categories[j].PredictedDate)
+		})
+	}
+
+	return weeks, nil
+}
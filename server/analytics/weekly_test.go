@@ -0,0 +1,50 @@
+package analytics
+
+import (
+	"context"
+	"server/types"
+	"testing"
+	"time"
+)
+
+func TestPredictSpendingByWeek(t *testing.T) {
+	repo := &fakeRepo{transactions: []types.Transaction{
+		// Rent on the 1st, next due May 1st: 16 days out, in the third week
+		txn("2025-01-01", -1500, "Rent", "Landlord"),
+		txn("2025-02-01", -1500, "Rent", "Landlord"),
+		txn("2025-03-01", -1500, "Rent", "Landlord"),
+		txn("2025-04-01", -1500, "Rent", "Landlord"),
+	}}
+	svc := NewService(repo, fixedClock("2025-04-15"))
+
+	got, err := svc.PredictSpendingByWeek(context.Background(), "1234567891", PredictionOptions{})
+	if err != nil {
+		t.Fatalf("PredictSpendingByWeek() failed: %v", err)
+	}
+	if len(got) != upcomingWeeks {
+		t.Fatalf("PredictSpendingByWeek() returned %d weeks, want %d", len(got), upcomingWeeks)
+	}
+
+	today := time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC)
+	for i, week := range got {
+		if want := today.AddDate(0, 0, 7*i); !week.Start.Equal(want) || !week.End.Equal(want.AddDate(0, 0, 7)) {
+			t.Errorf("week %d runs %v to %v, want from %v for 7 days", i, week.Start, week.End, want)
+		}
+		if i != 2 {
+			if len(week.Categories) != 0 {
+				t.Errorf("week %d = %+v, want nothing due", i, week.Categories)
+			}
+			continue
+		}
+		if len(week.Categories) != 1 || week.Categories[0].Category != "Rent" {
+			t.Fatalf("week %d = %+v, want rent due", i, week.Categories)
+		}
+		rent := week.Categories[0]
+		if rent.PredictedDate.Before(week.Start) || !rent.PredictedDate.Before(week.End) {
+			t.Errorf("rent predicted for %v, outside its week %v to %v", rent.PredictedDate, week.Start, week.End)
+		}
+		if !approxEqual(rent.Amount, 1500) || !approxEqual(week.Total, 1500) {
+			t.Errorf("rent Amount, week Total = %v, %v, want 1500", rent.Amount, week.Total)
+		}
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"os"
 	"server/types"
 	"strings"
-	"time"
 )
 
 // CreateTables creates all necessary database tables
@@ -50,7 +49,9 @@ func CreateTables(db *sql.DB) error {
 			amount DECIMAL(10, 2),
 			category VARCHAR(50),
 			merchant VARCHAR(50),
-			location VARCHAR(100)
+			location VARCHAR(100),
+			rewards DECIMAL(10, 2) NOT NULL DEFAULT 0,
+			currency VARCHAR(3) NOT NULL DEFAULT ''
 		)`
 	
 	if err := db.QueryRow(createTransactions).Err(); err != nil {
@@ -62,6 +63,12 @@ func CreateTables(db *sql.DB) error {
 
 // InsertJaneData reads JaneDoe.json and inserts the data into the database
 func InsertJaneData(db *sql.DB) error {
+	return InsertJaneDataWithMapper(db, DefaultTransactionMapper)
+}
+
+// InsertJaneDataWithMapper is InsertJaneData with a custom mapper for the
+// transaction records, for source files that don't follow the common schema
+func InsertJaneDataWithMapper(db *sql.DB, mapper TransactionMapper) error {
 	// Read JSON file
 	data, err := os.ReadFile("JaneDoe.json")
 	if err != nil {
@@ -86,18 +93,7 @@ func InsertJaneData(db *sql.DB) error {
 				RoutingNumber string `json:"routing_number"`
 				Branch        string `json:"branch"`
 			} `json:"bank_details"`
-			Transactions []struct {
-				TransactionID string  `json:"transaction_id"`
-				AccountID    int     `json:"account_id"`
-				Date        string  `json:"date"`
-				Amount      float64 `json:"amount"`
-				Category    string  `json:"category"`
-				Merchant    string  `json:"merchant"`
-				Location    string  `json:"location"`
-				Type       string  `json:"type"`
-				Status     string  `json:"status"`
-				Timestamp  string  `json:"timestamp"`
-			} `json:"transactions"`
+			Transactions []map[string]any `json:"transactions"`
 		} `json:"account"`
 	}
 
@@ -143,30 +139,32 @@ func InsertJaneData(db *sql.DB) error {
 	if len(jsonData.Account.Transactions) > 0 {
 		// Create batch insert query
 		valueStrings := make([]string, 0, len(jsonData.Account.Transactions))
-		valueArgs := make([]interface{}, 0, len(jsonData.Account.Transactions)*7)
-		for i, t := range jsonData.Account.Transactions {
-			date, err := time.Parse("2006-01-02", t.Date)
+		valueArgs := make([]interface{}, 0, len(jsonData.Account.Transactions)*9)
+		for i, raw := range jsonData.Account.Transactions {
+			t, err := mapper(raw)
 			if err != nil {
-				return fmt.Errorf("failed to parse date %s: %w", t.Date, err)
+				return fmt.Errorf("failed to map transaction %d: %w", i, err)
 			}
 			
-			valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d)",
-				i*7+1, i*7+2, i*7+3, i*7+4, i*7+5, i*7+6, i*7+7))
+			valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
+				i*9+1, i*9+2, i*9+3, i*9+4, i*9+5, i*9+6, i*9+7, i*9+8, i*9+9))
 			valueArgs = append(valueArgs, 
 				t.TransactionID,
-				fmt.Sprintf("%d", t.AccountID), // Convert int to string
-				date,
+				t.AccountID,
+				t.Date,
 				t.Amount,
 				t.Category,
 				t.Merchant,
-				t.Location)
+				t.Location,
+				t.Rewards,
+				t.Currency)
 		}
 
 		fmt.Printf("Inserting %d transactions\n", len(valueStrings))
 
 		transactionQuery := fmt.Sprintf(`
 			INSERT INTO transactions (
-				transaction_id, account_id, date, amount, category, merchant, location
+				transaction_id, account_id, date, amount, category, merchant, location, rewards, currency
 			) VALUES %s`, strings.Join(valueStrings, ","))
 		
 		_, err = tx.Exec(transactionQuery, valueArgs...)
@@ -222,7 +220,7 @@ func CreateUser(db *sql.DB, user *types.User) error {
 func GetTransactions(db *sql.DB, accountID string) ([]types.Transaction, error) { 
 	// Convert string account ID to integer for comparison
 	query := ` 
-		SELECT transaction_id, account_id, date, amount, category, merchant, location 
+		SELECT transaction_id, account_id, date, amount, category, merchant, location, rewards, currency
 		FROM transactions 
 		WHERE account_id = $1
 		ORDER BY date DESC`
@@ -233,7 +231,7 @@ func GetTransactions(db *sql.DB, accountID string) ([]types.Transaction, error)
 	}
 	defer rows.Close()
 
-	var transactions []types.Transaction
+	transactions := make([]types.Transaction, 0)
 	for rows.Next() {
 		var t types.Transaction
 		var accountIDStr string
@@ -245,6 +243,8 @@ func GetTransactions(db *sql.DB, accountID string) ([]types.Transaction, error)
 			&t.Category,
 			&t.Merchant,
 			&t.Location,
+			&t.Rewards,
+			&t.Currency,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan transaction: %w", err)
 		}
@@ -269,8 +269,8 @@ func InsertTransaction(db *sql.DB, transaction *types.Transaction) error {
 
 	query := `
 		INSERT INTO transactions (
-			transaction_id, account_id, date, amount, category, merchant, location
-		) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+			transaction_id, account_id, date, amount, category, merchant, location, rewards, currency
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
 	
 	_, err = tx.Exec(query,
 		transaction.TransactionID,
@@ -280,6 +280,8 @@ func InsertTransaction(db *sql.DB, transaction *types.Transaction) error {
 		transaction.Category,
 		transaction.Merchant,
 		transaction.Location,
+		transaction.Rewards,
+		transaction.Currency,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert transaction: %w", err)
@@ -0,0 +1,97 @@
+package crud
+
+import (
+	"encoding/json"
+	"fmt"
+	"server/types"
+	"strconv"
+	"time"
+)
+
+// TransactionMapper converts one raw transaction record from a source file
+// into a Transaction, so sources with a different shape can be ingested
+// without changing this package
+type TransactionMapper func(raw map[string]any) (types.Transaction, error)
+
+// DefaultTransactionMapper maps the common schema used by JaneDoe.json:
+// transaction_id, account_id, date (YYYY-MM-DD), amount, category, merchant,
+// location and optional rewards and currency fields
+func DefaultTransactionMapper(raw map[string]any) (types.Transaction, error) {
+	var t types.Transaction
+	var err error
+
+	if t.TransactionID, err = StringField(raw, "transaction_id"); err != nil {
+		return t, err
+	}
+	if t.AccountID, err = StringField(raw, "account_id"); err != nil {
+		return t, err
+	}
+	date, err := StringField(raw, "date")
+	if err != nil {
+		return t, err
+	}
+	if t.Date, err = time.Parse("2006-01-02", date); err != nil {
+		return t, fmt.Errorf("failed to parse date %s: %w", date, err)
+	}
+	if t.Amount, err = FloatField(raw, "amount"); err != nil {
+		return t, err
+	}
+	if t.Category, err = StringField(raw, "category"); err != nil {
+		return t, err
+	}
+	if t.Merchant, err = StringField(raw, "merchant"); err != nil {
+		return t, err
+	}
+	if t.Location, err = StringField(raw, "location"); err != nil {
+		return t, err
+	}
+	if _, ok := raw["rewards"]; ok {
+		if t.Rewards, err = FloatField(raw, "rewards"); err != nil {
+			return t, err
+		}
+	}
+	if _, ok := raw["currency"]; ok {
+		if t.Currency, err = StringField(raw, "currency"); err != nil {
+			return t, err
+		}
+	}
+
+	return t, nil
+}
+
+// StringField reads a field as a string, formatting numbers such as numeric
+// account IDs without a decimal point
+func StringField(raw map[string]any, name string) (string, error) {
+	switch v := raw[name].(type) {
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case json.Number:
+		return v.String(), nil
+	case nil:
+		return "", fmt.Errorf("missing field %s", name)
+	default:
+		return "", fmt.Errorf("field %s has unexpected type %T", name, v)
+	}
+}
+
+// FloatField reads a field as a number, accepting numeric strings
+func FloatField(raw map[string]any, name string) (float64, error) {
+	switch v := raw[name].(type) {
+	case float64:
+		return v, nil
+	case json.Number:
+		return v.Float64()
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse field %s: %w", name, err)
+		}
+		return f, nil
+	case nil:
+		return 0, fmt.Errorf("missing field %s", name)
+	default:
+		return 0, fmt.Errorf("field %s has unexpected type %T", name, v)
+	}
+}
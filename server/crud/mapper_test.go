@@ -0,0 +1,99 @@
+package crud
+
+import (
+	"fmt"
+	"reflect"
+	"server/types"
+	"testing"
+	"time"
+)
+
+func TestDefaultTransactionMapper(t *testing.T) {
+	raw := map[string]any{
+		"transaction_id": "TXN00001",
+		"account_id":     float64(1234567891),
+		"date":           "2025-01-01",
+		"amount":         float64(-2260),
+		"category":       "Rent",
+		"merchant":       "Park Avenue Apartments",
+		"location":       "Manhattan, New York, NY",
+		"type":           "ACH Transfer",
+	}
+
+	got, err := DefaultTransactionMapper(raw)
+	if err != nil {
+		t.Fatalf("DefaultTransactionMapper() failed: %v", err)
+	}
+	want := types.Transaction{
+		TransactionID: "TXN00001",
+		AccountID:     "1234567891",
+		Date:          time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Amount:        -2260,
+		Category:      "Rent",
+		Merchant:      "Park Avenue Apartments",
+		Location:      "Manhattan, New York, NY",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DefaultTransactionMapper() = %+v, want %+v", got, want)
+	}
+
+	delete(raw, "amount")
+	if _, err := DefaultTransactionMapper(raw); err == nil {
+		t.Error("DefaultTransactionMapper() without an amount should fail")
+	}
+}
+
+func TestCustomTransactionMapper(t *testing.T) {
+	// A source that reports debits as positive "value" strings in cents with a
+	// separate direction and a nested merchant
+	var mapper TransactionMapper = func(raw map[string]any) (types.Transaction, error) {
+		id, err := StringField(raw, "id")
+		if err != nil {
+			return types.Transaction{}, err
+		}
+		cents, err := FloatField(raw, "value")
+		if err != nil {
+			return types.Transaction{}, err
+		}
+		if raw["direction"] == "debit" {
+			cents = -cents
+		}
+		posted, err := StringField(raw, "posted_at")
+		if err != nil {
+			return types.Transaction{}, err
+		}
+		date, err := time.Parse(time.RFC3339, posted)
+		if err != nil {
+			return types.Transaction{}, fmt.Errorf("failed to parse posted_at: %w", err)
+		}
+		merchant, _ := raw["merchant"].(map[string]any)
+		name, err := StringField(merchant, "name")
+		if err != nil {
+			return types.Transaction{}, err
+		}
+		return types.Transaction{
+			TransactionID: id,
+			Date:          date,
+			Amount:        cents / 100,
+			Merchant:      name,
+			Category:      "Uncategorized",
+		}, nil
+	}
+
+	got, err := mapper(map[string]any{
+		"id":        "abc-1",
+		"value":     "1299",
+		"direction": "debit",
+		"posted_at": "2025-03-12T18:30:00Z",
+		"merchant":  map[string]any{"name": "Whole Foods"},
+	})
+	if err != nil {
+		t.Fatalf("mapper() failed: %v", err)
+	}
+	if got.TransactionID != "abc-1" || got.Amount != -12.99 || got.Merchant != "Whole Foods" {
+		t.Errorf("mapper() = %+v, want abc-1 at -12.99 from Whole Foods", got)
+	}
+	if want := time.Date(2025, 3, 12, 18, 30, 0, 0, time.UTC); !got.Date.Equal(want) {
+		t.Errorf("Date = %v, want %v", got.Date, want)
+	}
+}
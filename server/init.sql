@@ -31,5 +31,7 @@ CREATE TABLE transactions (
     amount DECIMAL(10, 2),
     category VARCHAR(50),
     merchant VARCHAR(50),
-    location VARCHAR(100)
+    location VARCHAR(100),
+    rewards DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    currency VARCHAR(3) NOT NULL DEFAULT ''
 );
@@ -0,0 +1,6 @@
+-- Bring a database created by an older init.sql up to date without
+-- dropping its data. Safe to run more than once.
+
+-- Add transaction rewards and currency
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS rewards DECIMAL(10, 2) NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT '';
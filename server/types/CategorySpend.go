@@ -1,7 +0,0 @@
-package types
-
-type CategorySpend struct {
-	Category    string  `json:"category"`
-	TotalSpent  string `json:"totalSpent"`
-	Percentage  string `json:"percentage"`
-} 
\ No newline at end of file
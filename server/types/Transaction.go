@@ -10,4 +10,17 @@ type Transaction struct {
 	Category      string    `json:"category"`
 	Merchant      string    `json:"merchant"`
 	Location      string    `json:"location"`
+	Rewards       float64   `json:"rewards,omitempty"`
+	Splits        []Split   `json:"splits,omitempty"`
+	// Currency is the ISO 4217 code the amount is in; empty means the
+	// account's reporting currency
+	Currency string `json:"currency,omitempty"`
+}
+
+// Split assigns part of a transaction to another category, e.g. the household
+// goods in a supermarket shop. Amount is signed like the transaction's; any
+// part not covered by splits stays with the transaction's own category.
+type Split struct {
+	Category string  `json:"category"`
+	Amount   float64 `json:"amount"`
 }
\ No newline at end of file
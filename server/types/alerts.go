@@ -0,0 +1,18 @@
+package types
+
+type AlertLevel string
+
+const (
+	AlertLevelWarning AlertLevel = "warning"
+	AlertLevelBreach  AlertLevel = "breach"
+)
+
+type Alert struct {
+	AccountID string     `json:"accountId"`
+	Category  string     `json:"category"`
+	Level     AlertLevel `json:"level"`
+	Threshold float64    `json:"threshold"`
+	Spent     float64    `json:"spent"`
+	Budget    float64    `json:"budget"`
+	Message   string     `json:"message"`
+}
@@ -3,17 +3,33 @@ package types
 import "time"
 
 type SpendingAnalytics struct {
+	SchemaVersion     int               `json:"schemaVersion"`
 	TopCategories     []CategorySpend   `json:"topCategories"`
 	SpendingPatterns  []TimePattern     `json:"spendingPatterns"`
 	PredictedSpending []PredictedSpend  `json:"predictedSpending"`
 	TotalSpent        float64           `json:"totalSpent"`
 	MonthlyAverage    float64           `json:"monthlyAverage"`
+	Income            *IncomeSummary    `json:"income,omitempty"`
+	DataQuality       *DataQuality      `json:"dataQuality,omitempty"`
+}
+
+type AnalyticsResponse struct {
+	Data        *SpendingAnalytics `json:"data"`
+	Currency    string             `json:"currency"`
+	TimeRange   string             `json:"timeRange"`
+	GeneratedAt time.Time          `json:"generatedAt"`
+	Version     string             `json:"version"`
 }
 
 type CategorySpend struct {
-	Category   string `json:"category"`
-	TotalSpent string `json:"totalSpent"`
-	Percentage string `json:"percentage"`
+	Category   string          `json:"category"`
+	TotalSpent string          `json:"totalSpent"`
+	Percentage string          `json:"percentage"`
+	Amount     float64         `json:"amount"`
+	Share      float64         `json:"share"`
+	Health     *CategoryHealth `json:"health,omitempty"`
+	Sparkline  []float64       `json:"sparkline,omitempty"`
+	Delta      *CategoryDelta  `json:"delta,omitempty"`
 }
 
 type TimePattern struct {
@@ -21,11 +37,41 @@ type TimePattern struct {
 	DayOfWeek    string  `json:"dayOfWeek"`
 	Frequency    int     `json:"frequency"`
 	AverageSpend float64 `json:"averageSpend"`
+	DayOfMonth   int     `json:"dayOfMonth,omitempty"`
 }
 
 type PredictedSpend struct {
-	Category      string    `json:"category"`
-	Likelihood    float64   `json:"likelihood"`
-	PredictedDate time.Time `json:"predictedDate"`
-	Warning       string    `json:"warning,omitempty"`
-} 
\ No newline at end of file
+	Category        string             `json:"category"`
+	Likelihood      float64            `json:"likelihood"`
+	RawLikelihood   float64            `json:"rawLikelihood,omitempty"`
+	PredictedDate   time.Time          `json:"predictedDate"`
+	PredictedAmount float64            `json:"predictedAmount"`
+	AmountLow       float64            `json:"amountLow"`
+	AmountHigh      float64            `json:"amountHigh"`
+	DaysUntil       int                `json:"daysUntil"`
+	Overdue         bool               `json:"overdue,omitempty"`
+	Cadence         Cadence            `json:"cadence"`
+	TrendSlope      float64            `json:"trendSlope"`
+	TrendIntercept  float64            `json:"trendIntercept"`
+	ColdStart       bool               `json:"coldStart,omitempty"`
+	Warning         *PredictionWarning `json:"warning,omitempty"`
+	Basis           *PredictionBasis   `json:"basis,omitempty"`
+}
+
+type PredictionBasis struct {
+	Count        int           `json:"count"`
+	From         time.Time     `json:"from"`
+	To           time.Time     `json:"to"`
+	Transactions []Transaction `json:"transactions"`
+} 
+
+type CalibrationSample struct {
+	Likelihood float64 `json:"likelihood"`
+	Hit        bool    `json:"hit"`
+}
+
+type PredictionTotal struct {
+	Unweighted  float64          `json:"unweighted"`
+	Weighted    float64          `json:"weighted"`
+	Predictions []PredictedSpend `json:"predictions"`
+}
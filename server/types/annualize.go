@@ -0,0 +1,10 @@
+package types
+
+type AnnualizedSpend struct {
+	Annual         float64 `json:"annual"`
+	Observed       float64 `json:"observed"`
+	ObservedMonths float64 `json:"observedMonths"`
+	// Method is "seasonal" when last year's data set the scale, otherwise "linear"
+	Method        string `json:"method"`
+	LowConfidence bool   `json:"lowConfidence"`
+}
@@ -0,0 +1,26 @@
+package types
+
+type AnomalyMethod string
+
+const (
+	AnomalyZScore AnomalyMethod = "zscore"
+	AnomalyIQR    AnomalyMethod = "iqr"
+	AnomalyMAD    AnomalyMethod = "mad"
+)
+
+type AnomalyBaseline struct {
+	Count  int     `json:"count"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stdDev"`
+	Median float64 `json:"median"`
+}
+
+type Anomaly struct {
+	Transaction Transaction     `json:"transaction"`
+	Category    string          `json:"category"`
+	Method      AnomalyMethod   `json:"method"`
+	Score       float64         `json:"score"`
+	Expected    float64         `json:"expected"`
+	Baseline    AnomalyBaseline `json:"baseline"`
+	StdDevs     float64         `json:"stdDevs"`
+}
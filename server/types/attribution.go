@@ -0,0 +1,9 @@
+package types
+
+type CategoryAttribution struct {
+	Category           string             `json:"category"`
+	Total              float64            `json:"total"`
+	Recurring          float64            `json:"recurring"`
+	Discretionary      float64            `json:"discretionary"`
+	RecurringMerchants map[string]float64 `json:"recurringMerchants"`
+}
@@ -0,0 +1,17 @@
+package types
+
+type BudgetState string
+
+const (
+	BudgetUnder BudgetState = "under"
+	BudgetNear  BudgetState = "near"
+	BudgetOver  BudgetState = "over"
+)
+
+type BudgetStatus struct {
+	Category  string      `json:"category"`
+	Spent     float64     `json:"spent"`
+	Budgeted  float64     `json:"budgeted"`
+	Projected float64     `json:"projected"`
+	Status    BudgetState `json:"status"`
+}
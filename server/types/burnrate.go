@@ -0,0 +1,18 @@
+package types
+
+import "time"
+
+// BurnRate is how fast money is going out over the current period, from
+// Start up to but not including End
+type BurnRate struct {
+	Start             time.Time `json:"start"`
+	End               time.Time `json:"end"`
+	DaysElapsed       int       `json:"daysElapsed"`
+	DaysInPeriod      int       `json:"daysInPeriod"`
+	SpentSoFar        float64   `json:"spentSoFar"`
+	AverageDailySpend float64   `json:"averageDailySpend"`
+	// ProjectedTotal is the spend by End if the pace so far keeps up
+	ProjectedTotal float64 `json:"projectedTotal"`
+	// Early is set while too few days have passed for the pace to mean much
+	Early bool `json:"early"`
+}
@@ -0,0 +1,12 @@
+package types
+
+import "time"
+
+// DaySpend is one cell of a calendar heatmap. Cells for days outside the
+// month are left as the zero value.
+type DaySpend struct {
+	Date   time.Time `json:"date"`
+	Day    int       `json:"day"`
+	Amount float64   `json:"amount"`
+	Count  int       `json:"count"`
+}
@@ -0,0 +1,10 @@
+package types
+
+type TenureCohort struct {
+	Label          string  `json:"label"`
+	FromMonth      int     `json:"fromMonth"`
+	ToMonth        int     `json:"toMonth,omitempty"`
+	Months         float64 `json:"months"`
+	Total          float64 `json:"total"`
+	MonthlyAverage float64 `json:"monthlyAverage"`
+}
@@ -0,0 +1,47 @@
+package types
+
+type CategoryDelta struct {
+	Category      string  `json:"category"`
+	Current       float64 `json:"current"`
+	Prior         float64 `json:"prior"`
+	Change        float64 `json:"change"`
+	PercentChange float64 `json:"percentChange"`
+	New           bool    `json:"new,omitempty"`
+	Discontinued  bool    `json:"discontinued,omitempty"`
+	TopIncrease   bool    `json:"topIncrease,omitempty"`
+}
+
+type YoYComparison struct {
+	Period        string          `json:"period"`
+	PriorPeriod   string          `json:"priorPeriod"`
+	Current       float64         `json:"current"`
+	Prior         float64         `json:"prior"`
+	Change        float64         `json:"change"`
+	PercentChange float64         `json:"percentChange"`
+	Categories    []CategoryDelta `json:"categories"`
+}
+
+type SpendingComparison struct {
+	CurrentRange  DateRange       `json:"currentRange"`
+	PriorRange    DateRange       `json:"priorRange"`
+	Current       float64         `json:"current"`
+	Prior         float64         `json:"prior"`
+	Change        float64         `json:"change"`
+	PercentChange float64         `json:"percentChange"`
+	Categories    []CategoryDelta `json:"categories"`
+}
+
+type SeasonalComparison struct {
+	Period                string  `json:"period"`
+	PriorPeriod           string  `json:"priorPeriod"`
+	Current               float64 `json:"current"`
+	Prior                 float64 `json:"prior"`
+	Change                float64 `json:"change"`
+	PercentChange         float64 `json:"percentChange"`
+	CurrentIndex          float64 `json:"currentIndex"`
+	PriorIndex            float64 `json:"priorIndex"`
+	AdjustedCurrent       float64 `json:"adjustedCurrent"`
+	AdjustedPrior         float64 `json:"adjustedPrior"`
+	AdjustedChange        float64 `json:"adjustedChange"`
+	AdjustedPercentChange float64 `json:"adjustedPercentChange"`
+}
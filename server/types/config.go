@@ -0,0 +1,13 @@
+package types
+
+import "time"
+
+type AccountConfig struct {
+	AccountID           string             `json:"accountId"`
+	Budgets             map[string]float64 `json:"budgets"`
+	EssentialCategories []string           `json:"essentialCategories"`
+	Timezone            string             `json:"timezone"`
+	WeekStart           time.Weekday       `json:"weekStart"`
+	Currency            string             `json:"currency"`
+	SavingsTarget       float64            `json:"savingsTarget"`
+}
@@ -0,0 +1,30 @@
+package types
+
+import (
+	"strconv"
+	"strings"
+)
+
+// currencyDecimals lists ISO 4217 currencies whose minor unit isn't cents.
+// Anything not listed uses two decimal places.
+var currencyDecimals = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0,
+	"KRW": 0, "PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0,
+	"XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// CurrencyPrecision returns the number of fractional digits amounts in the
+// given currency are reported with
+func CurrencyPrecision(currency string) int {
+	if decimals, ok := currencyDecimals[strings.ToUpper(currency)]; ok {
+		return decimals
+	}
+	return 2
+}
+
+// FormatAmount formats an amount with the fractional digits of its currency,
+// e.g. 1234.5 is "1234.50" in USD and "1235" in JPY
+func FormatAmount(amount float64, currency string) string {
+	return strconv.FormatFloat(amount, 'f', CurrencyPrecision(currency), 64)
+}
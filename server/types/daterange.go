@@ -0,0 +1,17 @@
+package types
+
+import "time"
+
+// DateRange is an inclusive span of time transactions are read for
+type DateRange struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// Relative is the relative range, such as "1 month", the bounds were
+	// resolved from, or empty for explicit bounds
+	Relative string `json:"-"`
+}
+
+// Contains reports whether t falls within the range
+func (r DateRange) Contains(t time.Time) bool {
+	return !t.Before(r.Start) && !t.After(r.End)
+}
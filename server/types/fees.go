@@ -0,0 +1,24 @@
+package types
+
+type FeeSummary struct {
+	Category     string             `json:"category"`
+	Total        float64            `json:"total"`
+	Count        int                `json:"count"`
+	ByCategory   map[string]float64 `json:"byCategory"`
+	Transactions []Transaction      `json:"transactions"`
+}
+
+type ForeignFee struct {
+	Fee Transaction `json:"fee"`
+	// Purchase is the cross-border purchase the fee was charged on, when found
+	Purchase *Transaction `json:"purchase,omitempty"`
+	// Rate is the fee as a fraction of the purchase amount
+	Rate float64 `json:"rate,omitempty"`
+}
+
+type ForeignFeeSummary struct {
+	Total     float64      `json:"total"`
+	Count     int          `json:"count"`
+	Unmatched int          `json:"unmatched"`
+	Fees      []ForeignFee `json:"fees"`
+}
@@ -0,0 +1,9 @@
+package types
+
+type FixedVariableSplit struct {
+	Fixed    float64 `json:"fixed"`
+	Variable float64 `json:"variable"`
+	Total    float64 `json:"total"`
+	// FixedRatio is the share of Total that went on recurring charges, 0 to 1
+	FixedRatio float64 `json:"fixedRatio"`
+}
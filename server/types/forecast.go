@@ -0,0 +1,23 @@
+package types
+
+import "time"
+
+type MonthForecast struct {
+	Year             int        `json:"year"`
+	Month            time.Month `json:"month"`
+	AsOf             time.Time  `json:"asOf"`
+	DaysElapsed      int        `json:"daysElapsed"`
+	DaysInMonth      int        `json:"daysInMonth"`
+	SpentToDate      float64    `json:"spentToDate"`
+	LinearProjection float64    `json:"linearProjection"`
+	Projected        float64    `json:"projected"`
+	Planned          float64    `json:"planned"`
+	HistoricalMonths int        `json:"historicalMonths"`
+}
+
+type PlannedExpense struct {
+	Description string    `json:"description"`
+	Category    string    `json:"category"`
+	Amount      float64   `json:"amount"`
+	Date        time.Time `json:"date"`
+}
@@ -0,0 +1,15 @@
+package types
+
+type FraudSignal string
+
+const (
+	FraudSignalUnusualAmount FraudSignal = "unusual_amount"
+	FraudSignalNewMerchant   FraudSignal = "new_merchant"
+	FraudSignalUnusualTime   FraudSignal = "unusual_time"
+)
+
+type FraudScore struct {
+	Transaction Transaction   `json:"transaction"`
+	Score       float64       `json:"score"`
+	Signals     []FraudSignal `json:"signals"`
+}
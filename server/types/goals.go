@@ -0,0 +1,42 @@
+package types
+
+import "time"
+
+// SavingsGoal is an amount to have saved by a deadline, e.g. $5000 by
+// December
+type SavingsGoal struct {
+	Name   string  `json:"name,omitempty"`
+	Target float64 `json:"target"`
+	// Start is when saving towards the goal began; zero means the start of
+	// the deadline's year
+	Start    time.Time `json:"start,omitempty"`
+	Deadline time.Time `json:"deadline"`
+}
+
+type GoalStatus string
+
+const (
+	GoalOnTrack   GoalStatus = "on_track"
+	GoalBehind    GoalStatus = "behind"
+	GoalCompleted GoalStatus = "completed"
+	GoalMissed    GoalStatus = "missed"
+)
+
+type GoalProgress struct {
+	Goal SavingsGoal `json:"goal"`
+	// Saved is the net cash flow since the goal started
+	Saved           float64 `json:"saved"`
+	Remaining       float64 `json:"remaining"`
+	MonthsRemaining float64 `json:"monthsRemaining"`
+	// MonthlyPace is the average saved per month so far, and RequiredMonthly
+	// what must be saved each month from now on to reach the target
+	MonthlyPace     float64 `json:"monthlyPace"`
+	RequiredMonthly float64 `json:"requiredMonthly"`
+	// Projected is what will have been saved by the deadline at the current
+	// pace, and Surplus how far that is over the target; negative is a
+	// shortfall
+	Projected float64    `json:"projected"`
+	Surplus   float64    `json:"surplus"`
+	OnTrack   bool       `json:"onTrack"`
+	Status    GoalStatus `json:"status"`
+}
@@ -0,0 +1,17 @@
+package types
+
+type HealthStatus string
+
+const (
+	HealthGreen  HealthStatus = "green"
+	HealthYellow HealthStatus = "yellow"
+	HealthRed    HealthStatus = "red"
+)
+
+type CategoryHealth struct {
+	Status      HealthStatus `json:"status"`
+	Trend       float64      `json:"trend"`
+	Volatility  float64      `json:"volatility"`
+	BudgetUsage float64      `json:"budgetUsage,omitempty"`
+	Reasons     []string     `json:"reasons"`
+}
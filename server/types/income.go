@@ -0,0 +1,16 @@
+package types
+
+type IncomeSummary struct {
+	Total      float64            `json:"total"`
+	ByCategory map[string]float64 `json:"byCategory"`
+	// Net is income less spending over the same range
+	Net float64 `json:"net"`
+}
+
+type CashFlow struct {
+	Income   float64 `json:"income"`
+	Expenses float64 `json:"expenses"`
+	Net      float64 `json:"net"`
+	// SavingsRate is Net as a share of Income, zero when there was no income
+	SavingsRate float64 `json:"savingsRate"`
+}
@@ -0,0 +1,12 @@
+package types
+
+type IncomeSourceSpend struct {
+	Source            string  `json:"source"`
+	Deposits          int     `json:"deposits"`
+	AverageDeposit    float64 `json:"averageDeposit"`
+	AverageSpendAfter float64 `json:"averageSpendAfter"`
+	BaselineSpend     float64 `json:"baselineSpend"`
+	Lift              float64 `json:"lift"`
+	Correlation       float64 `json:"correlation"`
+	FuelsSpending     bool    `json:"fuelsSpending"`
+}
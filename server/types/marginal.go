@@ -0,0 +1,11 @@
+package types
+
+type MarginalSpend struct {
+	Category        string  `json:"category"`
+	PerTransaction  float64 `json:"perTransaction"`
+	WeeklyFrequency float64 `json:"weeklyFrequency"`
+	HabitMonthly    float64 `json:"habitMonthly"`
+	HabitAnnual     float64 `json:"habitAnnual"`
+	MarginalMonthly float64 `json:"marginalMonthly"`
+	MarginalAnnual  float64 `json:"marginalAnnual"`
+}
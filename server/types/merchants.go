@@ -0,0 +1,30 @@
+package types
+
+import "time"
+
+type MerchantTrendStatus string
+
+const (
+	MerchantRising    MerchantTrendStatus = "rising"
+	MerchantSteady    MerchantTrendStatus = "steady"
+	MerchantDeclining MerchantTrendStatus = "declining"
+	MerchantChurned   MerchantTrendStatus = "churned"
+)
+
+type MerchantTrend struct {
+	Merchant string `json:"merchant"`
+	// MonthlySpend and MonthlyVisits run oldest month first
+	MonthlySpend  []float64 `json:"monthlySpend"`
+	MonthlyVisits []int     `json:"monthlyVisits"`
+	// VisitSlope is the change in visits per month
+	VisitSlope float64             `json:"visitSlope"`
+	Status     MerchantTrendStatus `json:"status"`
+	LastVisit  time.Time           `json:"lastVisit"`
+}
+
+type MerchantSpend struct {
+	Merchant     string  `json:"merchant"`
+	TotalSpent   float64 `json:"totalSpent"`
+	Count        int     `json:"count"`
+	AverageSpend float64 `json:"averageSpend"`
+}
@@ -0,0 +1,17 @@
+package types
+
+type MomentumDirection string
+
+const (
+	MomentumUp   MomentumDirection = "up"
+	MomentumDown MomentumDirection = "down"
+	MomentumFlat MomentumDirection = "flat"
+)
+
+type CategoryMomentum struct {
+	Category  string            `json:"category"`
+	Current   float64           `json:"current"`
+	Average   float64           `json:"average"`
+	Ratio     float64           `json:"ratio"`
+	Direction MomentumDirection `json:"direction"`
+}
@@ -0,0 +1,8 @@
+package types
+
+type NetSpend struct {
+	Gross      float64            `json:"gross"`
+	Rewards    float64            `json:"rewards"`
+	Net        float64            `json:"net"`
+	ByCategory map[string]float64 `json:"byCategory"`
+}
@@ -0,0 +1,7 @@
+package types
+
+type SubscriptionOverlap struct {
+	ServiceType   string            `json:"serviceType"`
+	Subscriptions []RecurringCharge `json:"subscriptions"`
+	MonthlyTotal  float64           `json:"monthlyTotal"`
+}
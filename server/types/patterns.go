@@ -0,0 +1,32 @@
+package types
+
+// PatternGranularity is the bucket AnalyzeTimePatterns groups spending into
+type PatternGranularity string
+
+const (
+	// PatternHourly buckets by day of week and hour, e.g. Monday 15:00
+	PatternHourly PatternGranularity = "hourly"
+	// PatternDayPart buckets by day of week and morning/afternoon/evening/night
+	PatternDayPart PatternGranularity = "daypart"
+	// PatternQuarterHour buckets by day of week and 15-minute window, e.g. Monday 15:45
+	PatternQuarterHour PatternGranularity = "quarterhour"
+	// PatternDayOfMonth buckets by calendar day, for bills and payday habits
+	PatternDayOfMonth PatternGranularity = "dayofmonth"
+)
+
+// DayPartSummary rolls time patterns up into the parts of the day and the
+// days of the week
+type DayPartSummary struct {
+	// DayParts runs morning, afternoon, evening, night
+	DayParts []DayPartSpend `json:"dayParts"`
+	// TopDayPart and TopDay are where the most was spent; empty when nothing was
+	TopDayPart  string  `json:"topDayPart"`
+	TopDay      string  `json:"topDay"`
+	TopDaySpend float64 `json:"topDaySpend"`
+}
+
+type DayPartSpend struct {
+	DayPart string  `json:"dayPart"`
+	Total   float64 `json:"total"`
+	Count   int     `json:"count"`
+}
@@ -0,0 +1,8 @@
+package types
+
+type P2PStatus struct {
+	PaycheckToPaycheck  bool    `json:"paycheckToPaycheck"`
+	AveragePrePaydayLow float64 `json:"averagePrePaydayLow"`
+	PayPeriods          int     `json:"payPeriods"`
+	NearZeroPeriods     int     `json:"nearZeroPeriods"`
+}
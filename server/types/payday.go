@@ -0,0 +1,14 @@
+package types
+
+import "time"
+
+type PaydaySpike struct {
+	Weekday        time.Weekday `json:"weekday"`
+	Occurrence     int          `json:"occurrence"`
+	AverageSpend   float64      `json:"averageSpend"`
+	BaselineSpend  float64      `json:"baselineSpend"`
+	Magnitude      float64      `json:"magnitude"`
+	Periods        int          `json:"periods"`
+	SpikingPeriods int          `json:"spikingPeriods"`
+	Description    string       `json:"description"`
+}
@@ -0,0 +1,21 @@
+package types
+
+import "time"
+
+type PortfolioCategoryForecast struct {
+	Category string `json:"category"`
+	// Amount is the sum of each account's predicted next spend in the category
+	Amount float64 `json:"amount"`
+	// Expected weights each account's amount by its prediction's likelihood
+	Expected      float64   `json:"expected"`
+	PredictedDate time.Time `json:"predictedDate"`
+	AccountIDs    []string  `json:"accountIds"`
+}
+
+type PortfolioForecast struct {
+	AccountIDs        []string                    `json:"accountIds"`
+	Categories        []PortfolioCategoryForecast `json:"categories"`
+	Total             float64                     `json:"total"`
+	Expected          float64                     `json:"expected"`
+	TransfersExcluded int                         `json:"transfersExcluded"`
+}
@@ -0,0 +1,22 @@
+package types
+
+import "time"
+
+// DataConfidence is how far an analysis can be trusted given the data behind it
+type DataConfidence string
+
+const (
+	DataLow    DataConfidence = "low"
+	DataMedium DataConfidence = "medium"
+	DataHigh   DataConfidence = "high"
+)
+
+// DataQuality describes the transactions an analysis was built from, so
+// results resting on thin data can be caveated
+type DataQuality struct {
+	Transactions int            `json:"transactions"`
+	From         *time.Time     `json:"from,omitempty"`
+	To           *time.Time     `json:"to,omitempty"`
+	DaysCovered  int            `json:"daysCovered"`
+	Confidence   DataConfidence `json:"confidence"`
+}
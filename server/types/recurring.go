@@ -0,0 +1,27 @@
+package types
+
+import "time"
+
+type Cadence string
+
+const (
+	CadenceDaily     Cadence = "daily"
+	CadenceWeekly    Cadence = "weekly"
+	CadenceBiweekly  Cadence = "biweekly"
+	CadenceMonthly   Cadence = "monthly"
+	CadenceQuarterly Cadence = "quarterly"
+	CadenceYearly    Cadence = "yearly"
+	CadenceIrregular Cadence = "irregular"
+)
+
+type RecurringCharge struct {
+	Merchant      string    `json:"merchant"`
+	Category      string    `json:"category"`
+	Cadence       Cadence   `json:"cadence"`
+	AverageAmount float64   `json:"averageAmount"`
+	MonthlyAmount float64   `json:"monthlyAmount"`
+	Occurrences   int       `json:"occurrences"`
+	LastDate      time.Time `json:"lastDate"`
+	NextExpected  time.Time `json:"nextExpected"`
+	Confidence    float64   `json:"confidence"`
+}
@@ -0,0 +1,8 @@
+package types
+
+type RefundSummary struct {
+	Total        float64            `json:"total"`
+	Count        int                `json:"count"`
+	ByCategory   map[string]float64 `json:"byCategory"`
+	Transactions []Transaction      `json:"transactions"`
+}
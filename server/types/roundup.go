@@ -0,0 +1,8 @@
+package types
+
+type RoundUpSavings struct {
+	Total     float64 `json:"total"`
+	Purchases int     `json:"purchases"`
+	// ByMonth holds the round-ups saved per month, keyed "2006-01"
+	ByMonth map[string]float64 `json:"byMonth"`
+}
@@ -0,0 +1,19 @@
+package types
+
+type PeriodKind string
+
+const (
+	PeriodMonth     PeriodKind = "month"
+	PeriodPayPeriod PeriodKind = "pay_period"
+)
+
+type PeriodSpike struct {
+	Period           PeriodKind `json:"period"`
+	WindowDays       int        `json:"windowDays"`
+	Periods          int        `json:"periods"`
+	SpikingPeriods   int        `json:"spikingPeriods"`
+	EndOfPeriodDaily float64    `json:"endOfPeriodDaily"`
+	OverallDaily     float64    `json:"overallDaily"`
+	Magnitude        float64    `json:"magnitude"`
+	Detected         bool       `json:"detected"`
+}
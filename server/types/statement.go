@@ -0,0 +1,28 @@
+package types
+
+import "time"
+
+type StatementHeader struct {
+	AccountID   string    `json:"accountId"`
+	Period      string    `json:"period"`
+	PeriodStart time.Time `json:"periodStart"`
+	PeriodEnd   time.Time `json:"periodEnd"`
+	Currency    string    `json:"currency"`
+	GeneratedAt time.Time `json:"generatedAt"`
+}
+
+type StatementRow struct {
+	Category   string  `json:"category"`
+	Amount     float64 `json:"amount"`
+	Percentage float64 `json:"percentage"`
+	Count      int     `json:"count"`
+}
+
+type Statement struct {
+	Header           StatementHeader   `json:"header"`
+	Categories       []StatementRow    `json:"categories"`
+	TotalSpent       float64           `json:"totalSpent"`
+	TotalIncome      float64           `json:"totalIncome"`
+	Insights         []string          `json:"insights"`
+	RecurringCharges []RecurringCharge `json:"recurringCharges"`
+}
@@ -0,0 +1,14 @@
+package types
+
+import "time"
+
+// TimeBucket is one point of a spend time series, covering the day, week or
+// month that begins at Start
+type TimeBucket struct {
+	// Period labels the bucket, e.g. "2025-04-07" for a day or a week and
+	// "2025-04" for a month
+	Period string    `json:"period"`
+	Start  time.Time `json:"start"`
+	Total  float64   `json:"total"`
+	Count  int       `json:"count"`
+}
@@ -0,0 +1,9 @@
+package types
+
+type CategorySubstitution struct {
+	Rising       string  `json:"rising"`
+	Falling      string  `json:"falling"`
+	Correlation  float64 `json:"correlation"`
+	RisingSlope  float64 `json:"risingSlope"`
+	FallingSlope float64 `json:"fallingSlope"`
+}
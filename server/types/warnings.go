@@ -0,0 +1,48 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WarningSeverity is how strongly a prediction warning should be shown
+type WarningSeverity string
+
+const (
+	// WarningHigh marks a spend that is more likely than not to happen
+	WarningHigh WarningSeverity = "high"
+)
+
+// PredictionWarning flags a likely upcoming spend. It carries the facts
+// behind the warning so clients can word and style it themselves; String
+// gives the English text.
+type PredictionWarning struct {
+	Severity      WarningSeverity `json:"severity"`
+	Category      string          `json:"category"`
+	PredictedDate time.Time       `json:"predictedDate"`
+	Likelihood    float64         `json:"likelihood"`
+	Amount        float64         `json:"amount"`
+	AmountLow     float64         `json:"amountLow"`
+	AmountHigh    float64         `json:"amountHigh"`
+	// Currency is the code the amounts are in. Empty means US dollars, as
+	// warnings were before they carried one.
+	Currency string `json:"currency,omitempty"`
+}
+
+// String describes the warning in English, e.g. "High likelihood (90%) of
+// spending $2260.00 ($2200.00-$2320.00) in Rent category around Apr 01".
+// Amounts in other currencies are written with their code, e.g. "1235 JPY".
+func (w PredictionWarning) String() string {
+	return fmt.Sprintf("High likelihood (%.0f%%) of spending %s (%s-%s) in %s category around %s",
+		w.Likelihood*100, w.formatAmount(w.Amount), w.formatAmount(w.AmountLow), w.formatAmount(w.AmountHigh),
+		w.Category, w.PredictedDate.Format("Jan 02"))
+}
+
+// formatAmount writes amount in the warning's currency
+func (w PredictionWarning) formatAmount(amount float64) string {
+	if w.Currency == "" || strings.EqualFold(w.Currency, "USD") {
+		return "$" + FormatAmount(amount, "USD")
+	}
+	return FormatAmount(amount, w.Currency) + " " + strings.ToUpper(w.Currency)
+}
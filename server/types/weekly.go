@@ -0,0 +1,19 @@
+package types
+
+import "time"
+
+// WeekForecast is one week of the upcoming-spend calendar, from Start up to
+// but not including End
+type WeekForecast struct {
+	Start      time.Time      `json:"start"`
+	End        time.Time      `json:"end"`
+	Categories []WeekCategory `json:"categories"`
+	Total      float64        `json:"total"`
+}
+
+type WeekCategory struct {
+	Category      string    `json:"category"`
+	Amount        float64   `json:"amount"`
+	Likelihood    float64   `json:"likelihood"`
+	PredictedDate time.Time `json:"predictedDate"`
+}